@@ -15,3 +15,14 @@ limitations under the License.
 */
 
 package globalv1
+
+// IsReady returns true if the endpoint is ready (no conditions means ready).
+func (c *EndpointConditions) IsReady() bool {
+	return c == nil || c.Ready
+}
+
+// IsServingTerminating returns true if the endpoint is terminating but still serving.
+// Such endpoints are used as a fallback when no ready endpoint is available.
+func (c *EndpointConditions) IsServingTerminating() bool {
+	return c != nil && c.Serving && c.Terminating
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package globalv1
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"sigs.k8s.io/kpng/api/localv1"
+)
+
+// ServiceFor returns the service as seen from the cluster, with the IPs of the clusters exporting
+// it and the cluster's own IPs (see LocalIPs), or nil if the service is not available in the
+// cluster.
+func (si *ServiceInfo) ServiceFor(cluster string) *localv1.Service {
+	ips, local := si.LocalIPs[cluster]
+
+	switch {
+	case local:
+		svc := proto.Clone(si.Service).(*localv1.Service)
+		if svc.IPs == nil {
+			svc.IPs = &localv1.ServiceIPs{}
+		}
+		svc.IPs.Add(ips)
+		return svc
+
+	case len(si.ExportedBy) != 0 || len(si.LocalIPs) == 0:
+		// exported, or not merged from several clusters
+		return si.Service
+
+	default:
+		// only defined in other clusters, not exporting it
+		return nil
+	}
+}
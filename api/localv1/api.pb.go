@@ -34,12 +34,63 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Compression int32
+
+const (
+	Compression_Uncompressed Compression = 0
+	Compression_Gzip         Compression = 1
+	Compression_Zstd         Compression = 2
+)
+
+// Enum value maps for Compression.
+var (
+	Compression_name = map[int32]string{
+		0: "Uncompressed",
+		1: "Gzip",
+		2: "Zstd",
+	}
+	Compression_value = map[string]int32{
+		"Uncompressed": 0,
+		"Gzip":         1,
+		"Zstd":         2,
+	}
+)
+
+func (x Compression) Enum() *Compression {
+	p := new(Compression)
+	*p = x
+	return p
+}
+
+func (x Compression) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Compression) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_localv1_api_proto_enumTypes[0].Descriptor()
+}
+
+func (Compression) Type() protoreflect.EnumType {
+	return &file_api_localv1_api_proto_enumTypes[0]
+}
+
+func (x Compression) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Compression.Descriptor instead.
+func (Compression) EnumDescriptor() ([]byte, []int) {
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{0}
+}
+
 type Set int32
 
 const (
 	Set_UnknownSet   Set = 0
 	Set_ServicesSet  Set = 1
 	Set_EndpointsSet Set = 2
+	// NodesSet contains the node the watch was requested for.
+	Set_NodesSet Set = 3
 	// FIXME move to a 3rd generic proto ???
 	Set_GlobalServiceInfos  Set = 10
 	Set_GlobalEndpointInfos Set = 11
@@ -52,6 +103,7 @@ var (
 		0:  "UnknownSet",
 		1:  "ServicesSet",
 		2:  "EndpointsSet",
+		3:  "NodesSet",
 		10: "GlobalServiceInfos",
 		11: "GlobalEndpointInfos",
 		12: "GlobalNodeInfos",
@@ -60,6 +112,7 @@ var (
 		"UnknownSet":          0,
 		"ServicesSet":         1,
 		"EndpointsSet":        2,
+		"NodesSet":            3,
 		"GlobalServiceInfos":  10,
 		"GlobalEndpointInfos": 11,
 		"GlobalNodeInfos":     12,
@@ -77,11 +130,11 @@ func (x Set) String() string {
 }
 
 func (Set) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_localv1_api_proto_enumTypes[0].Descriptor()
+	return file_api_localv1_api_proto_enumTypes[1].Descriptor()
 }
 
 func (Set) Type() protoreflect.EnumType {
-	return &file_api_localv1_api_proto_enumTypes[0]
+	return &file_api_localv1_api_proto_enumTypes[1]
 }
 
 func (x Set) Number() protoreflect.EnumNumber {
@@ -90,7 +143,57 @@ func (x Set) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Set.Descriptor instead.
 func (Set) EnumDescriptor() ([]byte, []int) {
-	return file_api_localv1_api_proto_rawDescGZIP(), []int{0}
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{1}
+}
+
+type LoadBalancerIPMode int32
+
+const (
+	// VIP means the traffic is delivered to the node with the IP as destination,
+	// so the node should capture it.
+	LoadBalancerIPMode_VIP LoadBalancerIPMode = 0
+	// Proxy means the traffic is delivered to the node with the IP rewritten by the
+	// load-balancer, so the node must let the traffic to the IP go to the load-balancer.
+	LoadBalancerIPMode_Proxy LoadBalancerIPMode = 1
+)
+
+// Enum value maps for LoadBalancerIPMode.
+var (
+	LoadBalancerIPMode_name = map[int32]string{
+		0: "VIP",
+		1: "Proxy",
+	}
+	LoadBalancerIPMode_value = map[string]int32{
+		"VIP":   0,
+		"Proxy": 1,
+	}
+)
+
+func (x LoadBalancerIPMode) Enum() *LoadBalancerIPMode {
+	p := new(LoadBalancerIPMode)
+	*p = x
+	return p
+}
+
+func (x LoadBalancerIPMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (LoadBalancerIPMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_localv1_api_proto_enumTypes[2].Descriptor()
+}
+
+func (LoadBalancerIPMode) Type() protoreflect.EnumType {
+	return &file_api_localv1_api_proto_enumTypes[2]
+}
+
+func (x LoadBalancerIPMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use LoadBalancerIPMode.Descriptor instead.
+func (LoadBalancerIPMode) EnumDescriptor() ([]byte, []int) {
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{2}
 }
 
 type Protocol int32
@@ -129,11 +232,11 @@ func (x Protocol) String() string {
 }
 
 func (Protocol) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_localv1_api_proto_enumTypes[1].Descriptor()
+	return file_api_localv1_api_proto_enumTypes[3].Descriptor()
 }
 
 func (Protocol) Type() protoreflect.EnumType {
-	return &file_api_localv1_api_proto_enumTypes[1]
+	return &file_api_localv1_api_proto_enumTypes[3]
 }
 
 func (x Protocol) Number() protoreflect.EnumNumber {
@@ -142,7 +245,7 @@ func (x Protocol) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Protocol.Descriptor instead.
 func (Protocol) EnumDescriptor() ([]byte, []int) {
-	return file_api_localv1_api_proto_rawDescGZIP(), []int{1}
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{3}
 }
 
 // To request ENLS(Expected Node Local State) a client must specify the desired NodeName
@@ -154,6 +257,20 @@ type WatchReq struct {
 
 	// NodeName of the requester
 	NodeName string `protobuf:"bytes,1,opt,name=NodeName,proto3" json:"NodeName,omitempty"`
+	// StateDigest is the digest of the state the requester already has (see
+	// client/statedigest). When not 0 on the first request of a watch, the
+	// server may resume from this state instead of sending a Reset and the
+	// whole state again.
+	StateDigest uint64 `protobuf:"varint,2,opt,name=StateDigest,proto3" json:"StateDigest,omitempty"`
+	// Filter, if set, restricts the watched services (and their endpoints). It
+	// can change between requests of a watch.
+	Filter *WatchFilter `protobuf:"bytes,3,opt,name=Filter,proto3" json:"Filter,omitempty"`
+	// Applied is the status of the last state the requester applied, if any.
+	Applied *ApplyStatus `protobuf:"bytes,4,opt,name=Applied,proto3" json:"Applied,omitempty"`
+	// Batching, if set on the first request of a watch, asks the server to
+	// pack the Set and Delete ops in Batch ops. Servers not supporting it send
+	// the ops one by one, so the requester must accept both.
+	Batching *BatchingReq `protobuf:"bytes,5,opt,name=Batching,proto3" json:"Batching,omitempty"`
 }
 
 func (x *WatchReq) Reset() {
@@ -195,6 +312,227 @@ func (x *WatchReq) GetNodeName() string {
 	return ""
 }
 
+func (x *WatchReq) GetStateDigest() uint64 {
+	if x != nil {
+		return x.StateDigest
+	}
+	return 0
+}
+
+func (x *WatchReq) GetFilter() *WatchFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *WatchReq) GetApplied() *ApplyStatus {
+	if x != nil {
+		return x.Applied
+	}
+	return nil
+}
+
+func (x *WatchReq) GetBatching() *BatchingReq {
+	if x != nil {
+		return x.Batching
+	}
+	return nil
+}
+
+type BatchingReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// MaxBytes is the size budget of a batch, before compression (0 for the
+	// server's default).
+	MaxBytes uint32 `protobuf:"varint,1,opt,name=MaxBytes,proto3" json:"MaxBytes,omitempty"`
+	// Compressions supported by the requester, by order of preference. The
+	// server uses the first one it supports, or no compression.
+	Compressions []Compression `protobuf:"varint,2,rep,packed,name=Compressions,proto3,enum=localv1.Compression" json:"Compressions,omitempty"`
+}
+
+func (x *BatchingReq) Reset() {
+	*x = BatchingReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_localv1_api_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchingReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchingReq) ProtoMessage() {}
+
+func (x *BatchingReq) ProtoReflect() protoreflect.Message {
+	mi := &file_api_localv1_api_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchingReq.ProtoReflect.Descriptor instead.
+func (*BatchingReq) Descriptor() ([]byte, []int) {
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BatchingReq) GetMaxBytes() uint32 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
+func (x *BatchingReq) GetCompressions() []Compression {
+	if x != nil {
+		return x.Compressions
+	}
+	return nil
+}
+
+// ApplyStatus reports how a state was applied by a node.
+type ApplyStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Rev is the revision of the applied state (see SyncOp)
+	Rev uint64 `protobuf:"varint,1,opt,name=Rev,proto3" json:"Rev,omitempty"`
+	// Error is the apply error, if it failed
+	Error string `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	// DurationNanos is how long the apply took, in nanoseconds
+	DurationNanos int64 `protobuf:"varint,3,opt,name=DurationNanos,proto3" json:"DurationNanos,omitempty"`
+}
+
+func (x *ApplyStatus) Reset() {
+	*x = ApplyStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_localv1_api_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ApplyStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyStatus) ProtoMessage() {}
+
+func (x *ApplyStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_api_localv1_api_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyStatus.ProtoReflect.Descriptor instead.
+func (*ApplyStatus) Descriptor() ([]byte, []int) {
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ApplyStatus) GetRev() uint64 {
+	if x != nil {
+		return x.Rev
+	}
+	return 0
+}
+
+func (x *ApplyStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ApplyStatus) GetDurationNanos() int64 {
+	if x != nil {
+		return x.DurationNanos
+	}
+	return 0
+}
+
+// WatchFilter selects services. Empty fields match all services.
+type WatchFilter struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Namespaces of the services
+	Namespaces []string `protobuf:"bytes,1,rep,name=Namespaces,proto3" json:"Namespaces,omitempty"`
+	// LabelSelector on the services' labels (ie: "app=web,tier!=db")
+	LabelSelector string `protobuf:"bytes,2,opt,name=LabelSelector,proto3" json:"LabelSelector,omitempty"`
+	// ServiceTypes of the services (ie: "LoadBalancer")
+	ServiceTypes []string `protobuf:"bytes,3,rep,name=ServiceTypes,proto3" json:"ServiceTypes,omitempty"`
+}
+
+func (x *WatchFilter) Reset() {
+	*x = WatchFilter{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_localv1_api_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchFilter) ProtoMessage() {}
+
+func (x *WatchFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_api_localv1_api_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchFilter.ProtoReflect.Descriptor instead.
+func (*WatchFilter) Descriptor() ([]byte, []int) {
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WatchFilter) GetNamespaces() []string {
+	if x != nil {
+		return x.Namespaces
+	}
+	return nil
+}
+
+func (x *WatchFilter) GetLabelSelector() string {
+	if x != nil {
+		return x.LabelSelector
+	}
+	return ""
+}
+
+func (x *WatchFilter) GetServiceTypes() []string {
+	if x != nil {
+		return x.ServiceTypes
+	}
+	return nil
+}
+
 type OpItem struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -206,13 +544,14 @@ type OpItem struct {
 	//	*OpItem_Reset_
 	//	*OpItem_Set
 	//	*OpItem_Delete
+	//	*OpItem_Batch
 	Op isOpItem_Op `protobuf_oneof:"Op"`
 }
 
 func (x *OpItem) Reset() {
 	*x = OpItem{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_localv1_api_proto_msgTypes[1]
+		mi := &file_api_localv1_api_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -225,7 +564,7 @@ func (x *OpItem) String() string {
 func (*OpItem) ProtoMessage() {}
 
 func (x *OpItem) ProtoReflect() protoreflect.Message {
-	mi := &file_api_localv1_api_proto_msgTypes[1]
+	mi := &file_api_localv1_api_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -238,7 +577,7 @@ func (x *OpItem) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OpItem.ProtoReflect.Descriptor instead.
 func (*OpItem) Descriptor() ([]byte, []int) {
-	return file_api_localv1_api_proto_rawDescGZIP(), []int{1}
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{4}
 }
 
 func (m *OpItem) GetOp() isOpItem_Op {
@@ -248,7 +587,7 @@ func (m *OpItem) GetOp() isOpItem_Op {
 	return nil
 }
 
-func (x *OpItem) GetSync() *EmptyOp {
+func (x *OpItem) GetSync() *SyncOp {
 	if x, ok := x.GetOp().(*OpItem_Sync); ok {
 		return x.Sync
 	}
@@ -276,13 +615,20 @@ func (x *OpItem) GetDelete() *Ref {
 	return nil
 }
 
+func (x *OpItem) GetBatch() *Batch {
+	if x, ok := x.GetOp().(*OpItem_Batch); ok {
+		return x.Batch
+	}
+	return nil
+}
+
 type isOpItem_Op interface {
 	isOpItem_Op()
 }
 
 type OpItem_Sync struct {
 	// Sync signals that the change set is complete (especially useful to know when the initial state is complete)
-	Sync *EmptyOp `protobuf:"bytes,1,opt,name=Sync,proto3,oneof"`
+	Sync *SyncOp `protobuf:"bytes,1,opt,name=Sync,proto3,oneof"`
 }
 
 type OpItem_Reset_ struct {
@@ -300,6 +646,11 @@ type OpItem_Delete struct {
 	Delete *Ref `protobuf:"bytes,3,opt,name=Delete,proto3,oneof"`
 }
 
+type OpItem_Batch struct {
+	// Batch packs several ops (only sent if requested, see BatchingReq)
+	Batch *Batch `protobuf:"bytes,5,opt,name=Batch,proto3,oneof"`
+}
+
 func (*OpItem_Sync) isOpItem_Op() {}
 
 func (*OpItem_Reset_) isOpItem_Op() {}
@@ -308,6 +659,65 @@ func (*OpItem_Set) isOpItem_Op() {}
 
 func (*OpItem_Delete) isOpItem_Op() {}
 
+func (*OpItem_Batch) isOpItem_Op() {}
+
+type Batch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Compression Compression `protobuf:"varint,1,opt,name=Compression,proto3,enum=localv1.Compression" json:"Compression,omitempty"`
+	// Ops are the packed OpItems, each one prefixed by its size as a varint,
+	// compressed as a whole.
+	Ops []byte `protobuf:"bytes,2,opt,name=Ops,proto3" json:"Ops,omitempty"`
+}
+
+func (x *Batch) Reset() {
+	*x = Batch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_localv1_api_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Batch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Batch) ProtoMessage() {}
+
+func (x *Batch) ProtoReflect() protoreflect.Message {
+	mi := &file_api_localv1_api_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Batch.ProtoReflect.Descriptor instead.
+func (*Batch) Descriptor() ([]byte, []int) {
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Batch) GetCompression() Compression {
+	if x != nil {
+		return x.Compression
+	}
+	return Compression_Uncompressed
+}
+
+func (x *Batch) GetOps() []byte {
+	if x != nil {
+		return x.Ops
+	}
+	return nil
+}
+
 type EmptyOp struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -317,7 +727,7 @@ type EmptyOp struct {
 func (x *EmptyOp) Reset() {
 	*x = EmptyOp{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_localv1_api_proto_msgTypes[2]
+		mi := &file_api_localv1_api_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -330,7 +740,7 @@ func (x *EmptyOp) String() string {
 func (*EmptyOp) ProtoMessage() {}
 
 func (x *EmptyOp) ProtoReflect() protoreflect.Message {
-	mi := &file_api_localv1_api_proto_msgTypes[2]
+	mi := &file_api_localv1_api_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -343,7 +753,55 @@ func (x *EmptyOp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EmptyOp.ProtoReflect.Descriptor instead.
 func (*EmptyOp) Descriptor() ([]byte, []int) {
-	return file_api_localv1_api_proto_rawDescGZIP(), []int{2}
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{6}
+}
+
+type SyncOp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Rev is the server's revision of the state, if known
+	Rev uint64 `protobuf:"varint,1,opt,name=Rev,proto3" json:"Rev,omitempty"`
+}
+
+func (x *SyncOp) Reset() {
+	*x = SyncOp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_localv1_api_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SyncOp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncOp) ProtoMessage() {}
+
+func (x *SyncOp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_localv1_api_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncOp.ProtoReflect.Descriptor instead.
+func (*SyncOp) Descriptor() ([]byte, []int) {
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SyncOp) GetRev() uint64 {
+	if x != nil {
+		return x.Rev
+	}
+	return 0
 }
 
 type Ref struct {
@@ -358,7 +816,7 @@ type Ref struct {
 func (x *Ref) Reset() {
 	*x = Ref{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_localv1_api_proto_msgTypes[3]
+		mi := &file_api_localv1_api_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -371,7 +829,7 @@ func (x *Ref) String() string {
 func (*Ref) ProtoMessage() {}
 
 func (x *Ref) ProtoReflect() protoreflect.Message {
-	mi := &file_api_localv1_api_proto_msgTypes[3]
+	mi := &file_api_localv1_api_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -384,7 +842,7 @@ func (x *Ref) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Ref.ProtoReflect.Descriptor instead.
 func (*Ref) Descriptor() ([]byte, []int) {
-	return file_api_localv1_api_proto_rawDescGZIP(), []int{3}
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *Ref) GetSet() Set {
@@ -413,7 +871,7 @@ type Value struct {
 func (x *Value) Reset() {
 	*x = Value{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_localv1_api_proto_msgTypes[4]
+		mi := &file_api_localv1_api_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -426,7 +884,7 @@ func (x *Value) String() string {
 func (*Value) ProtoMessage() {}
 
 func (x *Value) ProtoReflect() protoreflect.Message {
-	mi := &file_api_localv1_api_proto_msgTypes[4]
+	mi := &file_api_localv1_api_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -439,7 +897,7 @@ func (x *Value) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Value.ProtoReflect.Descriptor instead.
 func (*Value) Descriptor() ([]byte, []int) {
-	return file_api_localv1_api_proto_rawDescGZIP(), []int{4}
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *Value) GetRef() *Ref {
@@ -479,12 +937,19 @@ type Service struct {
 	SessionAffinity        isService_SessionAffinity `protobuf_oneof:"SessionAffinity"`
 	InternalTrafficToLocal bool                      `protobuf:"varint,12,opt,name=InternalTrafficToLocal,proto3" json:"InternalTrafficToLocal,omitempty"`
 	HealthCheckNodePort    int32                     `protobuf:"varint,13,opt,name=HealthCheckNodePort,proto3" json:"HealthCheckNodePort,omitempty"`
+	// ExternalName is the DNS name an ExternalName service is an alias of.
+	ExternalName string `protobuf:"bytes,14,opt,name=ExternalName,proto3" json:"ExternalName,omitempty"`
+	// LastChangeTriggerTime is when the last change of the service's endpoints was
+	// triggered, in nanoseconds since the epoch, or 0 if unknown. Backends use it to
+	// measure the network programming latency of changes no endpoint carries (ie:
+	// deletions).
+	LastChangeTriggerTime int64 `protobuf:"varint,15,opt,name=LastChangeTriggerTime,proto3" json:"LastChangeTriggerTime,omitempty"`
 }
 
 func (x *Service) Reset() {
 	*x = Service{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_localv1_api_proto_msgTypes[5]
+		mi := &file_api_localv1_api_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -497,7 +962,7 @@ func (x *Service) String() string {
 func (*Service) ProtoMessage() {}
 
 func (x *Service) ProtoReflect() protoreflect.Message {
-	mi := &file_api_localv1_api_proto_msgTypes[5]
+	mi := &file_api_localv1_api_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -510,7 +975,7 @@ func (x *Service) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Service.ProtoReflect.Descriptor instead.
 func (*Service) Descriptor() ([]byte, []int) {
-	return file_api_localv1_api_proto_rawDescGZIP(), []int{5}
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *Service) GetNamespace() string {
@@ -611,6 +1076,20 @@ func (x *Service) GetHealthCheckNodePort() int32 {
 	return 0
 }
 
+func (x *Service) GetExternalName() string {
+	if x != nil {
+		return x.ExternalName
+	}
+	return ""
+}
+
+func (x *Service) GetLastChangeTriggerTime() int64 {
+	if x != nil {
+		return x.LastChangeTriggerTime
+	}
+	return 0
+}
+
 type isService_SessionAffinity interface {
 	isService_SessionAffinity()
 }
@@ -635,20 +1114,159 @@ type IPFilter struct {
 func (x *IPFilter) Reset() {
 	*x = IPFilter{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_localv1_api_proto_msgTypes[6]
+		mi := &file_api_localv1_api_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IPFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IPFilter) ProtoMessage() {}
+
+func (x *IPFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_api_localv1_api_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IPFilter.ProtoReflect.Descriptor instead.
+func (*IPFilter) Descriptor() ([]byte, []int) {
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *IPFilter) GetTargetIPs() *IPSet {
+	if x != nil {
+		return x.TargetIPs
+	}
+	return nil
+}
+
+func (x *IPFilter) GetSourceRanges() []string {
+	if x != nil {
+		return x.SourceRanges
+	}
+	return nil
+}
+
+type ServiceIPs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ClusterIPs      *IPSet `protobuf:"bytes,1,opt,name=ClusterIPs,proto3" json:"ClusterIPs,omitempty"`
+	ExternalIPs     *IPSet `protobuf:"bytes,2,opt,name=ExternalIPs,proto3" json:"ExternalIPs,omitempty"`
+	LoadBalancerIPs *IPSet `protobuf:"bytes,4,opt,name=LoadBalancerIPs,proto3" json:"LoadBalancerIPs,omitempty"`
+	Headless        bool   `protobuf:"varint,3,opt,name=Headless,proto3" json:"Headless,omitempty"`
+	// LoadBalancerIngresses are the load-balancer ingress points of the service.
+	LoadBalancerIngresses []*LoadBalancerIngress `protobuf:"bytes,5,rep,name=LoadBalancerIngresses,proto3" json:"LoadBalancerIngresses,omitempty"`
+}
+
+func (x *ServiceIPs) Reset() {
+	*x = ServiceIPs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_localv1_api_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ServiceIPs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceIPs) ProtoMessage() {}
+
+func (x *ServiceIPs) ProtoReflect() protoreflect.Message {
+	mi := &file_api_localv1_api_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceIPs.ProtoReflect.Descriptor instead.
+func (*ServiceIPs) Descriptor() ([]byte, []int) {
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ServiceIPs) GetClusterIPs() *IPSet {
+	if x != nil {
+		return x.ClusterIPs
+	}
+	return nil
+}
+
+func (x *ServiceIPs) GetExternalIPs() *IPSet {
+	if x != nil {
+		return x.ExternalIPs
+	}
+	return nil
+}
+
+func (x *ServiceIPs) GetLoadBalancerIPs() *IPSet {
+	if x != nil {
+		return x.LoadBalancerIPs
+	}
+	return nil
+}
+
+func (x *ServiceIPs) GetHeadless() bool {
+	if x != nil {
+		return x.Headless
+	}
+	return false
+}
+
+func (x *ServiceIPs) GetLoadBalancerIngresses() []*LoadBalancerIngress {
+	if x != nil {
+		return x.LoadBalancerIngresses
+	}
+	return nil
+}
+
+type LoadBalancerIngress struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	IP       string `protobuf:"bytes,1,opt,name=IP,proto3" json:"IP,omitempty"`
+	Hostname string `protobuf:"bytes,2,opt,name=Hostname,proto3" json:"Hostname,omitempty"`
+	// IPMode tells if backends should capture the IP (VIP) or let the traffic reach
+	// the load-balancer (Proxy).
+	IPMode LoadBalancerIPMode        `protobuf:"varint,3,opt,name=IPMode,proto3,enum=localv1.LoadBalancerIPMode" json:"IPMode,omitempty"`
+	Ports  []*LoadBalancerPortStatus `protobuf:"bytes,4,rep,name=Ports,proto3" json:"Ports,omitempty"`
+}
+
+func (x *LoadBalancerIngress) Reset() {
+	*x = LoadBalancerIngress{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_localv1_api_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *IPFilter) String() string {
+func (x *LoadBalancerIngress) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*IPFilter) ProtoMessage() {}
+func (*LoadBalancerIngress) ProtoMessage() {}
 
-func (x *IPFilter) ProtoReflect() protoreflect.Message {
-	mi := &file_api_localv1_api_proto_msgTypes[6]
+func (x *LoadBalancerIngress) ProtoReflect() protoreflect.Message {
+	mi := &file_api_localv1_api_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -659,53 +1277,66 @@ func (x *IPFilter) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use IPFilter.ProtoReflect.Descriptor instead.
-func (*IPFilter) Descriptor() ([]byte, []int) {
-	return file_api_localv1_api_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use LoadBalancerIngress.ProtoReflect.Descriptor instead.
+func (*LoadBalancerIngress) Descriptor() ([]byte, []int) {
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *IPFilter) GetTargetIPs() *IPSet {
+func (x *LoadBalancerIngress) GetIP() string {
 	if x != nil {
-		return x.TargetIPs
+		return x.IP
 	}
-	return nil
+	return ""
 }
 
-func (x *IPFilter) GetSourceRanges() []string {
+func (x *LoadBalancerIngress) GetHostname() string {
 	if x != nil {
-		return x.SourceRanges
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *LoadBalancerIngress) GetIPMode() LoadBalancerIPMode {
+	if x != nil {
+		return x.IPMode
+	}
+	return LoadBalancerIPMode_VIP
+}
+
+func (x *LoadBalancerIngress) GetPorts() []*LoadBalancerPortStatus {
+	if x != nil {
+		return x.Ports
 	}
 	return nil
 }
 
-type ServiceIPs struct {
+type LoadBalancerPortStatus struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ClusterIPs      *IPSet `protobuf:"bytes,1,opt,name=ClusterIPs,proto3" json:"ClusterIPs,omitempty"`
-	ExternalIPs     *IPSet `protobuf:"bytes,2,opt,name=ExternalIPs,proto3" json:"ExternalIPs,omitempty"`
-	LoadBalancerIPs *IPSet `protobuf:"bytes,4,opt,name=LoadBalancerIPs,proto3" json:"LoadBalancerIPs,omitempty"`
-	Headless        bool   `protobuf:"varint,3,opt,name=Headless,proto3" json:"Headless,omitempty"`
+	Port     int32    `protobuf:"varint,1,opt,name=Port,proto3" json:"Port,omitempty"`
+	Protocol Protocol `protobuf:"varint,2,opt,name=Protocol,proto3,enum=localv1.Protocol" json:"Protocol,omitempty"`
+	Error    string   `protobuf:"bytes,3,opt,name=Error,proto3" json:"Error,omitempty"`
 }
 
-func (x *ServiceIPs) Reset() {
-	*x = ServiceIPs{}
+func (x *LoadBalancerPortStatus) Reset() {
+	*x = LoadBalancerPortStatus{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_localv1_api_proto_msgTypes[7]
+		mi := &file_api_localv1_api_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ServiceIPs) String() string {
+func (x *LoadBalancerPortStatus) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ServiceIPs) ProtoMessage() {}
+func (*LoadBalancerPortStatus) ProtoMessage() {}
 
-func (x *ServiceIPs) ProtoReflect() protoreflect.Message {
-	mi := &file_api_localv1_api_proto_msgTypes[7]
+func (x *LoadBalancerPortStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_api_localv1_api_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -716,37 +1347,30 @@ func (x *ServiceIPs) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ServiceIPs.ProtoReflect.Descriptor instead.
-func (*ServiceIPs) Descriptor() ([]byte, []int) {
-	return file_api_localv1_api_proto_rawDescGZIP(), []int{7}
-}
-
-func (x *ServiceIPs) GetClusterIPs() *IPSet {
-	if x != nil {
-		return x.ClusterIPs
-	}
-	return nil
+// Deprecated: Use LoadBalancerPortStatus.ProtoReflect.Descriptor instead.
+func (*LoadBalancerPortStatus) Descriptor() ([]byte, []int) {
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *ServiceIPs) GetExternalIPs() *IPSet {
+func (x *LoadBalancerPortStatus) GetPort() int32 {
 	if x != nil {
-		return x.ExternalIPs
+		return x.Port
 	}
-	return nil
+	return 0
 }
 
-func (x *ServiceIPs) GetLoadBalancerIPs() *IPSet {
+func (x *LoadBalancerPortStatus) GetProtocol() Protocol {
 	if x != nil {
-		return x.LoadBalancerIPs
+		return x.Protocol
 	}
-	return nil
+	return Protocol_UnknownProtocol
 }
 
-func (x *ServiceIPs) GetHeadless() bool {
+func (x *LoadBalancerPortStatus) GetError() string {
 	if x != nil {
-		return x.Headless
+		return x.Error
 	}
-	return false
+	return ""
 }
 
 type Endpoint struct {
@@ -759,12 +1383,19 @@ type Endpoint struct {
 	Local         bool            `protobuf:"varint,3,opt,name=Local,proto3" json:"Local,omitempty"`
 	PortOverrides []*PortName     `protobuf:"bytes,4,rep,name=PortOverrides,proto3" json:"PortOverrides,omitempty"`
 	Scopes        *EndpointScopes `protobuf:"bytes,5,opt,name=Scopes,proto3" json:"Scopes,omitempty"`
+	// true if the endpoint is terminating (only provided as a fallback when
+	// no ready endpoint is available).
+	Terminating bool `protobuf:"varint,6,opt,name=Terminating,proto3" json:"Terminating,omitempty"`
+	// LastChangeTriggerTime is when the change of this endpoint was triggered, in
+	// nanoseconds since the epoch, or 0 if unknown. Backends use it to measure
+	// the network programming latency.
+	LastChangeTriggerTime int64 `protobuf:"varint,7,opt,name=LastChangeTriggerTime,proto3" json:"LastChangeTriggerTime,omitempty"`
 }
 
 func (x *Endpoint) Reset() {
 	*x = Endpoint{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_localv1_api_proto_msgTypes[8]
+		mi := &file_api_localv1_api_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -777,7 +1408,7 @@ func (x *Endpoint) String() string {
 func (*Endpoint) ProtoMessage() {}
 
 func (x *Endpoint) ProtoReflect() protoreflect.Message {
-	mi := &file_api_localv1_api_proto_msgTypes[8]
+	mi := &file_api_localv1_api_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -790,7 +1421,7 @@ func (x *Endpoint) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Endpoint.ProtoReflect.Descriptor instead.
 func (*Endpoint) Descriptor() ([]byte, []int) {
-	return file_api_localv1_api_proto_rawDescGZIP(), []int{8}
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *Endpoint) GetHostname() string {
@@ -828,6 +1459,20 @@ func (x *Endpoint) GetScopes() *EndpointScopes {
 	return nil
 }
 
+func (x *Endpoint) GetTerminating() bool {
+	if x != nil {
+		return x.Terminating
+	}
+	return false
+}
+
+func (x *Endpoint) GetLastChangeTriggerTime() int64 {
+	if x != nil {
+		return x.LastChangeTriggerTime
+	}
+	return 0
+}
+
 type EndpointScopes struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -840,7 +1485,7 @@ type EndpointScopes struct {
 func (x *EndpointScopes) Reset() {
 	*x = EndpointScopes{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_localv1_api_proto_msgTypes[9]
+		mi := &file_api_localv1_api_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -853,7 +1498,7 @@ func (x *EndpointScopes) String() string {
 func (*EndpointScopes) ProtoMessage() {}
 
 func (x *EndpointScopes) ProtoReflect() protoreflect.Message {
-	mi := &file_api_localv1_api_proto_msgTypes[9]
+	mi := &file_api_localv1_api_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -866,7 +1511,7 @@ func (x *EndpointScopes) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use EndpointScopes.ProtoReflect.Descriptor instead.
 func (*EndpointScopes) Descriptor() ([]byte, []int) {
-	return file_api_localv1_api_proto_rawDescGZIP(), []int{9}
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *EndpointScopes) GetInternal() bool {
@@ -883,6 +1528,95 @@ func (x *EndpointScopes) GetExternal() bool {
 	return false
 }
 
+// Node is the node the watch was requested for, as known by the cluster.
+type Node struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name        string            `protobuf:"bytes,1,opt,name=Name,proto3" json:"Name,omitempty"`
+	Labels      map[string]string `protobuf:"bytes,2,rep,name=Labels,proto3" json:"Labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Annotations map[string]string `protobuf:"bytes,3,rep,name=Annotations,proto3" json:"Annotations,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	InternalIPs *IPSet            `protobuf:"bytes,4,opt,name=InternalIPs,proto3" json:"InternalIPs,omitempty"`
+	ExternalIPs *IPSet            `protobuf:"bytes,5,opt,name=ExternalIPs,proto3" json:"ExternalIPs,omitempty"`
+	// PodCIDRs are the ranges allocated to the pods on this node.
+	PodCIDRs []string `protobuf:"bytes,6,rep,name=PodCIDRs,proto3" json:"PodCIDRs,omitempty"`
+}
+
+func (x *Node) Reset() {
+	*x = Node{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_localv1_api_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Node) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Node) ProtoMessage() {}
+
+func (x *Node) ProtoReflect() protoreflect.Message {
+	mi := &file_api_localv1_api_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Node.ProtoReflect.Descriptor instead.
+func (*Node) Descriptor() ([]byte, []int) {
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Node) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Node) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Node) GetAnnotations() map[string]string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+func (x *Node) GetInternalIPs() *IPSet {
+	if x != nil {
+		return x.InternalIPs
+	}
+	return nil
+}
+
+func (x *Node) GetExternalIPs() *IPSet {
+	if x != nil {
+		return x.ExternalIPs
+	}
+	return nil
+}
+
+func (x *Node) GetPodCIDRs() []string {
+	if x != nil {
+		return x.PodCIDRs
+	}
+	return nil
+}
+
 type IPSet struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -895,7 +1629,7 @@ type IPSet struct {
 func (x *IPSet) Reset() {
 	*x = IPSet{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_localv1_api_proto_msgTypes[10]
+		mi := &file_api_localv1_api_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -908,7 +1642,7 @@ func (x *IPSet) String() string {
 func (*IPSet) ProtoMessage() {}
 
 func (x *IPSet) ProtoReflect() protoreflect.Message {
-	mi := &file_api_localv1_api_proto_msgTypes[10]
+	mi := &file_api_localv1_api_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -921,7 +1655,7 @@ func (x *IPSet) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IPSet.ProtoReflect.Descriptor instead.
 func (*IPSet) Descriptor() ([]byte, []int) {
-	return file_api_localv1_api_proto_rawDescGZIP(), []int{10}
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *IPSet) GetV4() []string {
@@ -950,7 +1684,7 @@ type PortName struct {
 func (x *PortName) Reset() {
 	*x = PortName{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_localv1_api_proto_msgTypes[11]
+		mi := &file_api_localv1_api_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -963,7 +1697,7 @@ func (x *PortName) String() string {
 func (*PortName) ProtoMessage() {}
 
 func (x *PortName) ProtoReflect() protoreflect.Message {
-	mi := &file_api_localv1_api_proto_msgTypes[11]
+	mi := &file_api_localv1_api_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -976,7 +1710,7 @@ func (x *PortName) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PortName.ProtoReflect.Descriptor instead.
 func (*PortName) Descriptor() ([]byte, []int) {
-	return file_api_localv1_api_proto_rawDescGZIP(), []int{11}
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *PortName) GetName() string {
@@ -1009,7 +1743,7 @@ type PortMapping struct {
 func (x *PortMapping) Reset() {
 	*x = PortMapping{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_localv1_api_proto_msgTypes[12]
+		mi := &file_api_localv1_api_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1022,7 +1756,7 @@ func (x *PortMapping) String() string {
 func (*PortMapping) ProtoMessage() {}
 
 func (x *PortMapping) ProtoReflect() protoreflect.Message {
-	mi := &file_api_localv1_api_proto_msgTypes[12]
+	mi := &file_api_localv1_api_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1035,7 +1769,7 @@ func (x *PortMapping) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PortMapping.ProtoReflect.Descriptor instead.
 func (*PortMapping) Descriptor() ([]byte, []int) {
-	return file_api_localv1_api_proto_rawDescGZIP(), []int{12}
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *PortMapping) GetName() string {
@@ -1091,7 +1825,7 @@ type ClientIPAffinity struct {
 func (x *ClientIPAffinity) Reset() {
 	*x = ClientIPAffinity{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_localv1_api_proto_msgTypes[13]
+		mi := &file_api_localv1_api_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1104,7 +1838,7 @@ func (x *ClientIPAffinity) String() string {
 func (*ClientIPAffinity) ProtoMessage() {}
 
 func (x *ClientIPAffinity) ProtoReflect() protoreflect.Message {
-	mi := &file_api_localv1_api_proto_msgTypes[13]
+	mi := &file_api_localv1_api_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1117,7 +1851,7 @@ func (x *ClientIPAffinity) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ClientIPAffinity.ProtoReflect.Descriptor instead.
 func (*ClientIPAffinity) Descriptor() ([]byte, []int) {
-	return file_api_localv1_api_proto_rawDescGZIP(), []int{13}
+	return file_api_localv1_api_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *ClientIPAffinity) GetTimeoutSeconds() int32 {
@@ -1132,149 +1866,255 @@ var File_api_localv1_api_proto protoreflect.FileDescriptor
 var file_api_localv1_api_proto_rawDesc = []byte{
 	0x0a, 0x15, 0x61, 0x70, 0x69, 0x2f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2f, 0x61, 0x70,
 	0x69, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x07, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31,
-	0x22, 0x26, 0x0a, 0x08, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x12, 0x1a, 0x0a, 0x08,
-	0x4e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
-	0x4e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0xac, 0x01, 0x0a, 0x06, 0x4f, 0x70, 0x49,
-	0x74, 0x65, 0x6d, 0x12, 0x26, 0x0a, 0x04, 0x53, 0x79, 0x6e, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x10, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x45, 0x6d, 0x70, 0x74,
-	0x79, 0x4f, 0x70, 0x48, 0x00, 0x52, 0x04, 0x53, 0x79, 0x6e, 0x63, 0x12, 0x28, 0x0a, 0x05, 0x52,
-	0x65, 0x73, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6c, 0x6f, 0x63,
-	0x61, 0x6c, 0x76, 0x31, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x4f, 0x70, 0x48, 0x00, 0x52, 0x05,
-	0x52, 0x65, 0x73, 0x65, 0x74, 0x12, 0x22, 0x0a, 0x03, 0x53, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c,
-	0x75, 0x65, 0x48, 0x00, 0x52, 0x03, 0x53, 0x65, 0x74, 0x12, 0x26, 0x0a, 0x06, 0x44, 0x65, 0x6c,
-	0x65, 0x74, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6c, 0x6f, 0x63, 0x61,
-	0x6c, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x66, 0x48, 0x00, 0x52, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74,
-	0x65, 0x42, 0x04, 0x0a, 0x02, 0x4f, 0x70, 0x22, 0x09, 0x0a, 0x07, 0x45, 0x6d, 0x70, 0x74, 0x79,
-	0x4f, 0x70, 0x22, 0x39, 0x0a, 0x03, 0x52, 0x65, 0x66, 0x12, 0x1e, 0x0a, 0x03, 0x53, 0x65, 0x74,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31,
-	0x2e, 0x53, 0x65, 0x74, 0x52, 0x03, 0x53, 0x65, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x50, 0x61, 0x74,
-	0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x50, 0x61, 0x74, 0x68, 0x22, 0x3d, 0x0a,
-	0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1e, 0x0a, 0x03, 0x52, 0x65, 0x66, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x52, 0x65,
-	0x66, 0x52, 0x03, 0x52, 0x65, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x42, 0x79, 0x74, 0x65, 0x73, 0x22, 0xcd, 0x05, 0x0a,
-	0x07, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x4e, 0x61, 0x6d, 0x65,
-	0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x4e, 0x61, 0x6d,
-	0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x79,
-	0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x34,
-	0x0a, 0x06, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c,
-	0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65,
-	0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x4c, 0x61,
-	0x62, 0x65, 0x6c, 0x73, 0x12, 0x43, 0x0a, 0x0b, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6c, 0x6f, 0x63, 0x61,
-	0x6c, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x41, 0x6e, 0x6e, 0x6f,
-	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x41, 0x6e,
-	0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x25, 0x0a, 0x03, 0x49, 0x50, 0x73,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31,
-	0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x49, 0x50, 0x73, 0x52, 0x03, 0x49, 0x50, 0x73,
-	0x12, 0x2f, 0x0a, 0x09, 0x49, 0x50, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x73, 0x18, 0x0a, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x49, 0x50,
-	0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x09, 0x49, 0x50, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72,
-	0x73, 0x12, 0x14, 0x0a, 0x05, 0x4d, 0x61, 0x70, 0x49, 0x50, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x05, 0x4d, 0x61, 0x70, 0x49, 0x50, 0x12, 0x2a, 0x0a, 0x05, 0x50, 0x6f, 0x72, 0x74, 0x73,
-	0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31,
-	0x2e, 0x50, 0x6f, 0x72, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x52, 0x05, 0x50, 0x6f,
-	0x72, 0x74, 0x73, 0x12, 0x36, 0x0a, 0x16, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x54,
-	0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x54, 0x6f, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x18, 0x07, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x16, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x54, 0x72, 0x61,
-	0x66, 0x66, 0x69, 0x63, 0x54, 0x6f, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x12, 0x37, 0x0a, 0x08, 0x43,
-	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x50, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
-	0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x50,
-	0x41, 0x66, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x79, 0x48, 0x00, 0x52, 0x08, 0x43, 0x6c, 0x69, 0x65,
-	0x6e, 0x74, 0x49, 0x50, 0x12, 0x36, 0x0a, 0x16, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c,
-	0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x54, 0x6f, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x18, 0x0c,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x16, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x54, 0x72,
-	0x61, 0x66, 0x66, 0x69, 0x63, 0x54, 0x6f, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x12, 0x30, 0x0a, 0x13,
-	0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x50,
-	0x6f, 0x72, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x48, 0x65, 0x61, 0x6c, 0x74,
-	0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x1a, 0x39,
-	0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
-	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
-	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3e, 0x0a, 0x10, 0x41, 0x6e, 0x6e,
-	0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a,
-	0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12,
-	0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x11, 0x0a, 0x0f, 0x53, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x41, 0x66, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x79, 0x22, 0x5c, 0x0a, 0x08,
-	0x49, 0x50, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x2c, 0x0a, 0x09, 0x54, 0x61, 0x72, 0x67,
-	0x65, 0x74, 0x49, 0x50, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c, 0x6f,
-	0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x49, 0x50, 0x53, 0x65, 0x74, 0x52, 0x09, 0x54, 0x61, 0x72,
-	0x67, 0x65, 0x74, 0x49, 0x50, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65,
-	0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x53, 0x6f,
-	0x75, 0x72, 0x63, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x22, 0xc4, 0x01, 0x0a, 0x0a, 0x53,
-	0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x49, 0x50, 0x73, 0x12, 0x2e, 0x0a, 0x0a, 0x43, 0x6c, 0x75,
-	0x73, 0x74, 0x65, 0x72, 0x49, 0x50, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
-	0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x49, 0x50, 0x53, 0x65, 0x74, 0x52, 0x0a, 0x43,
-	0x6c, 0x75, 0x73, 0x74, 0x65, 0x72, 0x49, 0x50, 0x73, 0x12, 0x30, 0x0a, 0x0b, 0x45, 0x78, 0x74,
-	0x65, 0x72, 0x6e, 0x61, 0x6c, 0x49, 0x50, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e,
-	0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x49, 0x50, 0x53, 0x65, 0x74, 0x52, 0x0b,
-	0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x49, 0x50, 0x73, 0x12, 0x38, 0x0a, 0x0f, 0x4c,
-	0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x49, 0x50, 0x73, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x49,
-	0x50, 0x53, 0x65, 0x74, 0x52, 0x0f, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63,
-	0x65, 0x72, 0x49, 0x50, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x48, 0x65, 0x61, 0x64, 0x6c, 0x65, 0x73,
-	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x48, 0x65, 0x61, 0x64, 0x6c, 0x65, 0x73,
-	0x73, 0x22, 0xc8, 0x01, 0x0a, 0x08, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1a,
-	0x0a, 0x08, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x03, 0x49, 0x50,
-	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76,
-	0x31, 0x2e, 0x49, 0x50, 0x53, 0x65, 0x74, 0x52, 0x03, 0x49, 0x50, 0x73, 0x12, 0x14, 0x0a, 0x05,
-	0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x4c, 0x6f, 0x63,
-	0x61, 0x6c, 0x12, 0x37, 0x0a, 0x0d, 0x50, 0x6f, 0x72, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69,
-	0x64, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6c, 0x6f, 0x63, 0x61,
-	0x6c, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x52, 0x0d, 0x50, 0x6f,
-	0x72, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x12, 0x2f, 0x0a, 0x06, 0x53,
-	0x63, 0x6f, 0x70, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x6c, 0x6f,
-	0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x53, 0x63,
-	0x6f, 0x70, 0x65, 0x73, 0x52, 0x06, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x22, 0x48, 0x0a, 0x0e,
-	0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x1a,
-	0x0a, 0x08, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x08, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x45, 0x78,
-	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x45, 0x78,
-	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x22, 0x27, 0x0a, 0x05, 0x49, 0x50, 0x53, 0x65, 0x74, 0x12,
-	0x0e, 0x0a, 0x02, 0x56, 0x34, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x02, 0x56, 0x34, 0x12,
-	0x0e, 0x0a, 0x02, 0x56, 0x36, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x02, 0x56, 0x36, 0x22,
-	0x32, 0x0a, 0x08, 0x50, 0x6f, 0x72, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x4e,
-	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12,
-	0x12, 0x0a, 0x04, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x50,
-	0x6f, 0x72, 0x74, 0x22, 0xc8, 0x01, 0x0a, 0x0b, 0x50, 0x6f, 0x72, 0x74, 0x4d, 0x61, 0x70, 0x70,
-	0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2d, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x74, 0x6f,
-	0x63, 0x6f, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x6c, 0x6f, 0x63, 0x61,
-	0x6c, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x52, 0x08, 0x50, 0x72,
-	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x4e, 0x6f,
-	0x64, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x4e, 0x6f,
-	0x64, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
-	0x50, 0x6f, 0x72, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x54, 0x61, 0x72, 0x67,
-	0x65, 0x74, 0x50, 0x6f, 0x72, 0x74, 0x12, 0x26, 0x0a, 0x0e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
-	0x50, 0x6f, 0x72, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
-	0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x6f, 0x72, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x3a,
-	0x0a, 0x10, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x50, 0x41, 0x66, 0x66, 0x69, 0x6e, 0x69,
-	0x74, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63,
-	0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x54, 0x69, 0x6d, 0x65,
-	0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x2a, 0x7e, 0x0a, 0x03, 0x53, 0x65,
+	0x22, 0xd8, 0x01, 0x0a, 0x08, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x12, 0x1a, 0x0a,
+	0x08, 0x4e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x4e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x44, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x06, 0x46,
+	0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6c, 0x6f,
+	0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x46, 0x69, 0x6c, 0x74, 0x65,
+	0x72, 0x52, 0x06, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x07, 0x41, 0x70, 0x70,
+	0x6c, 0x69, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x76, 0x31, 0x2e, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x07, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x12, 0x30, 0x0a, 0x08, 0x42, 0x61, 0x74,
+	0x63, 0x68, 0x69, 0x6e, 0x67, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6c, 0x6f,
+	0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x52, 0x65,
+	0x71, 0x52, 0x08, 0x42, 0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x22, 0x63, 0x0a, 0x0b, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x12, 0x1a, 0x0a, 0x08, 0x4d, 0x61,
+	0x78, 0x42, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x4d, 0x61,
+	0x78, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x38, 0x0a, 0x0c, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x6c,
+	0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x52, 0x0c, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73,
+	0x22, 0x5b, 0x0a, 0x0b, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
+	0x10, 0x0a, 0x03, 0x52, 0x65, 0x76, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x52, 0x65,
+	0x76, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x24, 0x0a, 0x0d, 0x44, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x4e, 0x61, 0x6e, 0x6f, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d,
+	0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4e, 0x61, 0x6e, 0x6f, 0x73, 0x22, 0x77, 0x0a,
+	0x0b, 0x57, 0x61, 0x74, 0x63, 0x68, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a,
+	0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0a, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x73, 0x12, 0x24, 0x0a, 0x0d,
+	0x4c, 0x61, 0x62, 0x65, 0x6c, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x54, 0x79, 0x70,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63,
+	0x65, 0x54, 0x79, 0x70, 0x65, 0x73, 0x22, 0xd3, 0x01, 0x0a, 0x06, 0x4f, 0x70, 0x49, 0x74, 0x65,
+	0x6d, 0x12, 0x25, 0x0a, 0x04, 0x53, 0x79, 0x6e, 0x63, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x4f, 0x70,
+	0x48, 0x00, 0x52, 0x04, 0x53, 0x79, 0x6e, 0x63, 0x12, 0x28, 0x0a, 0x05, 0x52, 0x65, 0x73, 0x65,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76,
+	0x31, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x4f, 0x70, 0x48, 0x00, 0x52, 0x05, 0x52, 0x65, 0x73,
+	0x65, 0x74, 0x12, 0x22, 0x0a, 0x03, 0x53, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x48,
+	0x00, 0x52, 0x03, 0x53, 0x65, 0x74, 0x12, 0x26, 0x0a, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31,
+	0x2e, 0x52, 0x65, 0x66, 0x48, 0x00, 0x52, 0x06, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x26,
+	0x0a, 0x05, 0x42, 0x61, 0x74, 0x63, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x48, 0x00, 0x52,
+	0x05, 0x42, 0x61, 0x74, 0x63, 0x68, 0x42, 0x04, 0x0a, 0x02, 0x4f, 0x70, 0x22, 0x51, 0x0a, 0x05,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x36, 0x0a, 0x0b, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x52, 0x0b, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a,
+	0x03, 0x4f, 0x70, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x4f, 0x70, 0x73, 0x22,
+	0x09, 0x0a, 0x07, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x4f, 0x70, 0x22, 0x1a, 0x0a, 0x06, 0x53, 0x79,
+	0x6e, 0x63, 0x4f, 0x70, 0x12, 0x10, 0x0a, 0x03, 0x52, 0x65, 0x76, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x03, 0x52, 0x65, 0x76, 0x22, 0x39, 0x0a, 0x03, 0x52, 0x65, 0x66, 0x12, 0x1e, 0x0a,
+	0x03, 0x53, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0c, 0x2e, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x74, 0x52, 0x03, 0x53, 0x65, 0x74, 0x12, 0x12, 0x0a,
+	0x04, 0x50, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x50, 0x61, 0x74,
+	0x68, 0x22, 0x3d, 0x0a, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x1e, 0x0a, 0x03, 0x52, 0x65,
+	0x66, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76,
+	0x31, 0x2e, 0x52, 0x65, 0x66, 0x52, 0x03, 0x52, 0x65, 0x66, 0x12, 0x14, 0x0a, 0x05, 0x42, 0x79,
+	0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x42, 0x79, 0x74, 0x65, 0x73,
+	0x22, 0xa7, 0x06, 0x0a, 0x07, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09,
+	0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12,
+	0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x34, 0x0a, 0x06, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x08, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x06, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x12, 0x43, 0x0a, 0x0b, 0x41, 0x6e, 0x6e, 0x6f,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x21, 0x2e,
+	0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x2e,
+	0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x0b, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x25, 0x0a,
+	0x03, 0x49, 0x50, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x49, 0x50, 0x73, 0x52,
+	0x03, 0x49, 0x50, 0x73, 0x12, 0x2f, 0x0a, 0x09, 0x49, 0x50, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76,
+	0x31, 0x2e, 0x49, 0x50, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x52, 0x09, 0x49, 0x50, 0x46, 0x69,
+	0x6c, 0x74, 0x65, 0x72, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x4d, 0x61, 0x70, 0x49, 0x50, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x4d, 0x61, 0x70, 0x49, 0x50, 0x12, 0x2a, 0x0a, 0x05, 0x50,
+	0x6f, 0x72, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67,
+	0x52, 0x05, 0x50, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x36, 0x0a, 0x16, 0x45, 0x78, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x54, 0x6f, 0x4c, 0x6f, 0x63, 0x61,
+	0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x16, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x54, 0x6f, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x12,
+	0x37, 0x0a, 0x08, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x50, 0x18, 0x0b, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x43, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x49, 0x50, 0x41, 0x66, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x79, 0x48, 0x00, 0x52, 0x08,
+	0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x50, 0x12, 0x36, 0x0a, 0x16, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x6e, 0x61, 0x6c, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x54, 0x6f, 0x4c, 0x6f, 0x63,
+	0x61, 0x6c, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x16, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e,
+	0x61, 0x6c, 0x54, 0x72, 0x61, 0x66, 0x66, 0x69, 0x63, 0x54, 0x6f, 0x4c, 0x6f, 0x63, 0x61, 0x6c,
+	0x12, 0x30, 0x0a, 0x13, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x4e,
+	0x6f, 0x64, 0x65, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x48,
+	0x65, 0x61, 0x6c, 0x74, 0x68, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x4e, 0x6f, 0x64, 0x65, 0x50, 0x6f,
+	0x72, 0x74, 0x12, 0x22, 0x0a, 0x0c, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x4e, 0x61,
+	0x6d, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x45, 0x78, 0x74, 0x65, 0x72, 0x6e,
+	0x61, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x34, 0x0a, 0x15, 0x4c, 0x61, 0x73, 0x74, 0x43, 0x68,
+	0x61, 0x6e, 0x67, 0x65, 0x54, 0x72, 0x69, 0x67, 0x67, 0x65, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x18,
+	0x0f, 0x20, 0x01, 0x28, 0x03, 0x52, 0x15, 0x4c, 0x61, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x54, 0x72, 0x69, 0x67, 0x67, 0x65, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x1a, 0x39, 0x0a, 0x0b,
+	0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x3e, 0x0a, 0x10, 0x41, 0x6e, 0x6e, 0x6f, 0x74,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x42, 0x11, 0x0a, 0x0f, 0x53, 0x65, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x41, 0x66, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x79, 0x22, 0x5c, 0x0a, 0x08, 0x49, 0x50,
+	0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x12, 0x2c, 0x0a, 0x09, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x49, 0x50, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c, 0x6f, 0x63, 0x61,
+	0x6c, 0x76, 0x31, 0x2e, 0x49, 0x50, 0x53, 0x65, 0x74, 0x52, 0x09, 0x54, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x49, 0x50, 0x73, 0x12, 0x22, 0x0a, 0x0c, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x53, 0x6f, 0x75, 0x72,
+	0x63, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x22, 0x98, 0x02, 0x0a, 0x0a, 0x53, 0x65, 0x72,
+	0x76, 0x69, 0x63, 0x65, 0x49, 0x50, 0x73, 0x12, 0x2e, 0x0a, 0x0a, 0x43, 0x6c, 0x75, 0x73, 0x74,
+	0x65, 0x72, 0x49, 0x50, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c, 0x6f,
+	0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x49, 0x50, 0x53, 0x65, 0x74, 0x52, 0x0a, 0x43, 0x6c, 0x75,
+	0x73, 0x74, 0x65, 0x72, 0x49, 0x50, 0x73, 0x12, 0x30, 0x0a, 0x0b, 0x45, 0x78, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x49, 0x50, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c,
+	0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x49, 0x50, 0x53, 0x65, 0x74, 0x52, 0x0b, 0x45, 0x78,
+	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x49, 0x50, 0x73, 0x12, 0x38, 0x0a, 0x0f, 0x4c, 0x6f, 0x61,
+	0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x49, 0x50, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x49, 0x50, 0x53,
+	0x65, 0x74, 0x52, 0x0f, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72,
+	0x49, 0x50, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x48, 0x65, 0x61, 0x64, 0x6c, 0x65, 0x73, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x48, 0x65, 0x61, 0x64, 0x6c, 0x65, 0x73, 0x73, 0x12,
+	0x52, 0x0a, 0x15, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x49,
+	0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c,
+	0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c,
+	0x61, 0x6e, 0x63, 0x65, 0x72, 0x49, 0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x15, 0x4c, 0x6f,
+	0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x49, 0x6e, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x65, 0x73, 0x22, 0xad, 0x01, 0x0a, 0x13, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61,
+	0x6e, 0x63, 0x65, 0x72, 0x49, 0x6e, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x49,
+	0x50, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x50, 0x12, 0x1a, 0x0a, 0x08, 0x48,
+	0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x48,
+	0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x33, 0x0a, 0x06, 0x49, 0x50, 0x4d, 0x6f, 0x64,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76,
+	0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x49, 0x50,
+	0x4d, 0x6f, 0x64, 0x65, 0x52, 0x06, 0x49, 0x50, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x35, 0x0a, 0x05,
+	0x50, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x6c, 0x6f,
+	0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63,
+	0x65, 0x72, 0x50, 0x6f, 0x72, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x05, 0x50, 0x6f,
+	0x72, 0x74, 0x73, 0x22, 0x71, 0x0a, 0x16, 0x4c, 0x6f, 0x61, 0x64, 0x42, 0x61, 0x6c, 0x61, 0x6e,
+	0x63, 0x65, 0x72, 0x50, 0x6f, 0x72, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a,
+	0x04, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x50, 0x6f, 0x72,
+	0x74, 0x12, 0x2d, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x52, 0x08, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x12, 0x14, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x22, 0xa0, 0x02, 0x0a, 0x08, 0x45, 0x6e, 0x64, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x48, 0x6f, 0x73, 0x74, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x20, 0x0a, 0x03, 0x49, 0x50, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c,
+	0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x49, 0x50, 0x53, 0x65, 0x74, 0x52, 0x03, 0x49, 0x50,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x05, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x12, 0x37, 0x0a, 0x0d, 0x50, 0x6f, 0x72, 0x74, 0x4f,
+	0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x72, 0x74, 0x4e, 0x61, 0x6d,
+	0x65, 0x52, 0x0d, 0x50, 0x6f, 0x72, 0x74, 0x4f, 0x76, 0x65, 0x72, 0x72, 0x69, 0x64, 0x65, 0x73,
+	0x12, 0x2f, 0x0a, 0x06, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x64, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x52, 0x06, 0x53, 0x63, 0x6f, 0x70, 0x65,
+	0x73, 0x12, 0x20, 0x0a, 0x0b, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74, 0x69, 0x6e, 0x67,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x54, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x74,
+	0x69, 0x6e, 0x67, 0x12, 0x34, 0x0a, 0x15, 0x4c, 0x61, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67,
+	0x65, 0x54, 0x72, 0x69, 0x67, 0x67, 0x65, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x15, 0x4c, 0x61, 0x73, 0x74, 0x43, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x54, 0x72,
+	0x69, 0x67, 0x67, 0x65, 0x72, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x48, 0x0a, 0x0e, 0x45, 0x6e, 0x64,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x12, 0x1a, 0x0a, 0x08, 0x45, 0x78, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x45, 0x78, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x22, 0x8a, 0x03, 0x0a, 0x04, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x31, 0x0a, 0x06, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x2e,
+	0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x4c, 0x61, 0x62,
+	0x65, 0x6c, 0x73, 0x12, 0x40, 0x0a, 0x0b, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
+	0x76, 0x31, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x30, 0x0a, 0x0b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
+	0x6c, 0x49, 0x50, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x76, 0x31, 0x2e, 0x49, 0x50, 0x53, 0x65, 0x74, 0x52, 0x0b, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x6e, 0x61, 0x6c, 0x49, 0x50, 0x73, 0x12, 0x30, 0x0a, 0x0b, 0x45, 0x78, 0x74, 0x65, 0x72,
+	0x6e, 0x61, 0x6c, 0x49, 0x50, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c,
+	0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x49, 0x50, 0x53, 0x65, 0x74, 0x52, 0x0b, 0x45, 0x78,
+	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x49, 0x50, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x50, 0x6f, 0x64,
+	0x43, 0x49, 0x44, 0x52, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x50, 0x6f, 0x64,
+	0x43, 0x49, 0x44, 0x52, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x1a, 0x3e, 0x0a, 0x10, 0x41, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0x27, 0x0a, 0x05, 0x49, 0x50, 0x53, 0x65, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x56, 0x34, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x02, 0x56, 0x34, 0x12, 0x0e, 0x0a, 0x02, 0x56, 0x36, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x02, 0x56, 0x36, 0x22, 0x32, 0x0a, 0x08, 0x50, 0x6f, 0x72,
+	0x74, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x50, 0x6f, 0x72,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x50, 0x6f, 0x72, 0x74, 0x22, 0xc8, 0x01,
+	0x0a, 0x0b, 0x50, 0x6f, 0x72, 0x74, 0x4d, 0x61, 0x70, 0x70, 0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a,
+	0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x2d, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x50, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x52, 0x08, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x12, 0x12, 0x0a, 0x04, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x50, 0x6f, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x4e, 0x6f, 0x64, 0x65, 0x50, 0x6f, 0x72, 0x74,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x4e, 0x6f, 0x64, 0x65, 0x50, 0x6f, 0x72, 0x74,
+	0x12, 0x1e, 0x0a, 0x0a, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x6f, 0x72, 0x74, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x6f, 0x72, 0x74,
+	0x12, 0x26, 0x0a, 0x0e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x50, 0x6f, 0x72, 0x74, 0x4e, 0x61,
+	0x6d, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x50, 0x6f, 0x72, 0x74, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x3a, 0x0a, 0x10, 0x43, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x49, 0x50, 0x41, 0x66, 0x66, 0x69, 0x6e, 0x69, 0x74, 0x79, 0x12, 0x26, 0x0a, 0x0e,
+	0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63,
+	0x6f, 0x6e, 0x64, 0x73, 0x2a, 0x33, 0x0a, 0x0b, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x12, 0x10, 0x0a, 0x0c, 0x55, 0x6e, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73,
+	0x73, 0x65, 0x64, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x47, 0x7a, 0x69, 0x70, 0x10, 0x01, 0x12,
+	0x08, 0x0a, 0x04, 0x5a, 0x73, 0x74, 0x64, 0x10, 0x02, 0x2a, 0x8c, 0x01, 0x0a, 0x03, 0x53, 0x65,
 	0x74, 0x12, 0x0e, 0x0a, 0x0a, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x53, 0x65, 0x74, 0x10,
 	0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x73, 0x53, 0x65, 0x74,
 	0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x53,
-	0x65, 0x74, 0x10, 0x02, 0x12, 0x16, 0x0a, 0x12, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x73, 0x10, 0x0a, 0x12, 0x17, 0x0a, 0x13,
-	0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x6e,
-	0x66, 0x6f, 0x73, 0x10, 0x0b, 0x12, 0x13, 0x0a, 0x0f, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x4e,
-	0x6f, 0x64, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x73, 0x10, 0x0c, 0x2a, 0x3b, 0x0a, 0x08, 0x50, 0x72,
-	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x13, 0x0a, 0x0f, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77,
-	0x6e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x54,
-	0x43, 0x50, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03, 0x55, 0x44, 0x50, 0x10, 0x02, 0x12, 0x08, 0x0a,
-	0x04, 0x53, 0x43, 0x54, 0x50, 0x10, 0x03, 0x32, 0x37, 0x0a, 0x04, 0x53, 0x65, 0x74, 0x73, 0x12,
-	0x2f, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68, 0x12, 0x11, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
-	0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x1a, 0x0f, 0x2e, 0x6c, 0x6f,
-	0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x4f, 0x70, 0x49, 0x74, 0x65, 0x6d, 0x28, 0x01, 0x30, 0x01,
-	0x42, 0x1e, 0x5a, 0x1c, 0x73, 0x69, 0x67, 0x73, 0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2f,
-	0x6b, 0x70, 0x6e, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x74, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x4e, 0x6f, 0x64, 0x65, 0x73, 0x53, 0x65, 0x74,
+	0x10, 0x03, 0x12, 0x16, 0x0a, 0x12, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x53, 0x65, 0x72, 0x76,
+	0x69, 0x63, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x73, 0x10, 0x0a, 0x12, 0x17, 0x0a, 0x13, 0x47, 0x6c,
+	0x6f, 0x62, 0x61, 0x6c, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x49, 0x6e, 0x66, 0x6f,
+	0x73, 0x10, 0x0b, 0x12, 0x13, 0x0a, 0x0f, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x4e, 0x6f, 0x64,
+	0x65, 0x49, 0x6e, 0x66, 0x6f, 0x73, 0x10, 0x0c, 0x2a, 0x28, 0x0a, 0x12, 0x4c, 0x6f, 0x61, 0x64,
+	0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x72, 0x49, 0x50, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x07,
+	0x0a, 0x03, 0x56, 0x49, 0x50, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x50, 0x72, 0x6f, 0x78, 0x79,
+	0x10, 0x01, 0x2a, 0x3b, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x12, 0x13,
+	0x0a, 0x0f, 0x55, 0x6e, 0x6b, 0x6e, 0x6f, 0x77, 0x6e, 0x50, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x43, 0x50, 0x10, 0x01, 0x12, 0x07, 0x0a, 0x03,
+	0x55, 0x44, 0x50, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x43, 0x54, 0x50, 0x10, 0x03, 0x32,
+	0x37, 0x0a, 0x04, 0x53, 0x65, 0x74, 0x73, 0x12, 0x2f, 0x0a, 0x05, 0x57, 0x61, 0x74, 0x63, 0x68,
+	0x12, 0x11, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x65, 0x71, 0x1a, 0x0f, 0x2e, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x2e, 0x4f, 0x70,
+	0x49, 0x74, 0x65, 0x6d, 0x28, 0x01, 0x30, 0x01, 0x42, 0x1e, 0x5a, 0x1c, 0x73, 0x69, 0x67, 0x73,
+	0x2e, 0x6b, 0x38, 0x73, 0x2e, 0x69, 0x6f, 0x2f, 0x6b, 0x70, 0x6e, 0x67, 0x2f, 0x61, 0x70, 0x69,
+	0x2f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1289,56 +2129,82 @@ func file_api_localv1_api_proto_rawDescGZIP() []byte {
 	return file_api_localv1_api_proto_rawDescData
 }
 
-var file_api_localv1_api_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_api_localv1_api_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_api_localv1_api_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_api_localv1_api_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_api_localv1_api_proto_goTypes = []interface{}{
-	(Set)(0),                 // 0: localv1.Set
-	(Protocol)(0),            // 1: localv1.Protocol
-	(*WatchReq)(nil),         // 2: localv1.WatchReq
-	(*OpItem)(nil),           // 3: localv1.OpItem
-	(*EmptyOp)(nil),          // 4: localv1.EmptyOp
-	(*Ref)(nil),              // 5: localv1.Ref
-	(*Value)(nil),            // 6: localv1.Value
-	(*Service)(nil),          // 7: localv1.Service
-	(*IPFilter)(nil),         // 8: localv1.IPFilter
-	(*ServiceIPs)(nil),       // 9: localv1.ServiceIPs
-	(*Endpoint)(nil),         // 10: localv1.Endpoint
-	(*EndpointScopes)(nil),   // 11: localv1.EndpointScopes
-	(*IPSet)(nil),            // 12: localv1.IPSet
-	(*PortName)(nil),         // 13: localv1.PortName
-	(*PortMapping)(nil),      // 14: localv1.PortMapping
-	(*ClientIPAffinity)(nil), // 15: localv1.ClientIPAffinity
-	nil,                      // 16: localv1.Service.LabelsEntry
-	nil,                      // 17: localv1.Service.AnnotationsEntry
+	(Compression)(0),               // 0: localv1.Compression
+	(Set)(0),                       // 1: localv1.Set
+	(LoadBalancerIPMode)(0),        // 2: localv1.LoadBalancerIPMode
+	(Protocol)(0),                  // 3: localv1.Protocol
+	(*WatchReq)(nil),               // 4: localv1.WatchReq
+	(*BatchingReq)(nil),            // 5: localv1.BatchingReq
+	(*ApplyStatus)(nil),            // 6: localv1.ApplyStatus
+	(*WatchFilter)(nil),            // 7: localv1.WatchFilter
+	(*OpItem)(nil),                 // 8: localv1.OpItem
+	(*Batch)(nil),                  // 9: localv1.Batch
+	(*EmptyOp)(nil),                // 10: localv1.EmptyOp
+	(*SyncOp)(nil),                 // 11: localv1.SyncOp
+	(*Ref)(nil),                    // 12: localv1.Ref
+	(*Value)(nil),                  // 13: localv1.Value
+	(*Service)(nil),                // 14: localv1.Service
+	(*IPFilter)(nil),               // 15: localv1.IPFilter
+	(*ServiceIPs)(nil),             // 16: localv1.ServiceIPs
+	(*LoadBalancerIngress)(nil),    // 17: localv1.LoadBalancerIngress
+	(*LoadBalancerPortStatus)(nil), // 18: localv1.LoadBalancerPortStatus
+	(*Endpoint)(nil),               // 19: localv1.Endpoint
+	(*EndpointScopes)(nil),         // 20: localv1.EndpointScopes
+	(*Node)(nil),                   // 21: localv1.Node
+	(*IPSet)(nil),                  // 22: localv1.IPSet
+	(*PortName)(nil),               // 23: localv1.PortName
+	(*PortMapping)(nil),            // 24: localv1.PortMapping
+	(*ClientIPAffinity)(nil),       // 25: localv1.ClientIPAffinity
+	nil,                            // 26: localv1.Service.LabelsEntry
+	nil,                            // 27: localv1.Service.AnnotationsEntry
+	nil,                            // 28: localv1.Node.LabelsEntry
+	nil,                            // 29: localv1.Node.AnnotationsEntry
 }
 var file_api_localv1_api_proto_depIdxs = []int32{
-	4,  // 0: localv1.OpItem.Sync:type_name -> localv1.EmptyOp
-	4,  // 1: localv1.OpItem.Reset:type_name -> localv1.EmptyOp
-	6,  // 2: localv1.OpItem.Set:type_name -> localv1.Value
-	5,  // 3: localv1.OpItem.Delete:type_name -> localv1.Ref
-	0,  // 4: localv1.Ref.Set:type_name -> localv1.Set
-	5,  // 5: localv1.Value.Ref:type_name -> localv1.Ref
-	16, // 6: localv1.Service.Labels:type_name -> localv1.Service.LabelsEntry
-	17, // 7: localv1.Service.Annotations:type_name -> localv1.Service.AnnotationsEntry
-	9,  // 8: localv1.Service.IPs:type_name -> localv1.ServiceIPs
-	8,  // 9: localv1.Service.IPFilters:type_name -> localv1.IPFilter
-	14, // 10: localv1.Service.Ports:type_name -> localv1.PortMapping
-	15, // 11: localv1.Service.ClientIP:type_name -> localv1.ClientIPAffinity
-	12, // 12: localv1.IPFilter.TargetIPs:type_name -> localv1.IPSet
-	12, // 13: localv1.ServiceIPs.ClusterIPs:type_name -> localv1.IPSet
-	12, // 14: localv1.ServiceIPs.ExternalIPs:type_name -> localv1.IPSet
-	12, // 15: localv1.ServiceIPs.LoadBalancerIPs:type_name -> localv1.IPSet
-	12, // 16: localv1.Endpoint.IPs:type_name -> localv1.IPSet
-	13, // 17: localv1.Endpoint.PortOverrides:type_name -> localv1.PortName
-	11, // 18: localv1.Endpoint.Scopes:type_name -> localv1.EndpointScopes
-	1,  // 19: localv1.PortMapping.Protocol:type_name -> localv1.Protocol
-	2,  // 20: localv1.Sets.Watch:input_type -> localv1.WatchReq
-	3,  // 21: localv1.Sets.Watch:output_type -> localv1.OpItem
-	21, // [21:22] is the sub-list for method output_type
-	20, // [20:21] is the sub-list for method input_type
-	20, // [20:20] is the sub-list for extension type_name
-	20, // [20:20] is the sub-list for extension extendee
-	0,  // [0:20] is the sub-list for field type_name
+	7,  // 0: localv1.WatchReq.Filter:type_name -> localv1.WatchFilter
+	6,  // 1: localv1.WatchReq.Applied:type_name -> localv1.ApplyStatus
+	5,  // 2: localv1.WatchReq.Batching:type_name -> localv1.BatchingReq
+	0,  // 3: localv1.BatchingReq.Compressions:type_name -> localv1.Compression
+	11, // 4: localv1.OpItem.Sync:type_name -> localv1.SyncOp
+	10, // 5: localv1.OpItem.Reset:type_name -> localv1.EmptyOp
+	13, // 6: localv1.OpItem.Set:type_name -> localv1.Value
+	12, // 7: localv1.OpItem.Delete:type_name -> localv1.Ref
+	9,  // 8: localv1.OpItem.Batch:type_name -> localv1.Batch
+	0,  // 9: localv1.Batch.Compression:type_name -> localv1.Compression
+	1,  // 10: localv1.Ref.Set:type_name -> localv1.Set
+	12, // 11: localv1.Value.Ref:type_name -> localv1.Ref
+	26, // 12: localv1.Service.Labels:type_name -> localv1.Service.LabelsEntry
+	27, // 13: localv1.Service.Annotations:type_name -> localv1.Service.AnnotationsEntry
+	16, // 14: localv1.Service.IPs:type_name -> localv1.ServiceIPs
+	15, // 15: localv1.Service.IPFilters:type_name -> localv1.IPFilter
+	24, // 16: localv1.Service.Ports:type_name -> localv1.PortMapping
+	25, // 17: localv1.Service.ClientIP:type_name -> localv1.ClientIPAffinity
+	22, // 18: localv1.IPFilter.TargetIPs:type_name -> localv1.IPSet
+	22, // 19: localv1.ServiceIPs.ClusterIPs:type_name -> localv1.IPSet
+	22, // 20: localv1.ServiceIPs.ExternalIPs:type_name -> localv1.IPSet
+	22, // 21: localv1.ServiceIPs.LoadBalancerIPs:type_name -> localv1.IPSet
+	17, // 22: localv1.ServiceIPs.LoadBalancerIngresses:type_name -> localv1.LoadBalancerIngress
+	2,  // 23: localv1.LoadBalancerIngress.IPMode:type_name -> localv1.LoadBalancerIPMode
+	18, // 24: localv1.LoadBalancerIngress.Ports:type_name -> localv1.LoadBalancerPortStatus
+	3,  // 25: localv1.LoadBalancerPortStatus.Protocol:type_name -> localv1.Protocol
+	22, // 26: localv1.Endpoint.IPs:type_name -> localv1.IPSet
+	23, // 27: localv1.Endpoint.PortOverrides:type_name -> localv1.PortName
+	20, // 28: localv1.Endpoint.Scopes:type_name -> localv1.EndpointScopes
+	28, // 29: localv1.Node.Labels:type_name -> localv1.Node.LabelsEntry
+	29, // 30: localv1.Node.Annotations:type_name -> localv1.Node.AnnotationsEntry
+	22, // 31: localv1.Node.InternalIPs:type_name -> localv1.IPSet
+	22, // 32: localv1.Node.ExternalIPs:type_name -> localv1.IPSet
+	3,  // 33: localv1.PortMapping.Protocol:type_name -> localv1.Protocol
+	4,  // 34: localv1.Sets.Watch:input_type -> localv1.WatchReq
+	8,  // 35: localv1.Sets.Watch:output_type -> localv1.OpItem
+	35, // [35:36] is the sub-list for method output_type
+	34, // [34:35] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
 }
 
 func init() { file_api_localv1_api_proto_init() }
@@ -1360,7 +2226,7 @@ func file_api_localv1_api_proto_init() {
 			}
 		}
 		file_api_localv1_api_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*OpItem); i {
+			switch v := v.(*BatchingReq); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1372,7 +2238,7 @@ func file_api_localv1_api_proto_init() {
 			}
 		}
 		file_api_localv1_api_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EmptyOp); i {
+			switch v := v.(*ApplyStatus); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1384,7 +2250,7 @@ func file_api_localv1_api_proto_init() {
 			}
 		}
 		file_api_localv1_api_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Ref); i {
+			switch v := v.(*WatchFilter); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1396,7 +2262,7 @@ func file_api_localv1_api_proto_init() {
 			}
 		}
 		file_api_localv1_api_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Value); i {
+			switch v := v.(*OpItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1408,7 +2274,7 @@ func file_api_localv1_api_proto_init() {
 			}
 		}
 		file_api_localv1_api_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Service); i {
+			switch v := v.(*Batch); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1420,7 +2286,7 @@ func file_api_localv1_api_proto_init() {
 			}
 		}
 		file_api_localv1_api_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*IPFilter); i {
+			switch v := v.(*EmptyOp); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1432,7 +2298,7 @@ func file_api_localv1_api_proto_init() {
 			}
 		}
 		file_api_localv1_api_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ServiceIPs); i {
+			switch v := v.(*SyncOp); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1444,7 +2310,7 @@ func file_api_localv1_api_proto_init() {
 			}
 		}
 		file_api_localv1_api_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Endpoint); i {
+			switch v := v.(*Ref); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1456,7 +2322,7 @@ func file_api_localv1_api_proto_init() {
 			}
 		}
 		file_api_localv1_api_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*EndpointScopes); i {
+			switch v := v.(*Value); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1468,7 +2334,7 @@ func file_api_localv1_api_proto_init() {
 			}
 		}
 		file_api_localv1_api_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*IPSet); i {
+			switch v := v.(*Service); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1480,7 +2346,7 @@ func file_api_localv1_api_proto_init() {
 			}
 		}
 		file_api_localv1_api_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PortName); i {
+			switch v := v.(*IPFilter); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1492,7 +2358,7 @@ func file_api_localv1_api_proto_init() {
 			}
 		}
 		file_api_localv1_api_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PortMapping); i {
+			switch v := v.(*ServiceIPs); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1504,6 +2370,102 @@ func file_api_localv1_api_proto_init() {
 			}
 		}
 		file_api_localv1_api_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerIngress); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_localv1_api_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LoadBalancerPortStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_localv1_api_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Endpoint); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_localv1_api_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EndpointScopes); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_localv1_api_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Node); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_localv1_api_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IPSet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_localv1_api_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PortName); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_localv1_api_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PortMapping); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_localv1_api_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ClientIPAffinity); i {
 			case 0:
 				return &v.state
@@ -1516,13 +2478,14 @@ func file_api_localv1_api_proto_init() {
 			}
 		}
 	}
-	file_api_localv1_api_proto_msgTypes[1].OneofWrappers = []interface{}{
+	file_api_localv1_api_proto_msgTypes[4].OneofWrappers = []interface{}{
 		(*OpItem_Sync)(nil),
 		(*OpItem_Reset_)(nil),
 		(*OpItem_Set)(nil),
 		(*OpItem_Delete)(nil),
+		(*OpItem_Batch)(nil),
 	}
-	file_api_localv1_api_proto_msgTypes[5].OneofWrappers = []interface{}{
+	file_api_localv1_api_proto_msgTypes[10].OneofWrappers = []interface{}{
 		(*Service_ClientIP)(nil),
 	}
 	type x struct{}
@@ -1530,8 +2493,8 @@ func file_api_localv1_api_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_localv1_api_proto_rawDesc,
-			NumEnums:      2,
-			NumMessages:   16,
+			NumEnums:      4,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,223 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localv1
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultBatchBytes is the size budget of a batch when the requester doesn't set one.
+const DefaultBatchBytes = 1 << 20
+
+type compressor struct {
+	compress   func(data []byte) ([]byte, error)
+	decompress func(data []byte) ([]byte, error)
+}
+
+// compressors are the supported compressions (Uncompressed excluded)
+var compressors = map[Compression]compressor{
+	Compression_Gzip: {gzipCompress, gzipDecompress},
+	Compression_Zstd: {zstdCompress, zstdDecompress},
+}
+
+// CompressionSupported returns true if the compression can be used in batches.
+func CompressionSupported(c Compression) bool {
+	_, ok := compressors[c]
+	return ok || c == Compression_Uncompressed
+}
+
+// ParseCompression parses a compression name (ie: "gzip"), case insensitive.
+func ParseCompression(name string) (c Compression, err error) {
+	for value, valueName := range Compression_name {
+		if strings.EqualFold(name, valueName) {
+			c = Compression(value)
+			if !CompressionSupported(c) {
+				err = fmt.Errorf("unsupported compression: %s", name)
+			}
+			return
+		}
+	}
+
+	err = fmt.Errorf("unknown compression: %s", name)
+	return
+}
+
+// Batcher packs the Set and Delete ops sent to its sink in Batch ops, up to a size budget. The
+// other ops (ie: Sync) flush the pending batch and are sent as is.
+type Batcher struct {
+	Sink        OpSink
+	MaxBytes    int
+	Compression Compression
+
+	buf []byte
+}
+
+var _ OpSink = &Batcher{}
+
+// NewBatcher returns a Batcher to sink, negotiated from the requester's batching request.
+func NewBatcher(sink OpSink, req *BatchingReq) *Batcher {
+	b := &Batcher{
+		Sink:     sink,
+		MaxBytes: int(req.MaxBytes),
+	}
+
+	if b.MaxBytes == 0 {
+		b.MaxBytes = DefaultBatchBytes
+	}
+
+	for _, c := range req.Compressions {
+		if CompressionSupported(c) {
+			b.Compression = c
+			break
+		}
+	}
+
+	return b
+}
+
+// Send packs the op in the current batch, or sends it after flushing the batch.
+func (b *Batcher) Send(op *OpItem) (err error) {
+	switch op.Op.(type) {
+	case *OpItem_Set, *OpItem_Delete:
+		b.buf = protowire.AppendVarint(b.buf, uint64(proto.Size(op)))
+
+		b.buf, err = proto.MarshalOptions{}.MarshalAppend(b.buf, op)
+		if err != nil {
+			return
+		}
+
+		if len(b.buf) >= b.MaxBytes {
+			err = b.Flush()
+		}
+		return
+	}
+
+	if err = b.Flush(); err != nil {
+		return
+	}
+
+	return b.Sink.Send(op)
+}
+
+// Flush sends the pending batch, if any.
+func (b *Batcher) Flush() (err error) {
+	if len(b.buf) == 0 {
+		return
+	}
+
+	ops := b.buf
+	b.buf = nil
+
+	if c, ok := compressors[b.Compression]; ok {
+		ops, err = c.compress(ops)
+		if err != nil {
+			return
+		}
+	}
+
+	return b.Sink.Send(&OpItem{Op: &OpItem_Batch{Batch: &Batch{
+		Compression: b.Compression,
+		Ops:         ops,
+	}}})
+}
+
+// Unpack returns the ops packed in the batch.
+func (b *Batch) Unpack() (ops []*OpItem, err error) {
+	data := b.Ops
+
+	if b.Compression != Compression_Uncompressed {
+		c, ok := compressors[b.Compression]
+		if !ok {
+			return nil, fmt.Errorf("unsupported batch compression: %v", b.Compression)
+		}
+
+		data, err = c.decompress(data)
+		if err != nil {
+			return
+		}
+	}
+
+	for len(data) != 0 {
+		size, n := protowire.ConsumeVarint(data)
+		if n < 0 || uint64(len(data)-n) < size {
+			return nil, errors.New("invalid batch")
+		}
+
+		data = data[n:]
+
+		op := &OpItem{}
+		if err = proto.Unmarshal(data[:size], op); err != nil {
+			return
+		}
+
+		ops = append(ops, op)
+		data = data[size:]
+	}
+
+	return
+}
+
+func gzipCompress(data []byte) ([]byte, error) {
+	out := new(bytes.Buffer)
+
+	w, err := gzip.NewWriterLevel(out, gzip.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = w.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+func gzipDecompress(data []byte) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(r)
+}
+
+// zstd encoders and decoders are safe for concurrent EncodeAll/DecodeAll calls
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest))
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+)
+
+func zstdCompress(data []byte) ([]byte, error) {
+	return zstdEncoder.EncodeAll(data, nil), nil
+}
+
+func zstdDecompress(data []byte) ([]byte, error) {
+	return zstdDecoder.DecodeAll(data, nil)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localv1
+
+import (
+	"fmt"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type opsRecorder []*OpItem
+
+func (r *opsRecorder) Send(op *OpItem) error {
+	*r = append(*r, op)
+	return nil
+}
+
+func TestBatcher(t *testing.T) {
+	for _, compression := range []Compression{Compression_Uncompressed, Compression_Gzip, Compression_Zstd} {
+		t.Run(compression.String(), func(t *testing.T) {
+			sent := &opsRecorder{}
+
+			b := NewBatcher(sent, &BatchingReq{
+				MaxBytes:     1000,
+				Compressions: []Compression{Compression(42), compression}, // unknown compressions are skipped
+			})
+
+			if b.Compression != compression {
+				t.Fatalf("expected %v to be negotiated, got %v", compression, b.Compression)
+			}
+
+			expected := make([]*OpItem, 0)
+			for i := 0; i < 100; i++ {
+				op := &OpItem{Op: &OpItem_Set{Set: &Value{
+					Ref:   &Ref{Set: Set_ServicesSet, Path: fmt.Sprint("default/svc-", i)},
+					Bytes: []byte("some value"),
+				}}}
+				if i%10 == 0 {
+					op = &OpItem{Op: &OpItem_Delete{Delete: &Ref{Set: Set_EndpointsSet, Path: fmt.Sprint("default/svc-", i)}}}
+				}
+
+				expected = append(expected, op)
+
+				if err := b.Send(op); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			sync := &OpItem{Op: &OpItem_Sync{Sync: &SyncOp{Rev: 1}}}
+			expected = append(expected, sync)
+			b.Send(sync)
+
+			received := make([]*OpItem, 0)
+			batches := 0
+			for _, op := range *sent {
+				batch := op.GetBatch()
+				if batch == nil {
+					received = append(received, op)
+					continue
+				}
+
+				batches++
+
+				if batch.Compression != compression {
+					t.Errorf("expected a %v batch, got %v", compression, batch.Compression)
+				}
+
+				ops, err := batch.Unpack()
+				if err != nil {
+					t.Fatal(err)
+				}
+				received = append(received, ops...)
+			}
+
+			if batches < 2 || batches > 10 {
+				t.Errorf("expected the ops to be split in a few batches, got %d", batches)
+			}
+
+			if len(received) != len(expected) {
+				t.Fatalf("expected %d ops, got %d", len(expected), len(received))
+			}
+			for i := range expected {
+				if !proto.Equal(expected[i], received[i]) {
+					t.Errorf("op %d: expected %v, got %v", i, expected[i], received[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseCompression(t *testing.T) {
+	if c, err := ParseCompression("gzip"); err != nil || c != Compression_Gzip {
+		t.Errorf("expected gzip, got %v, %v", c, err)
+	}
+	if c, err := ParseCompression("ZSTD"); err != nil || c != Compression_Zstd {
+		t.Errorf("expected zstd, got %v, %v", c, err)
+	}
+	if _, err := ParseCompression("lz4"); err == nil {
+		t.Error("expected lz4 to be unknown")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"k8s.io/klog/v2"
 	"net"
+	"time"
 )
 
 // AddAddress adds an address to this endpoint, returning the parsed IP. `ìp` will be nil if it couldn't be parsed.
@@ -31,6 +32,14 @@ func (ep *Endpoint) AddAddress(s string) (ip net.IP) {
 	return ep.IPs.Add(s)
 }
 
+// ChangeTriggerTime returns when the change of this endpoint was triggered, or the zero time if unknown.
+func (ep *Endpoint) ChangeTriggerTime() time.Time {
+	if ep.GetLastChangeTriggerTime() == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ep.LastChangeTriggerTime)
+}
+
 func (ep *Endpoint) PortMapping(port *PortMapping) (int32, error) {
 	nameToFind := ""
 	if port.Name != "" {
@@ -0,0 +1,47 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localv1
+
+import "strings"
+
+// IPs returns all the node's addresses (internal and external).
+func (n *Node) IPs() (all *IPSet) {
+	all = NewIPSet()
+	all.AddSet(n.GetInternalIPs())
+	all.AddSet(n.GetExternalIPs())
+	return
+}
+
+// PodCIDRsV4 returns the IPv4 pod CIDRs of the node.
+func (n *Node) PodCIDRsV4() (cidrs []string) {
+	for _, cidr := range n.GetPodCIDRs() {
+		if !strings.Contains(cidr, ":") {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	return
+}
+
+// PodCIDRsV6 returns the IPv6 pod CIDRs of the node.
+func (n *Node) PodCIDRsV6() (cidrs []string) {
+	for _, cidr := range n.GetPodCIDRs() {
+		if strings.Contains(cidr, ":") {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+	return
+}
@@ -24,9 +24,53 @@ func (s *ServiceIPs) All() (all *IPSet) {
 	return
 }
 
+// LoadBalancerVIPs returns the load-balancer IPs the node should capture, excluding
+// the ones of Proxy mode ingresses.
+func (s *ServiceIPs) LoadBalancerVIPs() (ips *IPSet) {
+	ips = NewIPSet()
+
+	proxied := map[string]bool{}
+	for _, ingress := range s.GetLoadBalancerIngresses() {
+		if ingress.IPMode == LoadBalancerIPMode_Proxy {
+			proxied[ingress.IP] = true
+		}
+	}
+
+	for _, ip := range s.GetLoadBalancerIPs().All() {
+		if !proxied[ip] {
+			ips.Add(ip)
+		}
+	}
+
+	return
+}
+
 func (s *ServiceIPs) AllIngress() (all *IPSet) {
 	all = NewIPSet()
 	all.AddSet(s.ExternalIPs)
 	all.AddSet(s.LoadBalancerIPs)
 	return
 }
+
+// Add adds the IPs of other to s.
+func (s *ServiceIPs) Add(other *ServiceIPs) {
+	if other == nil {
+		return
+	}
+
+	addSet := func(dst **IPSet, src *IPSet) {
+		if src == nil || src.IsEmpty() {
+			return
+		}
+		if *dst == nil {
+			*dst = NewIPSet()
+		}
+		(*dst).AddSet(src)
+	}
+
+	addSet(&s.ClusterIPs, other.ClusterIPs)
+	addSet(&s.ExternalIPs, other.ExternalIPs)
+	addSet(&s.LoadBalancerIPs, other.LoadBalancerIPs)
+
+	s.LoadBalancerIngresses = append(s.LoadBalancerIngresses, other.LoadBalancerIngresses...)
+}
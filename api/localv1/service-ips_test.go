@@ -0,0 +1,36 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package localv1
+
+import "fmt"
+
+func ExampleServiceIPs_LoadBalancerVIPs() {
+	ips := &ServiceIPs{
+		LoadBalancerIPs: NewIPSet("192.0.2.1", "192.0.2.2", "2001:db8::1"),
+		LoadBalancerIngresses: []*LoadBalancerIngress{
+			{IP: "192.0.2.1", IPMode: LoadBalancerIPMode_VIP},
+			{IP: "192.0.2.2", IPMode: LoadBalancerIPMode_Proxy},
+			{IP: "2001:db8::1"},
+			{Hostname: "lb.example.com", IPMode: LoadBalancerIPMode_Proxy},
+		},
+	}
+
+	fmt.Println(ips.LoadBalancerVIPs().All())
+
+	// Output:
+	// [192.0.2.1 2001:db8::1]
+}
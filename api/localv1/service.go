@@ -16,6 +16,17 @@ limitations under the License.
 
 package localv1
 
+import "time"
+
 func (s *Service) NamespacedName() string {
 	return s.Namespace + "/" + s.Name
 }
+
+// ChangeTriggerTime returns when the last change of the service's endpoints was triggered, or the
+// zero time if unknown.
+func (s *Service) ChangeTriggerTime() time.Time {
+	if s.GetLastChangeTriggerTime() == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, s.LastChangeTriggerTime)
+}
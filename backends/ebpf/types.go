@@ -111,7 +111,7 @@ type ServicePort interface {
 	NodeLocalInternal() bool
 	// InternalTrafficPolicy returns service InternalTrafficPolicy
 	InternalTrafficPolicy() *v1.ServiceInternalTrafficPolicyType
-	// HintsAnnotation returns the value of the v1.AnnotationTopologyAwareHints annotation.
+	// HintsAnnotation returns the value of the v1.DeprecatedAnnotationTopologyAwareHints annotation.
 	HintsAnnotation() string
 }
 
@@ -257,7 +257,7 @@ func (sct *ebpfController) newBaseServiceInfo(port *localv1.PortMapping, service
 		nodeLocalExternal: nodeLocalExternal,
 		nodeLocalInternal: nodeLocalInternal,
 		// internalTrafficPolicy: service.Spec.InternalTrafficPolicy, //TODO : CHECK InternalTrafficPolicy
-		hintsAnnotation:          service.Annotations[v1.AnnotationTopologyAwareHints],
+		hintsAnnotation:          service.Annotations[v1.DeprecatedAnnotationTopologyAwareHints],
 		loadBalancerSourceRanges: getLoadbalancerSourceRanges(service.IPFilters),
 		loadBalancerIPs:          getLoadBalancerIPs(service.IPs.LoadBalancerIPs, sct.ipFamily),
 		sessionAffinity:          getSessionAffinity(service.SessionAffinity),
@@ -55,10 +55,11 @@ func (b *backend) Callback(ch <-chan *client.ServiceEndpoints) {
 		}
 		key := nsn.Namespace + "/" + nsn.Name + ":" + strconv.Itoa(port)
 
-		// count is the number of local endpoints
+		// count is the number of local endpoints (terminating ones are not counted
+		// so load-balancers can drain this node)
 		count := 0
 		for _, ep := range seps.Endpoints {
-			if ep.Local {
+			if ep.Local && !ep.Terminating {
 				count++
 			}
 		}
@@ -72,6 +72,9 @@ type iptables struct {
 	serviceMap   ServicesSnapshot
 	endpointsMap EndpointsMap
 
+	// syncErr is the error of the last sync, if any
+	syncErr error
+
 	// Since converting probabilities (floats) to strings is expensive
 	// and we are using only probabilities in the format of 1/n, we are
 	// precomputing some number of those and cache for future reuse.
@@ -134,7 +137,9 @@ func (t *iptables) sync() {
 	// even if nothing changed in the meantime. In other words, callers are
 	// responsible for detecting no-op changes and not calling this function.
 	t.serviceMap.Update(t.serviceChanges)
-	endpointUpdateResult := t.endpointsMap.Update(t.endpointsChanges)
+	t.endpointsMap.Update(t.endpointsChanges)
+
+	t.syncErr = nil
 
 	klog.InfoS("Syncing iptables rules")
 
@@ -247,6 +252,7 @@ func (t *iptables) sync() {
 	if err != nil {
 		klog.ErrorS(err, "Failed to execute iptables-restore")
 		IptablesRestoreFailuresTotal.Inc()
+		t.syncErr = fmt.Errorf("iptables-restore failed: %w", err)
 		// Revert new local ports.
 		klog.V(2).InfoS("Closing local ports after iptables-restore failure")
 		RevertPorts(replacementPortsMap, t.portsMap)
@@ -254,14 +260,6 @@ func (t *iptables) sync() {
 	}
 	//	success = true
 
-	for name, lastChangeTriggerTimes := range endpointUpdateResult.LastChangeTriggerTimes {
-		for _, lastChangeTriggerTime := range lastChangeTriggerTimes {
-			latency := SinceInSeconds(lastChangeTriggerTime)
-			NetworkProgrammingLatency.Observe(latency)
-			klog.V(4).InfoS("Network programming", "endpoint", klog.KRef(name.Namespace, name.Name), "elapsed", latency)
-		}
-	}
-
 	// Close old local ports and save new ones.
 	for k, v := range t.portsMap {
 		if replacementPortsMap[k] == nil {
@@ -176,9 +176,9 @@ func (sct *ServiceChangeTracker) newBaseServiceInfo(port *localv1.PortMapping, s
 		nodeLocalExternal: nodeLocalExternal,
 		nodeLocalInternal: nodeLocalInternal,
 		// internalTrafficPolicy: service.Spec.InternalTrafficPolicy, //TODO : CHECK InternalTrafficPolicy
-		hintsAnnotation:          service.Annotations[v1.AnnotationTopologyAwareHints],
+		hintsAnnotation:          service.Annotations[v1.DeprecatedAnnotationTopologyAwareHints],
 		loadBalancerSourceRanges: getLoadbalancerSourceRanges(service.IPFilters),
-		loadBalancerIPs:          getLoadBalancerIPs(service.IPs.LoadBalancerIPs, sct.ipFamily),
+		loadBalancerIPs:          getLoadBalancerIPs(service.IPs.LoadBalancerVIPs(), sct.ipFamily),
 		sessionAffinity:          getSessionAffinity(service.SessionAffinity),
 	}
 
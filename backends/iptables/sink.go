@@ -17,7 +17,9 @@ limitations under the License.
 package iptables
 
 import (
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/spf13/pflag"
 	v1 "k8s.io/api/core/v1"
@@ -30,30 +32,44 @@ import (
 	"sigs.k8s.io/kpng/client/localsink/filterreset"
 	"sigs.k8s.io/kpng/client/localsink/filterreset/pipe"
 	"sigs.k8s.io/kpng/client/plugins/conntrack"
+	"sigs.k8s.io/kpng/client/plugins/externalname"
+	"sigs.k8s.io/kpng/client/plugins/proglatency"
 )
 
 type Backend struct {
 	localsink.Config
+	externalNames externalname.Config
+
+	// latency tracks the endpoint changes waiting to be programmed
+	latency *proglatency.Tracker
 }
 
 var wg = sync.WaitGroup{}
 var IptablesImpl map[v1.IPFamily]*iptables
 var hostname string
-var _ decoder.Interface = &Backend{}
+var (
+	_ decoder.Interface         = &Backend{}
+	_ decoder.SyncErrorReporter = &Backend{}
+)
 
 func New() *Backend {
 	return &Backend{}
 }
 
 func (s *Backend) Sink() localsink.Sink {
-	return filterreset.New(pipe.New(decoder.New(s), decoder.New(conntrack.NewSink())))
+	return filterreset.New(pipe.New(decoder.New(s.externalNames.WrapDecoder(s)), decoder.New(conntrack.NewSink())))
 }
 
 func (s *Backend) BindFlags(flags *pflag.FlagSet) {
+	s.externalNames.BindFlags(flags)
 }
 
 func (s *Backend) Setup() {
 	hostname = s.NodeName
+	RegisterMetrics()
+	s.latency = proglatency.New()
+	// kube-proxy's network_programming_duration_seconds, as this backend always exposed it
+	s.latency.Observe = func(latency time.Duration) { NetworkProgrammingLatency.Observe(latency.Seconds()) }
 	IptablesImpl = make(map[v1.IPFamily]*iptables)
 	for _, protocol := range []v1.IPFamily{v1.IPv4Protocol, v1.IPv6Protocol} {
 		iptable := NewIptables()
@@ -72,9 +88,26 @@ func (s *Backend) Sync() {
 		go impl.sync()
 	}
 	wg.Wait()
+
+	// on failure, the changes are kept to be observed once a later sync applies them
+	if s.SyncError() == nil {
+		s.latency.Applied()
+	}
+}
+
+// SyncError returns the error of the last Sync, if any family failed to apply.
+func (s *Backend) SyncError() error {
+	for family, impl := range IptablesImpl {
+		if impl.syncErr != nil {
+			return fmt.Errorf("%s: %w", family, impl.syncErr)
+		}
+	}
+	return nil
 }
 
 func (s *Backend) SetService(svc *localv1.Service) {
+	s.latency.AddService(svc)
+
 	for _, impl := range IptablesImpl {
 		impl.serviceChanges.Update(svc)
 	}
@@ -87,6 +120,8 @@ func (s *Backend) DeleteService(namespace, name string) {
 }
 
 func (s *Backend) SetEndpoint(namespace, serviceName, key string, endpoint *localv1.Endpoint) {
+	s.latency.Add(endpoint)
+
 	for _, impl := range IptablesImpl {
 		impl.endpointsChanges.EndpointUpdate(namespace, serviceName, key, endpoint)
 	}
@@ -33,10 +33,31 @@ func getExternalIPs(service *localv1.Service, ipFamily v1.IPFamily) []string {
 	return IPs
 }
 
+// localNode is the node we're running on, as known by the cluster.
+var localNode *localv1.Node
+
+func setLocalNode(node *localv1.Node) {
+	localNode = node
+}
+
+// nodeAddresses returns the addresses given by flag, or the Node addresses from the cluster,
+// or the local interfaces addresses.
+func nodeAddresses() []string {
+	if len(*NodeAddresses) != 0 {
+		return *NodeAddresses
+	}
+
+	if ips := localNode.IPs(); !ips.IsEmpty() {
+		return ips.All()
+	}
+
+	return interfaceAddresses()
+}
+
 // getNodeIPs safely returns all Node IPs for given IPFamily.
 func getNodeIPs(ipFamily v1.IPFamily) []string {
 	IPs := make([]string, 0)
-	for _, ip := range *NodeAddresses {
+	for _, ip := range nodeAddresses() {
 		if ipFamily == v1.IPv4Protocol && netutils.IsIPv4String(ip) {
 			IPs = append(IPs, ip)
 		} else if ipFamily == v1.IPv6Protocol && netutils.IsIPv6String(ip) {
@@ -50,10 +71,12 @@ func getNodeIPs(ipFamily v1.IPFamily) []string {
 func getLoadBalancerIPs(service *localv1.Service, ipFamily v1.IPFamily) []string {
 	IPs := make([]string, 0)
 	if service.IPs.LoadBalancerIPs != nil {
+		// Proxy mode IPs must reach the load-balancer, not be captured here
+		vips := service.IPs.LoadBalancerVIPs()
 		if ipFamily == v1.IPv4Protocol {
-			return service.IPs.LoadBalancerIPs.GetV4()
+			return vips.GetV4()
 		} else if ipFamily == v1.IPv6Protocol {
-			return service.IPs.LoadBalancerIPs.GetV6()
+			return vips.GetV6()
 		}
 	}
 	return IPs
@@ -11,9 +11,11 @@ import (
 	"sigs.k8s.io/kpng/backends/ipvs/internal/iptables"
 	"sigs.k8s.io/kpng/backends/ipvs/internal/ipvs"
 	"sigs.k8s.io/kpng/client"
+	"sigs.k8s.io/kpng/client/plugins/proglatency"
 
 	"k8s.io/klog/v2"
 
+	"errors"
 	"sync"
 	"time"
 )
@@ -27,6 +29,9 @@ type Controller struct {
 	ipvsManager   *ipvs.Manager
 	ipsetsManager *ipsets.Manager
 	iptManager    *iptables.Manager
+
+	// latency tracks the endpoint changes waiting to be programmed
+	latency *proglatency.Tracker
 }
 
 const (
@@ -42,6 +47,8 @@ func newController() Controller {
 		ipsetsManager: ipsets.NewManager(),
 
 		iptManager: iptables.NewManager(),
+
+		latency: proglatency.New(),
 	}
 }
 
@@ -74,7 +81,8 @@ func (c *Controller) ServeProxyMode(errCh chan error) {
 	go wait.Until(fn, 5*time.Second, wait.NeverStop)
 }
 
-func (c *Controller) Callback(ch <-chan *client.ServiceEndpoints) {
+// Callback applies the state, reporting apply errors to failed (see fullstate.Sink.Failed).
+func (c *Controller) Callback(ch <-chan *client.ServiceEndpoints, failed func(error)) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -88,6 +96,11 @@ func (c *Controller) Callback(ch <-chan *client.ServiceEndpoints) {
 	// to program the kernel, diffstores will maintain state and only create, update
 	// and delete those which are required.
 	for serviceEndpoints := range ch {
+		c.latency.AddService(serviceEndpoints.Service)
+		for _, ep := range serviceEndpoints.Endpoints {
+			c.latency.Add(ep)
+		}
+
 		switch serviceEndpoints.Service.Type {
 		case ClusterIPService.String():
 			c.addServiceEndpointsForClusterIP(serviceEndpoints)
@@ -105,8 +118,14 @@ func (c *Controller) Callback(ch <-chan *client.ServiceEndpoints) {
 
 	// execute the changes; this call will have actual side effects,
 	// kernel will be programed to achieve the desired data path.
-	c.ipvsManager.Apply()
-	c.ipsetsManager.Apply()
+	ipvsErr := c.ipvsManager.Apply()
+	ipsetsErr := c.ipsetsManager.Apply()
+
+	if err := errors.Join(ipvsErr, ipsetsErr); err != nil {
+		failed(err)
+	} else {
+		c.latency.Applied()
+	}
 
 	// reset the diffstore
 	c.ipvsManager.Reset()
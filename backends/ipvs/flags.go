@@ -13,7 +13,7 @@ var (
 	//TODO: implement dry run
 
 	DryRun                = BackendFlags.Bool("dry-run", false, "dry run (print instead of applying)")
-	NodeAddresses         = BackendFlags.StringArray("node-address", interfaceAddresses(), "A comma-separated list of IPs to associate when using NodePort type. Defaults to all the Node addresses")
+	NodeAddresses         = BackendFlags.StringArray("node-address", nil, "A comma-separated list of IPs to associate when using NodePort type. Defaults to the Node addresses known by the cluster, or all the interfaces addresses if unknown")
 	IPVSSchedulingMethod  = BackendFlags.String("scheduling-method", "rr", "Algorithm for allocating TCP conn & UDP datagrams to real servers. Values: rr,wrr,lc,wlc,lblc,lblcr,dh,sh,seq,nq")
 	IPVSDestinationWeight = BackendFlags.Int32("weight", 1, "An integer specifying the capacity of server relative to others in the pool")
 	// MasqueradeAll
@@ -3,6 +3,7 @@ package ipsets
 import (
 	"k8s.io/klog/v2"
 	"k8s.io/utils/exec"
+	"sigs.k8s.io/kpng/backends/ipvs/internal/apply"
 	"sigs.k8s.io/kpng/client/diffstore"
 )
 
@@ -68,8 +69,10 @@ func (m *Manager) Done() {
 
 // Apply has side effects. Apply should be called after processing fullstate callback, done will iterate
 // over changes from all the diffstores and create, update and delete required objects accordingly.
-func (m *Manager) Apply() {
+// It returns an error if any change failed.
+func (m *Manager) Apply() error {
 	var err error
+	var errs []error
 	var valid bool
 
 	// add new entries to ipsets here.
@@ -87,6 +90,7 @@ func (m *Manager) Apply() {
 		klog.V(4).Infof("adding entry [%s] to set [%s]", entry.String(), entry.set.GetName())
 		err = entry.set.addEntry(entry)
 		if err != nil {
+			errs = append(errs, err)
 			klog.V(2).ErrorS(err, "failed to add entry to set",
 				"entry", entry.String(), "set", entry.set.GetName())
 		}
@@ -107,8 +111,11 @@ func (m *Manager) Apply() {
 		klog.V(4).Infof("removing entry [%s] from set [%s]", entry.String(), entry.set.GetName())
 		err = entry.set.delEntry(entry)
 		if err != nil {
+			errs = append(errs, err)
 			klog.V(2).ErrorS(err, "failed to remove entry from set",
 				"entry", entry.String(), "set", entry.set.GetName())
 		}
 	}
+
+	return apply.Error("ipset", errs)
 }
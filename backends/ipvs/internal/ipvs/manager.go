@@ -8,6 +8,7 @@ import (
 	"k8s.io/klog/v2"
 	netutils "k8s.io/utils/net"
 	"net"
+	"sigs.k8s.io/kpng/backends/ipvs/internal/apply"
 	"sigs.k8s.io/kpng/client/diffstore"
 )
 
@@ -99,8 +100,10 @@ func (m *Manager) Done() {
 
 // Apply has side effects. Apply should be called after processing fullstate callback, done will iterate
 // over changes from all the diffstores and create, update and delete required objects accordingly.
-func (m *Manager) Apply() {
+// It returns an error if any change failed.
+func (m *Manager) Apply() error {
 	var err error
+	var errs []error
 
 	// unbind IPs from the network interface.
 	for _, item := range m.ipBindStore.Deleted() {
@@ -109,6 +112,7 @@ func (m *Manager) Apply() {
 		err = m.unbindIpFromInterface(ip)
 
 		if err != nil {
+			errs = append(errs, err)
 			klog.V(2).ErrorS(err, "failed to remove IP from interface",
 				"ip", ip, "interface", m.ipInterface)
 		}
@@ -128,6 +132,7 @@ func (m *Manager) Apply() {
 		)
 
 		if err != nil {
+			errs = append(errs, err)
 			klog.V(2).ErrorS(err, "failed to remove destination from server",
 				"server", virtualServer.IPPort(), "destination", destination.IPPort())
 		}
@@ -141,6 +146,7 @@ func (m *Manager) Apply() {
 		err = IPVSLib.DeleteService(virtualServer.asIPVSLibService(m.schedulingMethod))
 
 		if err != nil {
+			errs = append(errs, err)
 			klog.V(2).ErrorS(err, "failed to delete server", "server", virtualServer.IPPort())
 		}
 	}
@@ -156,6 +162,7 @@ func (m *Manager) Apply() {
 			err = IPVSLib.AddService(virtualServer.asIPVSLibService(m.schedulingMethod))
 
 			if err != nil {
+				errs = append(errs, err)
 				klog.V(2).ErrorS(err, "failed to create server", "server", virtualServer.IPPort())
 			}
 		} else if item.Updated() {
@@ -166,6 +173,7 @@ func (m *Manager) Apply() {
 			err = IPVSLib.UpdateService(virtualServer.asIPVSLibService(m.schedulingMethod))
 
 			if err != nil {
+				errs = append(errs, err)
 				klog.V(2).ErrorS(err, "failed to update server", "server", virtualServer.IPPort())
 			}
 
@@ -188,6 +196,7 @@ func (m *Manager) Apply() {
 			)
 
 			if err != nil {
+				errs = append(errs, err)
 				klog.V(2).ErrorS(err, "failed to add destination to server",
 					"server", virtualServer.IPPort(), "destination", destination.IPPort())
 			}
@@ -202,6 +211,7 @@ func (m *Manager) Apply() {
 			)
 
 			if err != nil {
+				errs = append(errs, err)
 				klog.V(2).ErrorS(err, "failed to update destination of server",
 					"server", virtualServer.IPPort(), "destination", destination.IPPort())
 			}
@@ -216,11 +226,13 @@ func (m *Manager) Apply() {
 		err = m.bindIpToInterface(ip)
 
 		if err != nil {
+			errs = append(errs, err)
 			klog.V(2).ErrorS(err, "failed to add ip to interface",
 				"ip", ip, "interface", m.ipInterface)
 		}
 	}
 
+	return apply.Error("IPVS", errs)
 }
 
 func asDummyIP(ip string) string {
@@ -25,12 +25,15 @@ import (
 	"sigs.k8s.io/kpng/client/localsink/fullstate"
 	"sigs.k8s.io/kpng/client/localsink/fullstate/fullstatepipe"
 	"sigs.k8s.io/kpng/client/plugins/conntrack"
+	"sigs.k8s.io/kpng/client/plugins/externalname"
+	"sigs.k8s.io/kpng/client/plugins/proglatency"
 )
 
 var controller Controller
 
 type backend struct {
-	cfg localsink.Config
+	cfg           localsink.Config
+	externalNames externalname.Config
 }
 
 func init() {
@@ -50,6 +53,7 @@ func init() {
 
 func (b *backend) BindFlags(flags *pflag.FlagSet) {
 	b.cfg.BindFlags(flags)
+	b.externalNames.BindFlags(flags)
 	BindFlags(flags)
 }
 
@@ -60,15 +64,18 @@ func (b *backend) Sync() { /* no-op */ }
 func (b *backend) Sink() localsink.Sink {
 	sink := fullstate.New(&b.cfg)
 
+	proglatency.RegisterMetrics()
+
 	// client will invoke Setup()
 	sink.SetupFunc = b.Setup
+	sink.NodeCallback = setLocalNode
 
 	ct := conntrack.New()
 
-	sink.Callback = fullstatepipe.New(fullstatepipe.ParallelSendSequenceClose,
-		controller.Callback,
+	sink.Callback = b.externalNames.Wrap(fullstatepipe.New(fullstatepipe.ParallelSendSequenceClose,
+		func(ch <-chan *fullstate.ServiceEndpoints) { controller.Callback(ch, sink.Failed) },
 		ct.Callback,
-	).Callback
+	).Callback)
 
 	return sink
 }
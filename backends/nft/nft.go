@@ -31,7 +31,9 @@ import (
 	"github.com/spf13/pflag"
 	"k8s.io/klog/v2"
 
+	"sigs.k8s.io/kpng/api/localv1"
 	"sigs.k8s.io/kpng/client"
+	"sigs.k8s.io/kpng/client/plugins/proglatency"
 )
 
 var (
@@ -50,11 +52,23 @@ var (
 	clusterCIDRsV4   []string
 	clusterCIDRsV6   []string
 
+	detectLocalMode = flag.String("detect-local-mode", detectLocalClusterCIDR, "how to detect local traffic that should not be masqueraded: "+
+		detectLocalClusterCIDR+" (from --cluster-cidrs) or "+detectLocalNodeCIDR+" (from the node's pod CIDRs given by the server)")
+	localNode *localv1.Node
+
 	fullResync = true
 
+	// latency tracks the changes applied, for the network programming latency
+	latency = proglatency.New()
+
 	hasNFTHashBug = false
 )
 
+const (
+	detectLocalClusterCIDR = "ClusterCIDR"
+	detectLocalNodeCIDR    = "NodeCIDR"
+)
+
 func BindFlags(flags *pflag.FlagSet) {
 	flags.AddFlagSet(flag)
 }
@@ -87,9 +101,34 @@ func PreRun() {
 
 	klog.Info("cluster CIDRs V4: ", clusterCIDRsV4)
 	klog.Info("cluster CIDRs V6: ", clusterCIDRsV6)
+
+	switch *detectLocalMode {
+	case detectLocalClusterCIDR, detectLocalNodeCIDR:
+	default:
+		klog.Fatalf("invalid detect-local-mode: %q", *detectLocalMode)
+	}
+}
+
+// SetNode records the node we're running on, as known by the cluster.
+func SetNode(node *localv1.Node) {
+	localNode = node
 }
 
-func Callback(ch <-chan *client.ServiceEndpoints) {
+// localCIDRs returns the CIDRs of the traffic that should not be masqueraded.
+func localCIDRs() (v4, v6 []string) {
+	if *detectLocalMode == detectLocalNodeCIDR {
+		if len(localNode.GetPodCIDRs()) != 0 {
+			return localNode.PodCIDRsV4(), localNode.PodCIDRsV6()
+		}
+
+		klog.Warning("node pod CIDRs not known, using cluster CIDRs")
+	}
+
+	return clusterCIDRsV4, clusterCIDRsV6
+}
+
+// Callback applies the state, reporting apply errors to failed (see fullstate.Sink.Failed).
+func Callback(ch <-chan *client.ServiceEndpoints, failed func(error)) {
 	svcCount := 0
 	epCount := 0
 
@@ -101,9 +140,11 @@ func Callback(ch <-chan *client.ServiceEndpoints) {
 	defer table4.Reset()
 	defer table6.Reset()
 
+	cidrsV4, cidrsV6 := localCIDRs()
+
 	renderContexts := []*renderContext{
-		newRenderContext(table4, clusterCIDRsV4, net.CIDRMask(*splitBits, 32)),
-		newRenderContext(table6, clusterCIDRsV6, net.CIDRMask(*splitBits6, 128)),
+		newRenderContext(table4, cidrsV4, net.CIDRMask(*splitBits, 32)),
+		newRenderContext(table6, cidrsV6, net.CIDRMask(*splitBits6, 128)),
 	}
 
 	for serviceEndpoints := range ch {
@@ -115,6 +156,11 @@ func Callback(ch <-chan *client.ServiceEndpoints) {
 
 		svcCount++
 
+		latency.AddService(serviceEndpoints.Service)
+		for _, ep := range serviceEndpoints.Endpoints {
+			latency.Add(ep)
+		}
+
 		for _, ctx := range renderContexts {
 			ctx.addServiceEndpoints(serviceEndpoints)
 			epCount += ctx.epCount
@@ -128,6 +174,7 @@ func Callback(ch <-chan *client.ServiceEndpoints) {
 	// check if we have changes to apply
 	if !fullResync && !table4.Changed() && !table6.Changed() {
 		klog.V(1).Info("no changes to apply")
+		latency.Applied()
 		return
 	}
 
@@ -143,6 +190,7 @@ func Callback(ch <-chan *client.ServiceEndpoints) {
 	if *dryRun {
 		io.Copy(ioutil.Discard, cmdIn)
 		klog.Info("not running nft (dry run mode)")
+		latency.Failed() // nothing applied
 	} else {
 		cmd := exec.Command("nft", "-f", "-")
 		cmd.Stdin = cmdIn
@@ -155,6 +203,8 @@ func Callback(ch <-chan *client.ServiceEndpoints) {
 
 		if err != nil {
 			klog.Errorf("nft failed: %v (%s)", err, elapsed)
+			failed(fmt.Errorf("nft failed: %w", err))
+			latency.Failed()
 
 			// ensure render is finished
 			io.Copy(ioutil.Discard, cmdIn)
@@ -169,6 +219,7 @@ func Callback(ch <-chan *client.ServiceEndpoints) {
 		}
 
 		klog.V(1).Infof("nft ok (%s)", elapsed)
+		latency.Applied()
 
 		if deferred.Len() != 0 {
 			klog.V(1).Infof("running deferred nft actions")
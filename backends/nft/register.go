@@ -24,10 +24,13 @@ import (
 	"sigs.k8s.io/kpng/client/localsink/fullstate"
 	"sigs.k8s.io/kpng/client/localsink/fullstate/fullstatepipe"
 	"sigs.k8s.io/kpng/client/plugins/conntrack"
+	"sigs.k8s.io/kpng/client/plugins/externalname"
+	"sigs.k8s.io/kpng/client/plugins/proglatency"
 )
 
 type backend struct {
-	cfg localsink.Config
+	cfg           localsink.Config
+	externalNames externalname.Config
 }
 
 func init() {
@@ -36,6 +39,7 @@ func init() {
 
 func (b *backend) BindFlags(flags *pflag.FlagSet) {
 	b.cfg.BindFlags(flags)
+	b.externalNames.BindFlags(flags)
 	BindFlags(flags)
 }
 
@@ -44,11 +48,14 @@ func (b *backend) Sink() localsink.Sink {
 
 	PreRun()
 
+	sink.NodeCallback = SetNode
+	proglatency.RegisterMetrics()
+
 	ct := conntrack.New()
-	sink.Callback = fullstatepipe.New(fullstatepipe.ParallelSendSequenceClose,
-		Callback,
+	sink.Callback = b.externalNames.Wrap(fullstatepipe.New(fullstatepipe.ParallelSendSequenceClose,
+		func(ch <-chan *fullstate.ServiceEndpoints) { Callback(ch, sink.Failed) },
 		ct.Callback,
-	).Callback
+	).Callback)
 
 	return sink
 }
@@ -96,6 +96,8 @@ func (ctx *renderContext) addServiceEndpoints(serviceEndpoints *fullstate.Servic
 		allSvcIPs.AddSet(svc.IPs.ClusterIPs)
 	}
 	allSvcIPs.AddSet(svc.IPs.ExternalIPs)
+	// Proxy mode load-balancer IPs must reach the load-balancer, not be captured here
+	allSvcIPs.AddSet(svc.IPs.LoadBalancerVIPs())
 
 	ips := table.IPsFromSet(allSvcIPs)
 
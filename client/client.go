@@ -20,20 +20,20 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
-
-	// allow multi gRPC URLs
-	//_ "github.com/Jille/grpc-multi-resolver"
+	"google.golang.org/grpc/keepalive"
 
 	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/kpng/api/localv1"
 	"sigs.k8s.io/kpng/client/localsink"
 	"sigs.k8s.io/kpng/client/localsink/fullstate"
+	"sigs.k8s.io/kpng/client/statedigest"
 	"sigs.k8s.io/kpng/client/tlsflags"
 )
 
@@ -54,7 +54,8 @@ func New(flags FlagSet) (lc *LocalClient) {
 
 // LocalClient is a simple client to kube-proxy's Endpoints API.
 type LocalClient struct {
-	// Target is the gRPC dial target
+	// Target is the gRPC dial target. It can be a comma-separated list of targets, to fail over
+	// between server replicas.
 	Target string
 
 	TLS *tlsflags.Flags
@@ -65,21 +66,37 @@ type LocalClient struct {
 	// GRPCBuffer is the max size of a gRPC message
 	MaxMsgSize int
 
+	// KeepaliveTime is the idle duration after which the server is pinged (0 to disable)
+	KeepaliveTime time.Duration
+
+	// BatchBytes is the size budget of the batches requested to the server (0 to receive the
+	// ops one by one)
+	BatchBytes int
+	// BatchCompression is the compression of the batches (ie: "gzip", "zstd" or "none")
+	BatchCompression string
+
 	Sink localsink.Sink
 
+	current  int // index of the current target
 	conn     *grpc.ClientConn
 	watch    localv1.Sets_WatchClient
 	watchReq *localv1.WatchReq
 
+	// digest of the sink's state, so the server can resume the watch on reconnect
+	digest *statedigest.Digest
+
 	ctx    context.Context
 	cancel func()
 }
 
 // DefaultFlags registers this client's values to the standard flags.
 func (lc *LocalClient) DefaultFlags(flags FlagSet) {
-	flags.StringVar(&lc.Target, "api", "127.0.0.1:12090", "API to reach (can use multi:///1.0.0.1:1234,1.0.0.2:1234)")
+	flags.StringVar(&lc.Target, "api", "127.0.0.1:12090", "API to reach (several comma-separated targets to fail over between server replicas, ie: 10.0.0.1:12090,10.0.0.2:12090)")
 	flags.DurationVar(&lc.ErrorDelay, "error-delay", 1*time.Second, "duration to wait before retrying after errors")
 	flags.IntVar(&lc.MaxMsgSize, "max-msg-size", 4<<20, "max gRPC message size")
+	flags.DurationVar(&lc.KeepaliveTime, "keepalive-time", 0, "ping the server after this idle duration, to detect dead connections (0 to disable; must not be lower than the server's --keepalive-min-time)")
+	flags.IntVar(&lc.BatchBytes, "batch-bytes", 0, "ask the server to pack the ops in batches of this size, before compression (0 to disable; must be lower than --max-msg-size)")
+	flags.StringVar(&lc.BatchCompression, "batch-compression", "gzip", "compression of the batches (gzip, zstd or none)")
 
 	lc.TLS.Bind(flags, "")
 }
@@ -87,6 +104,10 @@ func (lc *LocalClient) DefaultFlags(flags FlagSet) {
 // Next sends the next diff to the sink, waiting for a new revision as needed.
 // It's designed to never fail, unless canceled.
 func (lc *LocalClient) Next() (canceled bool) {
+	if lc.digest == nil {
+		lc.digest = statedigest.New()
+	}
+
 	if lc.watch == nil {
 		lc.dial()
 	}
@@ -105,7 +126,11 @@ retry:
 	}
 
 	err = lc.watch.Send(&localv1.WatchReq{
-		NodeName: nodeName,
+		NodeName:    nodeName,
+		StateDigest: lc.digest.Sum(),
+		Filter:      localsink.WatchFilter(lc.Sink),
+		Applied:     localsink.ApplyStatus(lc.Sink),
+		Batching:    lc.batching(),
 	})
 	if err != nil {
 		lc.postError()
@@ -121,15 +146,64 @@ retry:
 			goto retry
 		}
 
-		// pass the op to the sync
-		lc.Sink.Send(op)
+		batch := op.GetBatch()
+		if batch == nil {
+			if lc.handle(op) {
+				return
+			}
+			continue
+		}
 
-		// break on sync
-		switch v := op.Op; v.(type) {
-		case *localv1.OpItem_Sync:
-			return
+		ops, err := batch.Unpack()
+		if err != nil {
+			klog.Error("invalid batch: ", err)
+			lc.postError()
+			goto retry
 		}
+
+		for _, op := range ops {
+			if lc.handle(op) {
+				return
+			}
+		}
+	}
+}
+
+// handle passes the op to the sink, and returns true if it's the end of the change set.
+func (lc *LocalClient) handle(op *localv1.OpItem) (synced bool) {
+	lc.digest.Apply(op)
+
+	// the server couldn't resume from our state
+	if _, isReset := op.Op.(*localv1.OpItem_Reset_); isReset {
+		lc.Sink.Reset()
 	}
+
+	// pass the op to the sync
+	lc.Sink.Send(op)
+
+	// break on sync
+	_, synced = op.Op.(*localv1.OpItem_Sync)
+	return
+}
+
+// batching returns the batching request to the server, if enabled.
+func (lc *LocalClient) batching() *localv1.BatchingReq {
+	if lc.BatchBytes <= 0 {
+		return nil
+	}
+
+	req := &localv1.BatchingReq{MaxBytes: uint32(lc.BatchBytes)}
+
+	if name := lc.BatchCompression; name != "" && name != "none" {
+		c, err := localv1.ParseCompression(name)
+		if err != nil {
+			klog.Warning("not compressing the batches: ", err)
+		} else {
+			req.Compressions = []localv1.Compression{c}
+		}
+	}
+
+	return req
 }
 
 // Cancel will cancel this client, quickly closing any call to Next.
@@ -163,13 +237,21 @@ func (lc *LocalClient) Context() context.Context {
 }
 
 func (lc *LocalClient) DialContext(ctx context.Context) (conn *grpc.ClientConn, err error) {
-	klog.Info("connecting to ", lc.Target)
+	target := lc.target()
+	klog.Info("connecting to ", target)
 
 	opts := append(
 		make([]grpc.DialOption, 0),
 		grpc.WithMaxMsgSize(lc.MaxMsgSize),
 	)
 
+	if lc.KeepaliveTime != 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                lc.KeepaliveTime,
+			PermitWithoutStream: true,
+		}))
+	}
+
 	tlsCfg := lc.TLS.Config()
 	if tlsCfg == nil {
 		opts = append(opts, grpc.WithInsecure())
@@ -177,7 +259,7 @@ func (lc *LocalClient) DialContext(ctx context.Context) (conn *grpc.ClientConn,
 		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
 	}
 
-	return grpc.DialContext(lc.ctx, lc.Target, opts...)
+	return grpc.DialContext(lc.ctx, target, opts...)
 }
 
 func (lc *LocalClient) Dial() (conn *grpc.ClientConn, err error) {
@@ -197,7 +279,7 @@ retry:
 		return true
 	} else if err != nil {
 		//klog.Info("failed to connect: ", err)
-		lc.errorSleep()
+		lc.failover()
 		goto retry
 	}
 
@@ -208,11 +290,11 @@ retry:
 		conn.Close()
 
 		//klog.Info("failed to start watch: ", err)
-		lc.errorSleep()
+		lc.failover()
 		goto retry
 	}
 
-	lc.Sink.Reset()
+	// no sink reset here, the server sends one if it can't resume from the sink's state
 
 	//klog.V(1).Info("connected")
 	return false
@@ -237,6 +319,26 @@ func (lc *LocalClient) postError() {
 		return
 	}
 
-	lc.errorSleep()
+	lc.failover()
 	lc.dial()
 }
+
+func (lc *LocalClient) targets() []string {
+	return strings.Split(lc.Target, ",")
+}
+
+func (lc *LocalClient) target() string {
+	targets := lc.targets()
+	return strings.TrimSpace(targets[lc.current%len(targets)])
+}
+
+// failover switches to the next target, after the error delay if every target has been tried.
+// The server resumes the watch from the sink's state digest, so switching between replicas
+// with the same state is transparent to the sink.
+func (lc *LocalClient) failover() {
+	lc.current = (lc.current + 1) % len(lc.targets())
+
+	if lc.current == 0 {
+		lc.errorSleep()
+	}
+}
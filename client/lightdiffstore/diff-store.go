@@ -132,6 +132,16 @@ func (s *DiffStore) Deleted() (deleted []KV) {
 	return
 }
 
+// All returns all the entries, whatever their state.
+func (s *DiffStore) All() (all []KV) {
+	s.tree.Ascend(func(i btree.Item) bool {
+		v := i.(*storeKV)
+		all = append(all, KV{v.key, v.value})
+		return true
+	})
+	return
+}
+
 // GetByPrefix returns all the entries with the given prefix.
 func (s *DiffStore) GetByPrefix(prefix []byte) (items []KV) {
 	s.tree.AscendGreaterOrEqual(&storeKV{key: prefix}, func(i btree.Item) bool {
@@ -18,6 +18,8 @@ package decoder
 
 import (
 	"strings"
+	"sync"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 
@@ -39,6 +41,21 @@ type EndpointsListener interface {
 	DeleteEndpoint(namespace, serviceName, key string)
 }
 
+// NodeListener can be implemented by an Interface to receive the node it's running for.
+type NodeListener interface {
+	// SetNode is called when the node is added or updated
+	SetNode(node *localv1.Node)
+	// DeleteNode is called when the node is deleted
+	DeleteNode(name string)
+}
+
+// SyncErrorReporter can be implemented by an Interface to report the error of its last Sync, so
+// the Sink reports apply statuses to the server.
+type SyncErrorReporter interface {
+	// SyncError returns the error of the last Sync (nil if it succeeded).
+	SyncError() error
+}
+
 type Interface interface {
 	// Sync signals an stream sync event
 	Sync()
@@ -63,12 +80,26 @@ type Interface interface {
 
 type Sink struct {
 	Interface
+
+	statusLock sync.Mutex
+	status     *localv1.ApplyStatus
 }
 
-var _ localsink.Sink = &Sink{}
+var (
+	_ localsink.Sink           = &Sink{}
+	_ localsink.StatusReporter = &Sink{}
+)
 
 func New(iface Interface) *Sink {
-	return &Sink{iface}
+	return &Sink{Interface: iface}
+}
+
+// ApplyStatus returns the status of the last Sync, if the Interface is a SyncErrorReporter.
+func (s *Sink) ApplyStatus() *localv1.ApplyStatus {
+	s.statusLock.Lock()
+	defer s.statusLock.Unlock()
+
+	return s.status
 }
 
 func (s *Sink) Send(op *localv1.OpItem) (err error) {
@@ -77,6 +108,21 @@ func (s *Sink) Send(op *localv1.OpItem) (err error) {
 		set := op.GetSet()
 
 		switch set.Ref.Set {
+		case localv1.Set_NodesSet:
+			l, ok := s.Interface.(NodeListener)
+			if !ok {
+				return
+			}
+
+			v := &localv1.Node{}
+
+			err = proto.Unmarshal(set.Bytes, v)
+			if err != nil {
+				return
+			}
+
+			l.SetNode(v)
+
 		case localv1.Set_ServicesSet:
 			v := &localv1.Service{}
 
@@ -113,12 +159,37 @@ func (s *Sink) Send(op *localv1.OpItem) (err error) {
 		case localv1.Set_EndpointsSet: // Endpoint: namespace/name/key
 			s.DeleteEndpoint(parts[0], parts[1], parts[2])
 
+		case localv1.Set_NodesSet: // Node: name
+			if l, ok := s.Interface.(NodeListener); ok {
+				l.DeleteNode(del.Path)
+			}
+
 		default:
 			// unknown set, ignore
 		}
 
 	case *localv1.OpItem_Sync:
+		start := time.Now()
+
 		s.Sync()
+
+		r, ok := s.Interface.(SyncErrorReporter)
+		if !ok {
+			return
+		}
+
+		status := &localv1.ApplyStatus{
+			Rev:           op.GetSync().GetRev(),
+			DurationNanos: int64(time.Since(start)),
+		}
+
+		if err := r.SyncError(); err != nil {
+			status.Error = err.Error()
+		}
+
+		s.statusLock.Lock()
+		s.status = status
+		s.statusLock.Unlock()
 	}
 
 	return
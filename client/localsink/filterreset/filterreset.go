@@ -50,6 +50,14 @@ func (s *Sink) WaitRequest() (nodeName string, err error) {
 	return s.sink.WaitRequest()
 }
 
+func (s *Sink) WatchFilter() *localv1.WatchFilter {
+	return localsink.WatchFilter(s.sink)
+}
+
+func (s *Sink) ApplyStatus() *localv1.ApplyStatus {
+	return localsink.ApplyStatus(s.sink)
+}
+
 func (s *Sink) Reset() {
 	s.filtering = true
 	s.seen = make(map[string]bool, len(s.memory))
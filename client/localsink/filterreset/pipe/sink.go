@@ -17,6 +17,8 @@ limitations under the License.
 package pipe
 
 import (
+	"google.golang.org/protobuf/proto"
+
 	"sigs.k8s.io/kpng/api/localv1"
 	"sigs.k8s.io/kpng/client"
 	"sigs.k8s.io/kpng/client/localsink"
@@ -46,6 +48,30 @@ func (ps *Sink) Setup() {
 	}
 }
 
+// ApplyStatus returns the status reported by the target sinks, with the first error reported.
+func (ps *Sink) ApplyStatus() (status *localv1.ApplyStatus) {
+	for _, sink := range ps.targetSinks {
+		s := localsink.ApplyStatus(sink)
+		if s == nil {
+			continue
+		}
+
+		if status == nil {
+			status = proto.Clone(s).(*localv1.ApplyStatus)
+			continue
+		}
+
+		if status.Error == "" {
+			status.Error = s.Error
+		}
+		if s.DurationNanos > status.DurationNanos {
+			status.DurationNanos = s.DurationNanos
+		}
+	}
+
+	return
+}
+
 func (ps *Sink) Send(op *localv1.OpItem) error {
 	for _, sink := range ps.targetSinks {
 		if err := sink.Send(op); err != nil {
@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pipe
+
+import (
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/client/localsink/decoder"
+)
+
+// testBackend is a decoder.Interface doing nothing, and failing its syncs if err is set.
+type testBackend struct {
+	err error
+}
+
+func (b *testBackend) Sync()                                                        {}
+func (b *testBackend) Setup()                                                       {}
+func (b *testBackend) Reset()                                                       {}
+func (b *testBackend) WaitRequest() (string, error)                                 { return "node-a", nil }
+func (b *testBackend) SetService(*localv1.Service)                                  {}
+func (b *testBackend) DeleteService(namespace, name string)                         {}
+func (b *testBackend) SetEndpoint(namespace, svc, key string, ep *localv1.Endpoint) {}
+func (b *testBackend) DeleteEndpoint(namespace, svc, key string)                    {}
+
+// reportingBackend reports its sync errors.
+type reportingBackend struct {
+	testBackend
+}
+
+func (b *reportingBackend) SyncError() error { return b.err }
+
+func TestApplyStatus(t *testing.T) {
+	backend := &reportingBackend{}
+
+	// the second sink doesn't report statuses
+	sink := New(decoder.New(backend), decoder.New(&testBackend{err: errors.New("ignored")}))
+
+	if status := sink.ApplyStatus(); status != nil {
+		t.Errorf("expected no status before the first sync, got %v", status)
+	}
+
+	sync := func(rev uint64) {
+		if err := sink.Send(&localv1.OpItem{Op: &localv1.OpItem_Sync{Sync: &localv1.SyncOp{Rev: rev}}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	backend.err = errors.New("apply failed")
+	sync(3)
+
+	if status := sink.ApplyStatus(); status.Rev != 3 || status.Error != "apply failed" {
+		t.Errorf("expected rev 3 to fail, got %v", status)
+	}
+
+	backend.err = nil
+	sync(4)
+
+	if status := sink.ApplyStatus(); status.Rev != 4 || status.Error != "" {
+		t.Errorf("expected rev 4 to succeed, got %v", status)
+	}
+}
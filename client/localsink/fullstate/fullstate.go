@@ -17,6 +17,9 @@ limitations under the License.
 package fullstate
 
 import (
+	"sync"
+	"time"
+
 	"github.com/google/btree"
 	"google.golang.org/protobuf/proto"
 
@@ -31,13 +34,24 @@ type ServiceEndpoints struct {
 
 type Callback func(item <-chan *ServiceEndpoints)
 type Setup func()
+type NodeCallback func(node *localv1.Node)
 
 type Sink struct {
 	Config    *localsink.Config
 	Callback  Callback
 	SetupFunc Setup
 
+	// NodeCallback, if set, is called before each Callback with the node the sink
+	// is running for, or nil if the node is not known by the server.
+	NodeCallback NodeCallback
+
 	data *btree.BTree
+	node *localv1.Node
+
+	// status of the last applied state, and error of the current one
+	statusLock sync.Mutex
+	status     *localv1.ApplyStatus
+	applyErr   error
 }
 
 func New(config *localsink.Config) *Sink {
@@ -47,7 +61,11 @@ func New(config *localsink.Config) *Sink {
 	}
 }
 
-var _ localsink.Sink = &Sink{}
+var (
+	_ localsink.Sink           = &Sink{}
+	_ localsink.Filterer       = &Sink{}
+	_ localsink.StatusReporter = &Sink{}
+)
 
 // ArrayCallback wraps a array callback
 func ArrayCallback(callback func([]*ServiceEndpoints)) Callback {
@@ -76,8 +94,31 @@ func (s *Sink) WaitRequest() (nodeName string, err error) {
 	return s.Config.NodeName, nil
 }
 
+func (s *Sink) WatchFilter() *localv1.WatchFilter {
+	return s.Config.WatchFilter()
+}
+
+// Failed reports the state being applied couldn't be. It's meant to be called by callbacks, so
+// the error is reported to the server.
+func (s *Sink) Failed(err error) {
+	s.statusLock.Lock()
+	defer s.statusLock.Unlock()
+
+	if s.applyErr == nil {
+		s.applyErr = err
+	}
+}
+
+func (s *Sink) ApplyStatus() *localv1.ApplyStatus {
+	s.statusLock.Lock()
+	defer s.statusLock.Unlock()
+
+	return s.status
+}
+
 func (s *Sink) Reset() {
 	s.data.Clear(false)
+	s.node = nil
 }
 
 func (s *Sink) Send(op *localv1.OpItem) (err error) {
@@ -87,6 +128,16 @@ func (s *Sink) Send(op *localv1.OpItem) (err error) {
 
 		var v proto.Message
 		switch set.Ref.Set {
+		case localv1.Set_NodesSet:
+			node := &localv1.Node{}
+			err = proto.Unmarshal(set.Bytes, node)
+			if err != nil {
+				return
+			}
+
+			s.node = node
+			return
+
 		case localv1.Set_ServicesSet:
 			v = &localv1.Service{}
 		case localv1.Set_EndpointsSet:
@@ -104,9 +155,24 @@ func (s *Sink) Send(op *localv1.OpItem) (err error) {
 		s.data.ReplaceOrInsert(kv{set.Ref.Path, v})
 
 	case *localv1.OpItem_Delete:
+		if op.GetDelete().Set == localv1.Set_NodesSet {
+			s.node = nil
+			return
+		}
+
 		s.data.Delete(kv{Path: op.GetDelete().Path})
 
 	case *localv1.OpItem_Sync:
+		start := time.Now()
+
+		s.statusLock.Lock()
+		s.applyErr = nil
+		s.statusLock.Unlock()
+
+		if s.NodeCallback != nil {
+			s.NodeCallback(s.node)
+		}
+
 		results := make(chan *ServiceEndpoints)
 
 		go func() {
@@ -135,6 +201,18 @@ func (s *Sink) Send(op *localv1.OpItem) (err error) {
 		}()
 
 		s.Callback(results)
+
+		status := &localv1.ApplyStatus{
+			Rev:           op.GetSync().GetRev(),
+			DurationNanos: int64(time.Since(start)),
+		}
+
+		s.statusLock.Lock()
+		if s.applyErr != nil {
+			status.Error = s.applyErr.Error()
+		}
+		s.status = status
+		s.statusLock.Unlock()
 	}
 
 	return
@@ -17,6 +17,7 @@ limitations under the License.
 package fullstate
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
@@ -24,7 +25,7 @@ import (
 	localv1 "sigs.k8s.io/kpng/api/localv1"
 )
 
-var syncOp = &localv1.OpItem{Op: &localv1.OpItem_Sync{Sync: &localv1.EmptyOp{}}}
+var syncOp = &localv1.OpItem{Op: &localv1.OpItem_Sync{Sync: &localv1.SyncOp{}}}
 
 func TestAddRemoveService(t *testing.T) {
 	var latestSeps []*ServiceEndpoints
@@ -72,3 +73,38 @@ func TestAddRemoveService(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestApplyStatus(t *testing.T) {
+	sink := New(nil)
+
+	if status := sink.ApplyStatus(); status != nil {
+		t.Errorf("expected no status before the first sync, got %v", status)
+	}
+
+	fail := false
+	sink.Callback = func(ch <-chan *ServiceEndpoints) {
+		for range ch {
+		}
+		if fail {
+			sink.Failed(errors.New("apply failed"))
+		}
+	}
+
+	syncRev := func(rev uint64) *localv1.OpItem {
+		return &localv1.OpItem{Op: &localv1.OpItem_Sync{Sync: &localv1.SyncOp{Rev: rev}}}
+	}
+
+	fail = true
+	sink.Send(syncRev(3))
+
+	if status := sink.ApplyStatus(); status.Rev != 3 || status.Error != "apply failed" {
+		t.Errorf("unexpected status after a failure: %v", status)
+	}
+
+	fail = false
+	sink.Send(syncRev(4))
+
+	if status := sink.ApplyStatus(); status.Rev != 4 || status.Error != "" {
+		t.Errorf("unexpected status after a success: %v", status)
+	}
+}
@@ -37,8 +37,41 @@ type Sink interface {
 	localv1.OpSink
 }
 
+// Filterer is implemented by sinks watching only a part of the state. The filter is sent with
+// each request, so it can change between requests.
+type Filterer interface {
+	// WatchFilter returns the filter of the next request (nil to watch everything).
+	WatchFilter() *localv1.WatchFilter
+}
+
+// WatchFilter returns the sink's filter, or nil if it doesn't implement Filterer.
+func WatchFilter(sink Sink) *localv1.WatchFilter {
+	if f, ok := sink.(Filterer); ok {
+		return f.WatchFilter()
+	}
+	return nil
+}
+
+// StatusReporter is implemented by sinks reporting how they applied the received states. The
+// status is sent with the next request.
+type StatusReporter interface {
+	// ApplyStatus returns the status of the last applied state (nil if none).
+	ApplyStatus() *localv1.ApplyStatus
+}
+
+// ApplyStatus returns the sink's last apply status, or nil if it doesn't implement StatusReporter.
+func ApplyStatus(sink Sink) *localv1.ApplyStatus {
+	if r, ok := sink.(StatusReporter); ok {
+		return r.ApplyStatus()
+	}
+	return nil
+}
+
 type Config struct {
 	NodeName string
+
+	// Filter restricts the watched services (nil to watch everything)
+	Filter *localv1.WatchFilter
 }
 
 func (c *Config) BindFlags(flags *pflag.FlagSet) {
@@ -51,3 +84,7 @@ func (c *Config) BindFlags(flags *pflag.FlagSet) {
 func (c *Config) WaitRequest() (nodeName string, err error) {
 	return c.NodeName, nil
 }
+
+func (c *Config) WatchFilter() *localv1.WatchFilter {
+	return c.Filter
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalname
+
+import (
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/client/localsink/decoder"
+	"sigs.k8s.io/kpng/client/localsink/fullstate"
+)
+
+// Decoder rewrites ExternalName services into ClusterIP services with one endpoint per resolved
+// IP, for backends receiving changes through a decoder.Interface. The changes are sent to the
+// next interface on Sync, or on a sync of its own when the resolution of the names changes
+// between two Syncs.
+type Decoder struct {
+	decoder.Interface
+
+	names *names
+
+	l         sync.Mutex           // serializes the calls to the next interface
+	pending   bool                 // changes were received since the last Sync
+	externals map[string]*external // by namespace/name
+}
+
+type external struct {
+	svc   *localv1.Service
+	name  string
+	dirty bool           // svc was not sent yet
+	ips   *localv1.IPSet // the IPs sent as endpoints, if any
+}
+
+var (
+	_ decoder.Interface         = &Decoder{}
+	_ decoder.SyncErrorReporter = &Decoder{}
+)
+
+func NewDecoder(resolver Resolver, minTTL time.Duration, next decoder.Interface) *Decoder {
+	d := &Decoder{
+		Interface: next,
+		externals: map[string]*external{},
+	}
+	d.names = newNames(resolver, minTTL, d.resync)
+	return d
+}
+
+func (d *Decoder) Reset() {
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.pending = true
+	d.externals = map[string]*external{}
+	d.Interface.Reset()
+}
+
+func (d *Decoder) SetService(svc *localv1.Service) {
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.pending = true
+	key := svc.Namespace + "/" + svc.Name
+
+	name := externalName(svc)
+	if name == "" {
+		if ext := d.externals[key]; ext != nil {
+			d.deleteEndpoints(ext, nil)
+			delete(d.externals, key)
+		}
+
+		d.Interface.SetService(svc)
+		return
+	}
+
+	ext := d.externals[key]
+	if ext == nil {
+		ext = &external{}
+		d.externals[key] = ext
+	}
+
+	ext.svc, ext.name, ext.dirty = svc, name, true
+}
+
+func (d *Decoder) DeleteService(namespace, name string) {
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.pending = true
+	key := namespace + "/" + name
+
+	if ext := d.externals[key]; ext != nil {
+		d.deleteEndpoints(ext, nil)
+		delete(d.externals, key)
+	}
+
+	d.Interface.DeleteService(namespace, name)
+}
+
+func (d *Decoder) SetEndpoint(namespace, serviceName, key string, endpoint *localv1.Endpoint) {
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.pending = true
+	d.Interface.SetEndpoint(namespace, serviceName, key, endpoint)
+}
+
+func (d *Decoder) DeleteEndpoint(namespace, serviceName, key string) {
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.pending = true
+	d.Interface.DeleteEndpoint(namespace, serviceName, key)
+}
+
+func (d *Decoder) Sync() {
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	d.pending = false
+	d.sync()
+}
+
+// resync syncs the next interface with the new IPs of the names, unless changes are pending:
+// the next Sync applies them along.
+func (d *Decoder) resync() {
+	d.l.Lock()
+	defer d.l.Unlock()
+
+	if d.pending {
+		return
+	}
+
+	klog.V(1).Info("external names resolution changed, syncing again")
+	d.sync()
+}
+
+// sync sends the changes of the external services and syncs the next interface. Must be called
+// with the lock held.
+func (d *Decoder) sync() {
+	inUse := make(map[string]bool, len(d.externals))
+	for _, ext := range d.externals {
+		inUse[ext.name] = true
+	}
+
+	ips := d.names.update(inUse)
+
+	for _, ext := range d.externals {
+		extIPs := ips[ext.name]
+
+		if !ext.dirty && proto.Equal(extIPs, ext.ips) {
+			continue
+		}
+
+		ext.dirty = false
+
+		if extIPs == nil {
+			// not resolved, backends ignore ExternalName services
+			d.deleteEndpoints(ext, nil)
+			d.Interface.SetService(ext.svc)
+			continue
+		}
+
+		seps := forward(&fullstate.ServiceEndpoints{Service: ext.svc}, extIPs)
+
+		d.deleteEndpoints(ext, extIPs)
+		d.Interface.SetService(seps.Service)
+
+		for i, ip := range extIPs.All() {
+			d.Interface.SetEndpoint(ext.svc.Namespace, ext.svc.Name, ip, seps.Endpoints[i])
+		}
+
+		ext.ips = extIPs
+	}
+
+	d.Interface.Sync()
+}
+
+// SyncError forwards the error of the last Sync of the next interface, if it reports it.
+func (d *Decoder) SyncError() error {
+	if r, ok := d.Interface.(decoder.SyncErrorReporter); ok {
+		return r.SyncError()
+	}
+	return nil
+}
+
+// deleteEndpoints deletes the endpoints sent for the service, except the ones to keep.
+func (d *Decoder) deleteEndpoints(ext *external, keep *localv1.IPSet) {
+	if ext.ips == nil {
+		return
+	}
+
+	kept := map[string]bool{}
+	if keep != nil {
+		for _, ip := range keep.All() {
+			kept[ip] = true
+		}
+	}
+
+	for _, ip := range ext.ips.All() {
+		if !kept[ip] {
+			d.Interface.DeleteEndpoint(ext.svc.Namespace, ext.svc.Name, ip)
+		}
+	}
+
+	ext.ips = nil
+}
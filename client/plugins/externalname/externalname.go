@@ -0,0 +1,335 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package externalname allows backends to forward the IPs of ExternalName services to the
+// addresses their external name resolves to.
+package externalname
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/client/localsink/decoder"
+	"sigs.k8s.io/kpng/client/localsink/fullstate"
+)
+
+type Config struct {
+	Resolve    bool
+	ResolvConf string
+	MinTTL     time.Duration
+}
+
+func (c *Config) BindFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&c.Resolve, "resolve-external-names", false, "forward the IPs of ExternalName services to the IPs their external name resolves to")
+	flags.StringVar(&c.ResolvConf, "external-names-resolv-conf", "/etc/resolv.conf", "resolv.conf file giving the DNS servers used to resolve external names")
+	flags.DurationVar(&c.MinTTL, "external-names-min-ttl", 5*time.Second, "minimum delay before resolving an external name again")
+}
+
+// Wrap returns a callback resolving external names before calling the given callback, or
+// the callback itself if the resolution is not enabled.
+func (c *Config) Wrap(callback fullstate.Callback) fullstate.Callback {
+	if !c.Resolve {
+		return callback
+	}
+
+	return New(c.resolver(), c.MinTTL, callback).Callback
+}
+
+// WrapDecoder returns a decoder.Interface resolving external names before calling the given
+// one, or the given one itself if the resolution is not enabled.
+func (c *Config) WrapDecoder(next decoder.Interface) decoder.Interface {
+	if !c.Resolve {
+		return next
+	}
+
+	return NewDecoder(c.resolver(), c.MinTTL, next)
+}
+
+func (c *Config) resolver() Resolver {
+	resolver, err := NewDNSResolver(c.ResolvConf)
+	if err != nil {
+		klog.Fatal("failed to setup the external names resolver: ", err)
+	}
+
+	return resolver
+}
+
+// ExternalNames rewrites ExternalName services into ClusterIP services with one endpoint per
+// resolved IP, before calling the next callback. The last state is applied again when the
+// resolution of its names changes.
+type ExternalNames struct {
+	names *names
+	next  fullstate.Callback
+
+	l     sync.Mutex // serializes the calls to next
+	items []*fullstate.ServiceEndpoints
+}
+
+var _ fullstate.Callback = (&ExternalNames{}).Callback
+
+func New(resolver Resolver, minTTL time.Duration, next fullstate.Callback) *ExternalNames {
+	e := &ExternalNames{next: next}
+	e.names = newNames(resolver, minTTL, e.resync)
+	return e
+}
+
+func (e *ExternalNames) Callback(ch <-chan *fullstate.ServiceEndpoints) {
+	items := make([]*fullstate.ServiceEndpoints, 0)
+	for seps := range ch {
+		items = append(items, seps)
+	}
+
+	e.l.Lock()
+	defer e.l.Unlock()
+
+	e.items = items
+	e.apply()
+}
+
+// resync applies the last state again, with the new IPs of its names.
+func (e *ExternalNames) resync() {
+	e.l.Lock()
+	defer e.l.Unlock()
+
+	if e.items == nil {
+		return // no state yet
+	}
+
+	klog.V(1).Info("external names resolution changed, applying the state again")
+	e.apply()
+}
+
+// apply calls the next callback with the last state. Must be called with the lock held.
+func (e *ExternalNames) apply() {
+	items := e.items
+
+	inUse := map[string]bool{}
+	for _, seps := range items {
+		if name := externalName(seps.Service); name != "" {
+			inUse[name] = true
+		}
+	}
+
+	ips := e.names.update(inUse)
+
+	out := make(chan *fullstate.ServiceEndpoints, 1)
+
+	go func() {
+		defer close(out)
+
+		for _, seps := range items {
+			if name := externalName(seps.Service); name != "" && ips[name] != nil {
+				seps = forward(seps, ips[name])
+			}
+
+			out <- seps
+		}
+	}()
+
+	e.next(out)
+}
+
+// names resolves the external names in use in the background, and again when their TTL
+// expires. When the IPs of a name change, onChange is called so the sink gets them from a new
+// update.
+type names struct {
+	resolver Resolver
+	minTTL   time.Duration
+	onChange func()
+
+	now       func() time.Time
+	afterFunc func(d time.Duration, f func()) *time.Timer
+
+	l     sync.Mutex
+	names map[string]*resolved
+	timer *time.Timer
+}
+
+type resolved struct {
+	ips       *localv1.IPSet
+	expires   time.Time
+	resolving bool
+}
+
+func newNames(resolver Resolver, minTTL time.Duration, onChange func()) *names {
+	return &names{
+		resolver:  resolver,
+		minTTL:    minTTL,
+		onChange:  onChange,
+		now:       time.Now,
+		afterFunc: time.AfterFunc,
+		names:     map[string]*resolved{},
+	}
+}
+
+// update schedules the resolution of the new names, forgets the ones not in use anymore, and
+// returns the current IPs of the resolved names.
+func (n *names) update(inUse map[string]bool) (ips map[string]*localv1.IPSet) {
+	n.l.Lock()
+	defer n.l.Unlock()
+
+	for name := range inUse {
+		if _, ok := n.names[name]; !ok {
+			// expired already, so resolved right away, without IPs until then
+			n.names[name] = &resolved{}
+		}
+	}
+
+	ips = make(map[string]*localv1.IPSet, len(n.names))
+
+	for name, r := range n.names {
+		if !inUse[name] {
+			delete(n.names, name)
+			continue
+		}
+
+		if r.ips != nil {
+			ips[name] = r.ips
+		}
+	}
+
+	n.schedule()
+
+	return
+}
+
+// refresh resolves the expired names, and calls onChange if their IPs changed.
+func (n *names) refresh() {
+	n.l.Lock()
+	expired := map[string]*localv1.IPSet{}
+	now := n.now()
+	for name, r := range n.names {
+		if !r.resolving && !now.Before(r.expires) {
+			expired[name] = r.ips
+			r.resolving = true
+		}
+	}
+	n.l.Unlock()
+
+	// resolve without blocking updates
+	results := make(map[string]*resolved, len(expired))
+	for name, previous := range expired {
+		results[name] = n.resolve(name, previous)
+	}
+
+	n.l.Lock()
+
+	changed := false
+	for name, r := range results {
+		if current, ok := n.names[name]; ok {
+			if !proto.Equal(current.ips, r.ips) {
+				klog.V(1).Infof("external name %q resolution changed", name)
+				changed = true
+			}
+			n.names[name] = r
+		}
+	}
+
+	n.schedule()
+	n.l.Unlock()
+
+	if changed && n.onChange != nil {
+		n.onChange()
+	}
+}
+
+// resolve resolves the name, keeping the previous IPs if it fails.
+func (n *names) resolve(name string, previous *localv1.IPSet) *resolved {
+	ips, ttl, err := n.resolver.Resolve(context.Background(), name)
+	if err != nil {
+		// keep the previous IPs, if any, until the name can be resolved again
+		klog.Warningf("failed to resolve external name %q: %v", name, err)
+		ips = previous
+	}
+
+	if ips != nil && ips.IsEmpty() {
+		ips = nil
+	}
+
+	if ttl < n.minTTL {
+		ttl = n.minTTL
+	}
+
+	return &resolved{ips: ips, expires: n.now().Add(ttl)}
+}
+
+// schedule the next refresh, when the first name expires. Must be called with the lock held.
+func (n *names) schedule() {
+	if n.timer != nil {
+		n.timer.Stop()
+		n.timer = nil
+	}
+
+	next, found := time.Time{}, false
+	for _, r := range n.names {
+		if r.resolving {
+			continue // rescheduled once resolved
+		}
+		if !found || r.expires.Before(next) {
+			next, found = r.expires, true
+		}
+	}
+
+	if found {
+		n.timer = n.afterFunc(next.Sub(n.now()), n.refresh)
+	}
+}
+
+// externalName returns the name to resolve for the service, if it's an ExternalName service.
+func externalName(svc *localv1.Service) string {
+	if svc.Type != "ExternalName" {
+		return ""
+	}
+
+	return svc.ExternalName
+}
+
+// forward returns the service as a ClusterIP service having the given IPs as endpoints.
+func forward(seps *fullstate.ServiceEndpoints, ips *localv1.IPSet) *fullstate.ServiceEndpoints {
+	svc := proto.Clone(seps.Service).(*localv1.Service)
+	svc.Type = "ClusterIP"
+
+	overrides := make([]*localv1.PortName, 0, len(svc.Ports))
+
+	for _, port := range svc.Ports {
+		// external names have no named ports, the port is also the target port by default
+		if port.TargetPort == 0 {
+			port.TargetPort = port.Port
+		}
+		port.TargetPortName = ""
+
+		if port.Name != "" {
+			overrides = append(overrides, &localv1.PortName{Name: port.Name, Port: port.TargetPort})
+		}
+	}
+
+	endpoints := make([]*localv1.Endpoint, 0, len(ips.V4)+len(ips.V6))
+	for _, ip := range ips.All() {
+		endpoints = append(endpoints, &localv1.Endpoint{
+			IPs:           localv1.NewIPSet(ip),
+			PortOverrides: overrides,
+			Scopes:        &localv1.EndpointScopes{Internal: true, External: true},
+		})
+	}
+
+	return &fullstate.ServiceEndpoints{Service: svc, Endpoints: endpoints}
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalname
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/client/localsink/fullstate"
+)
+
+type stubResolver struct {
+	l     sync.Mutex
+	ips   map[string][]string
+	calls int
+}
+
+func (r *stubResolver) set(name string, ips ...string) {
+	r.l.Lock()
+	defer r.l.Unlock()
+	r.ips[name] = ips
+}
+
+func (r *stubResolver) Resolve(ctx context.Context, name string) (*localv1.IPSet, time.Duration, error) {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	r.calls++
+
+	ips, ok := r.ips[name]
+	if !ok {
+		return nil, 0, fmt.Errorf("no address found for %s", name)
+	}
+
+	return localv1.NewIPSet(ips...), time.Minute, nil
+}
+
+// testNames returns names with a controlled clock. The refreshes are never scheduled, so
+// they're triggered explicitly.
+func testNames(resolver Resolver, onChange func()) (n *names, advance func()) {
+	now := time.Unix(0, 0)
+
+	n = newNames(resolver, time.Hour, onChange)
+	n.now = func() time.Time { return now }
+	n.afterFunc = func(time.Duration, func()) *time.Timer { return time.NewTimer(time.Hour) }
+
+	return n, func() { now = now.Add(2 * time.Hour) }
+}
+
+func dbService() *localv1.Service {
+	return &localv1.Service{
+		Namespace:    "default",
+		Name:         "db",
+		Type:         "ExternalName",
+		ExternalName: "db.example.com",
+		Ports:        []*localv1.PortMapping{{Name: "sql", Protocol: localv1.Protocol_TCP, Port: 5432}},
+	}
+}
+
+func TestExternalNames(t *testing.T) {
+	resolver := &stubResolver{ips: map[string][]string{}}
+	resolver.set("db.example.com", "192.0.2.1")
+
+	var result []string
+
+	e := New(resolver, 0, func(ch <-chan *fullstate.ServiceEndpoints) {
+		result = []string{}
+		for seps := range ch {
+			s := seps.Service.Name + " " + seps.Service.Type
+			for _, ep := range seps.Endpoints {
+				s += fmt.Sprint(" ", ep.IPs.All(), ":", ep.PortMappings(seps.Service.Ports))
+			}
+			result = append(result, s)
+		}
+	})
+
+	var advance func()
+	e.names, advance = testNames(resolver, e.resync)
+
+	services := []*fullstate.ServiceEndpoints{
+		{Service: dbService()},
+		{Service: &localv1.Service{
+			Name:         "unresolved",
+			Type:         "ExternalName",
+			ExternalName: "unknown.example.com",
+		}},
+		{Service: &localv1.Service{Name: "web", Type: "ClusterIP"}},
+	}
+
+	callback := func(items ...*fullstate.ServiceEndpoints) {
+		ch := make(chan *fullstate.ServiceEndpoints, len(items))
+		for _, seps := range items {
+			ch <- seps
+		}
+		close(ch)
+
+		result = nil
+		e.Callback(ch)
+	}
+
+	check := func(step string, expected ...string) {
+		t.Helper()
+		if fmt.Sprint(result) != fmt.Sprint(expected) {
+			t.Errorf("%s: expected %q, got %q", step, expected, result)
+		}
+	}
+
+	// the names are resolved in the background
+	callback(services...)
+	check("initial",
+		"db ExternalName",
+		"unresolved ExternalName",
+		"web ClusterIP")
+
+	// the state is applied again once resolved
+	result = nil
+	e.names.refresh()
+	check("resolved",
+		"db ClusterIP [192.0.2.1]:map[5432:5432]",
+		"unresolved ExternalName",
+		"web ClusterIP")
+
+	// refreshes without changes don't apply the state again
+	advance()
+	result = nil
+	e.names.refresh()
+
+	if result != nil {
+		t.Errorf("unchanged refresh: expected no callback, got %q", result)
+	}
+
+	// refreshes with changes do
+	resolver.set("db.example.com", "192.0.2.2", "2001:db8::2")
+	advance()
+	result = nil
+	e.names.refresh()
+	check("after refresh",
+		"db ClusterIP [192.0.2.2]:map[5432:5432] [2001:db8::2]:map[5432:5432]",
+		"unresolved ExternalName",
+		"web ClusterIP")
+
+	// the names not used anymore are not resolved anymore
+	callback()
+	check("deleted")
+
+	calls := resolver.calls
+	advance()
+	e.names.refresh()
+
+	if resolver.calls != calls {
+		t.Errorf("expected no more resolutions, got %d", resolver.calls-calls)
+	}
+}
+
+// recorder records the calls of a decoder.
+type recorder struct {
+	ops []string
+}
+
+func (r *recorder) record(op ...interface{}) {
+	r.ops = append(r.ops, strings.TrimSpace(fmt.Sprintln(op...)))
+}
+
+func (r *recorder) Setup()                               {}
+func (r *recorder) WaitRequest() (string, error)         { return "node-a", nil }
+func (r *recorder) Reset()                               { r.record("reset") }
+func (r *recorder) Sync()                                { r.record("sync") }
+func (r *recorder) SetService(svc *localv1.Service)      { r.record("set service", svc.Name, svc.Type) }
+func (r *recorder) DeleteService(namespace, name string) { r.record("delete service", name) }
+
+func (r *recorder) SetEndpoint(namespace, serviceName, key string, endpoint *localv1.Endpoint) {
+	r.record("set endpoint", serviceName, key, endpoint.IPs.All())
+}
+
+func (r *recorder) DeleteEndpoint(namespace, serviceName, key string) {
+	r.record("delete endpoint", serviceName, key)
+}
+
+func TestDecoder(t *testing.T) {
+	resolver := &stubResolver{ips: map[string][]string{}}
+	resolver.set("db.example.com", "192.0.2.1")
+
+	rec := &recorder{}
+
+	d := NewDecoder(resolver, 0, rec)
+
+	var advance func()
+	d.names, advance = testNames(resolver, d.resync)
+
+	check := func(step string, expected ...string) {
+		t.Helper()
+		if fmt.Sprint(rec.ops) != fmt.Sprint(expected) {
+			t.Errorf("%s: expected %q, got %q", step, expected, rec.ops)
+		}
+		rec.ops = nil
+	}
+
+	// the names are resolved in the background
+	d.SetService(dbService())
+	d.SetService(&localv1.Service{Name: "web", Type: "ClusterIP"})
+	d.Sync()
+	check("initial",
+		"set service web ClusterIP",
+		"set service db ExternalName",
+		"sync")
+
+	// and synced again once resolved
+	d.names.refresh()
+	check("resolved",
+		"set service db ClusterIP",
+		"set endpoint db 192.0.2.1 [192.0.2.1]",
+		"sync")
+
+	// nothing changed
+	d.Sync()
+	check("unchanged", "sync")
+
+	advance()
+	d.names.refresh()
+	check("unchanged refresh")
+
+	// refreshes with changes are synced right away
+	resolver.set("db.example.com", "192.0.2.2", "2001:db8::2")
+	advance()
+	d.names.refresh()
+	check("after refresh",
+		"delete endpoint db 192.0.2.1",
+		"set service db ClusterIP",
+		"set endpoint db 192.0.2.2 [192.0.2.2]",
+		"set endpoint db 2001:db8::2 [2001:db8::2]",
+		"sync")
+
+	// unless changes are pending, then they're synced with them
+	d.SetService(&localv1.Service{Name: "web", Type: "ClusterIP"})
+	resolver.set("db.example.com", "192.0.2.3")
+	advance()
+	d.names.refresh()
+	check("pending", "set service web ClusterIP")
+
+	d.Sync()
+	check("after pending",
+		"delete endpoint db 192.0.2.2",
+		"delete endpoint db 2001:db8::2",
+		"set service db ClusterIP",
+		"set endpoint db 192.0.2.3 [192.0.2.3]",
+		"sync")
+
+	// not an ExternalName service anymore
+	svc := dbService()
+	svc.Type = "ClusterIP"
+	d.SetService(svc)
+	d.Sync()
+	check("type changed",
+		"delete endpoint db 192.0.2.3",
+		"set service db ClusterIP",
+		"sync")
+
+	// unresolved names are sent as is
+	svc = dbService()
+	svc.ExternalName = "unknown.example.com"
+	d.SetService(svc)
+	d.Sync()
+	check("unresolved", "set service db ExternalName", "sync")
+
+	d.SetService(dbService())
+	d.Sync()
+	d.names.refresh()
+	d.DeleteService("default", "db")
+	check("deleted",
+		"set service db ExternalName",
+		"sync",
+		"set service db ClusterIP",
+		"set endpoint db 192.0.2.3 [192.0.2.3]",
+		"sync",
+		"delete endpoint db 192.0.2.3",
+		"delete service db")
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalname
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"sigs.k8s.io/kpng/api/localv1"
+)
+
+// Resolver resolves external names.
+type Resolver interface {
+	// Resolve returns the IPs of the name and how long they are valid.
+	Resolve(ctx context.Context, name string) (ips *localv1.IPSet, ttl time.Duration, err error)
+}
+
+// DNSResolver queries DNS servers directly, since the TTLs are not available from the net package.
+type DNSResolver struct {
+	// Servers are the DNS servers addresses, tried in order.
+	Servers []string
+	// Timeout is the timeout of each query.
+	Timeout time.Duration
+}
+
+var _ Resolver = &DNSResolver{}
+
+// NewDNSResolver returns a resolver using the nameservers from the given resolv.conf file.
+func NewDNSResolver(resolvConf string) (*DNSResolver, error) {
+	f, err := os.Open(resolvConf)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := &DNSResolver{Timeout: 2 * time.Second}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+
+		r.Servers = append(r.Servers, net.JoinHostPort(fields[1], "53"))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(r.Servers) == 0 {
+		return nil, fmt.Errorf("no nameserver in %s", resolvConf)
+	}
+
+	return r, nil
+}
+
+func (r *DNSResolver) Resolve(ctx context.Context, name string) (ips *localv1.IPSet, ttl time.Duration, err error) {
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+
+	qname, err := dnsmessage.NewName(name)
+	if err != nil {
+		return
+	}
+
+	ips = localv1.NewIPSet()
+	ttl = -1
+
+	for _, qtype := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+		var answers []dnsmessage.Resource
+		answers, err = r.query(ctx, qname, qtype)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, answer := range answers {
+			switch body := answer.Body.(type) {
+			case *dnsmessage.AResource:
+				ips.Add(net.IP(body.A[:]).String())
+			case *dnsmessage.AAAAResource:
+				ips.Add(net.IP(body.AAAA[:]).String())
+			default:
+				// CNAMEs also limit the validity of the result
+			}
+
+			answerTTL := time.Duration(answer.Header.TTL) * time.Second
+			if ttl < 0 || answerTTL < ttl {
+				ttl = answerTTL
+			}
+		}
+	}
+
+	if ips.IsEmpty() {
+		return nil, 0, fmt.Errorf("no address found for %s", name)
+	}
+
+	return
+}
+
+// query sends the question to the servers until one answers.
+func (r *DNSResolver) query(ctx context.Context, name dnsmessage.Name, qtype dnsmessage.Type) (answers []dnsmessage.Resource, err error) {
+	id := uint16(rand.Uint32())
+
+	query, err := (&dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: name, Type: qtype, Class: dnsmessage.ClassINET}},
+	}).Pack()
+	if err != nil {
+		return
+	}
+
+	err = errors.New("no DNS server")
+
+	for _, server := range r.Servers {
+		answers, err = r.queryServer(ctx, server, id, query)
+		if err == nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (r *DNSResolver) queryServer(ctx context.Context, server string, id uint16, query []byte) (answers []dnsmessage.Resource, err error) {
+	ctx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "udp", server)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err = conn.Write(query); err != nil {
+		return
+	}
+
+	buf := make([]byte, 4096)
+	for {
+		var n int
+		n, err = conn.Read(buf)
+		if err != nil {
+			return
+		}
+
+		msg := dnsmessage.Message{}
+		if err = msg.Unpack(buf[:n]); err != nil {
+			return
+		}
+
+		if msg.ID != id || !msg.Response {
+			continue // not our answer
+		}
+
+		switch msg.RCode {
+		case dnsmessage.RCodeSuccess:
+			return msg.Answers, nil
+		case dnsmessage.RCodeNameError:
+			return nil, nil // no such name, let the caller report it
+		default:
+			return nil, fmt.Errorf("DNS server %s answered %v", server, msg.RCode)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package proglatency measures the network programming latency: the time from an endpoint
+// change in the cluster (see localv1.Endpoint.LastChangeTriggerTime and
+// localv1.Service.LastChangeTriggerTime) to its application by a backend.
+package proglatency
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"sigs.k8s.io/kpng/api/localv1"
+)
+
+// NetworkProgrammingLatency is the kube-proxy network programming latency SLI (see
+// https://github.com/kubernetes/community/blob/master/sig-scalability/slos/network_programming_latency.md).
+// It's based on the time set by the control plane, so it's inaccurate if the clocks drift.
+var NetworkProgrammingLatency = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Name: "kpng_network_programming_duration_seconds",
+	Help: "In Cluster Network Programming Latency in seconds",
+	Buckets: merge(
+		prometheus.LinearBuckets(0.25, 0.25, 2), // 0.25s, 0.50s
+		prometheus.LinearBuckets(1, 1, 59),      // 1s, 2s, 3s, ... 59s
+		prometheus.LinearBuckets(60, 5, 12),     // 60s, 65s, 70s, ... 115s
+		prometheus.LinearBuckets(120, 30, 7),    // 2min, 2.5min, 3min, ..., 5min
+	),
+})
+
+func merge(slices ...[]float64) (result []float64) {
+	for _, s := range slices {
+		result = append(result, s...)
+	}
+	return
+}
+
+var registerOnce sync.Once
+
+// RegisterMetrics registers the metrics of this package in the default prometheus registry.
+func RegisterMetrics() {
+	registerOnce.Do(func() {
+		prometheus.MustRegister(NetworkProgrammingLatency)
+	})
+}
+
+// Tracker tracks the change trigger times of the services and endpoints in the states applied by
+// a backend, observing each one once, when first successfully applied. Times before the
+// tracker's creation are ignored, as they come from changes applied before a restart.
+type Tracker struct {
+	// Observe is called with the latency of each change (defaults to NetworkProgrammingLatency)
+	Observe func(latency time.Duration)
+
+	start   time.Time
+	applied map[int64]bool
+	current map[int64]bool
+}
+
+func New() *Tracker {
+	return &Tracker{
+		Observe: func(latency time.Duration) { NetworkProgrammingLatency.Observe(latency.Seconds()) },
+		start:   time.Now(),
+		applied: map[int64]bool{},
+		current: map[int64]bool{},
+	}
+}
+
+// Add records the endpoint's trigger time in the state being applied.
+func (t *Tracker) Add(ep *localv1.Endpoint) {
+	t.add(ep.ChangeTriggerTime())
+}
+
+// AddService records the trigger time of the last change of the service's endpoints in the state
+// being applied, so the changes no endpoint carries (ie: deletions) are observed too.
+func (t *Tracker) AddService(svc *localv1.Service) {
+	t.add(svc.ChangeTriggerTime())
+}
+
+func (t *Tracker) add(triggerTime time.Time) {
+	if triggerTime.Before(t.start) {
+		return
+	}
+	t.current[triggerTime.UnixNano()] = true
+}
+
+// Applied observes the latency of the changes not applied before. It must be called once the
+// state is successfully applied.
+func (t *Tracker) Applied() {
+	now := time.Now()
+
+	for triggerTime := range t.current {
+		if !t.applied[triggerTime] {
+			t.Observe(now.Sub(time.Unix(0, triggerTime)))
+		}
+	}
+
+	t.applied, t.current = t.current, map[int64]bool{}
+}
+
+// Failed discards the state being applied, its changes will be observed on the next success.
+func (t *Tracker) Failed() {
+	t.current = map[int64]bool{}
+}
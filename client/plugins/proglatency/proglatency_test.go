@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proglatency
+
+import (
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kpng/api/localv1"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := New()
+
+	observed := 0
+	tracker.Observe = func(latency time.Duration) {
+		if latency < 0 {
+			t.Errorf("negative latency: %v", latency)
+		}
+		observed++
+	}
+
+	old := &localv1.Endpoint{LastChangeTriggerTime: tracker.start.Add(-time.Hour).UnixNano()}
+	ep1 := &localv1.Endpoint{LastChangeTriggerTime: time.Now().UnixNano()}
+	ep2 := &localv1.Endpoint{LastChangeTriggerTime: time.Now().UnixNano() + 1}
+
+	check := func(step string, expected int) {
+		t.Helper()
+		if observed != expected {
+			t.Errorf("%s: expected %d observations, got %d", step, expected, observed)
+		}
+	}
+
+	tracker.Add(old)
+	tracker.Add(ep1)
+	tracker.Applied()
+	check("first apply", 1)
+
+	// unchanged endpoints are not observed again
+	tracker.Add(ep1)
+	tracker.Applied()
+	check("unchanged", 1)
+
+	// failures delay the observation
+	tracker.Add(ep1)
+	tracker.Add(ep2)
+	tracker.Failed()
+	check("failed", 1)
+
+	tracker.Add(ep1)
+	tracker.Add(ep2)
+	tracker.Applied()
+	check("retried", 2)
+
+	// deletions are observed from the service's trigger time, once with its endpoints'
+	deleted := &localv1.Service{LastChangeTriggerTime: time.Now().UnixNano() + 2}
+	tracker.Add(ep2)
+	tracker.AddService(deleted)
+	tracker.Applied()
+	check("deleted", 3)
+
+	ep3 := &localv1.Endpoint{LastChangeTriggerTime: time.Now().UnixNano() + 3}
+	tracker.Add(ep3)
+	tracker.AddService(&localv1.Service{LastChangeTriggerTime: ep3.LastChangeTriggerTime})
+	tracker.Applied()
+	check("same change", 4)
+}
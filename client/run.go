@@ -20,11 +20,13 @@ import (
 	"flag"
 	"os"
 	"runtime/pprof"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 
 	// "k8s.io/klog/v2"
+	"sigs.k8s.io/kpng/api/localv1"
 	"sigs.k8s.io/kpng/client/localsink"
 	"sigs.k8s.io/kpng/client/localsink/fullstate"
 )
@@ -58,6 +60,11 @@ type Runner struct {
 	cpuprofile string
 	NodeName   string
 
+	// watch filter (comma separated lists)
+	namespaces    string
+	labelSelector string
+	serviceTypes  string
+
 	lc *LocalClient
 }
 
@@ -65,6 +72,9 @@ func (r *Runner) BindFlags(flags FlagSet) {
 	flag.BoolVar(&r.once, "once", false, "only one fetch loop")
 	flag.StringVar(&r.cpuprofile, "cpuprofile", "", "write cpu profile to file")
 	flag.StringVar(&r.NodeName, "node-name", func() string { s, _ := os.Hostname(); return s }(), "node name to request to the proxy server")
+	flag.StringVar(&r.namespaces, "watch-namespaces", "", "only watch services in these namespaces (comma separated)")
+	flag.StringVar(&r.labelSelector, "watch-label-selector", "", "only watch services matching this label selector")
+	flag.StringVar(&r.serviceTypes, "watch-service-types", "", "only watch services of these types (comma separated, ie: LoadBalancer)")
 
 	r.lc = New(flags)
 }
@@ -81,12 +91,36 @@ func ArrayBackend(handlers ...HandleFunc) fullstate.Callback {
 // RunBackend runs the client with the standard options, using the channeled backend.
 // It should consume less memory as the dataset is processed as it's read instead of buffered.
 func (r *Runner) RunBackend(handler fullstate.Callback) {
-	sink := fullstate.New(&localsink.Config{NodeName: r.NodeName})
+	sink := fullstate.New(&localsink.Config{NodeName: r.NodeName, Filter: r.WatchFilter()})
 	sink.Callback = handler
 
 	r.RunSink(sink)
 }
 
+// WatchFilter returns the filter set by the command-line flags, or nil if there's none.
+func (r *Runner) WatchFilter() *localv1.WatchFilter {
+	filter := &localv1.WatchFilter{
+		Namespaces:    splitList(r.namespaces),
+		LabelSelector: r.labelSelector,
+		ServiceTypes:  splitList(r.serviceTypes),
+	}
+
+	if len(filter.Namespaces) == 0 && filter.LabelSelector == "" && len(filter.ServiceTypes) == 0 {
+		return nil
+	}
+
+	return filter
+}
+
+func splitList(s string) (values []string) {
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return
+}
+
 func (r *Runner) RunSink(sink localsink.Sink) {
 	r.lc.Sink = sink
 
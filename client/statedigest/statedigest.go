@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package statedigest computes an order-independent digest of a local state,
+// as built from the localv1.OpItem stream. The server and the client computing
+// the digest of the same state get the same value, allowing a watch to resume.
+package statedigest
+
+import (
+	"encoding/binary"
+
+	"github.com/cespare/xxhash"
+
+	"sigs.k8s.io/kpng/api/localv1"
+)
+
+type ref struct {
+	set  localv1.Set
+	path string
+}
+
+type Digest struct {
+	items map[ref]uint64
+	sum   uint64
+}
+
+func New() *Digest {
+	return &Digest{items: map[ref]uint64{}}
+}
+
+// ItemHash returns the hash of a single item of the state.
+func ItemHash(set localv1.Set, path string, value []byte) uint64 {
+	h := xxhash.New()
+
+	buf := make([]byte, binary.MaxVarintLen64)
+	h.Write(buf[:binary.PutUvarint(buf, uint64(set))])
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write(value)
+
+	return h.Sum64()
+}
+
+// Sum returns the digest of the current state (0 if the state is empty).
+func (d *Digest) Sum() uint64 {
+	return d.sum
+}
+
+// Set adds or updates an item.
+func (d *Digest) Set(set localv1.Set, path string, value []byte) {
+	d.Delete(set, path)
+
+	h := ItemHash(set, path, value)
+	d.items[ref{set, path}] = h
+	d.sum += h
+}
+
+// Delete removes an item.
+func (d *Digest) Delete(set localv1.Set, path string) {
+	r := ref{set, path}
+
+	if h, ok := d.items[r]; ok {
+		d.sum -= h
+		delete(d.items, r)
+	}
+}
+
+// Reset clears the state.
+func (d *Digest) Reset() {
+	d.items = map[ref]uint64{}
+	d.sum = 0
+}
+
+// Apply updates the digest with the given operation.
+func (d *Digest) Apply(op *localv1.OpItem) {
+	switch v := op.Op.(type) {
+	case *localv1.OpItem_Set:
+		d.Set(v.Set.Ref.Set, v.Set.Ref.Path, v.Set.Bytes)
+
+	case *localv1.OpItem_Delete:
+		d.Delete(v.Delete.Set, v.Delete.Path)
+
+	case *localv1.OpItem_Reset_:
+		d.Reset()
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statedigest
+
+import (
+	"testing"
+
+	"sigs.k8s.io/kpng/api/localv1"
+)
+
+func setOp(set localv1.Set, path, value string) *localv1.OpItem {
+	return &localv1.OpItem{Op: &localv1.OpItem_Set{Set: &localv1.Value{
+		Ref:   &localv1.Ref{Set: set, Path: path},
+		Bytes: []byte(value),
+	}}}
+}
+
+func TestDigest(t *testing.T) {
+	d1 := New()
+	d1.Apply(setOp(localv1.Set_ServicesSet, "ns/a", "a"))
+	d1.Apply(setOp(localv1.Set_ServicesSet, "ns/b", "b"))
+	d1.Apply(setOp(localv1.Set_EndpointsSet, "ns/b/x", "x"))
+
+	d2 := New()
+	d2.Apply(setOp(localv1.Set_EndpointsSet, "ns/b/x", "x"))
+	d2.Apply(setOp(localv1.Set_ServicesSet, "ns/b", "old-b"))
+	d2.Apply(setOp(localv1.Set_ServicesSet, "ns/c", "c"))
+	d2.Apply(setOp(localv1.Set_ServicesSet, "ns/a", "a"))
+
+	if d1.Sum() == d2.Sum() {
+		t.Fatal("different states should have different digests")
+	}
+
+	d2.Apply(setOp(localv1.Set_ServicesSet, "ns/b", "b"))
+	d2.Apply(&localv1.OpItem{Op: &localv1.OpItem_Delete{Delete: &localv1.Ref{Set: localv1.Set_ServicesSet, Path: "ns/c"}}})
+
+	if d1.Sum() != d2.Sum() {
+		t.Error("same states should have the same digest")
+	}
+
+	// same path in another set is another item
+	d2.Apply(setOp(localv1.Set_EndpointsSet, "ns/a", "a"))
+	if d1.Sum() == d2.Sum() {
+		t.Error("different sets should have different digests")
+	}
+
+	d2.Apply(&localv1.OpItem{Op: &localv1.OpItem_Reset_{}})
+	if d2.Sum() != 0 {
+		t.Error("empty state should have a zero digest")
+	}
+}
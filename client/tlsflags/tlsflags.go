@@ -19,9 +19,13 @@ package tlsflags
 import (
 	"crypto/tls"
 	"crypto/x509"
-	"io/ioutil"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
 	"time"
-	// "k8s.io/klog/v2"
+
+	"k8s.io/klog/v2"
 )
 
 func Bind(flags FlagSet) (f *Flags) {
@@ -34,6 +38,16 @@ type Flags struct {
 	KeyFile,
 	CertFile,
 	CAFile string
+
+	// MinVersion is the minimum TLS version (ie: "1.2")
+	MinVersion string
+	// CipherSuites are the comma-separated names of the allowed TLS 1.2 cipher suites (empty for Go's defaults)
+	CipherSuites string
+	// ServerName is the name expected in the server's certificate, if not the dialed host
+	ServerName string
+
+	filesOnce sync.Once
+	files     *files
 }
 
 // FlagSet matches flag.FlagSet and pflag.FlagSet
@@ -48,38 +62,211 @@ func (f *Flags) Bind(flags FlagSet, prefix string) {
 	flags.StringVar(&f.KeyFile, prefix+"tls-key", "", "TLS key file")
 	flags.StringVar(&f.CertFile, prefix+"tls-crt", "", "TLS certificate file")
 	flags.StringVar(&f.CAFile, prefix+"tls-ca", "", "TLS CA certificate file")
+	flags.StringVar(&f.MinVersion, prefix+"tls-min-version", "1.2", "minimum TLS version (1.2 or 1.3)")
+	flags.StringVar(&f.CipherSuites, prefix+"tls-cipher-suites", "", "comma-separated TLS 1.2 cipher suites (ie: TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256; defaults to Go's secure ones)")
+	flags.StringVar(&f.ServerName, prefix+"tls-server-name", "", "name expected in the server's certificate (defaults to the dialed host)")
 }
 
+// Config returns the TLS configuration, or nil if TLS is not configured.
+//
+// The key pair is reloaded when its files change, at each handshake. The CA is reloaded the
+// same way by servers, and at each call by clients (ie: when they dial).
 func (f *Flags) Config() (cfg *tls.Config) {
 	if f == nil || f.CAFile == "" && f.KeyFile == "" && f.CertFile == "" {
 		return
 	}
 
-	cfg = &tls.Config{MinVersion: tls.VersionTLS12}
+	minVersion, err := parseVersion(f.MinVersion)
+	if err != nil {
+		klog.Fatal(err)
+	}
+
+	cipherSuites, err := parseCipherSuites(f.CipherSuites)
+	if err != nil {
+		klog.Fatal(err)
+	}
+
+	cfg = &tls.Config{
+		MinVersion:   minVersion,
+		CipherSuites: cipherSuites,
+		ServerName:   f.ServerName,
+	}
+
+	f.filesOnce.Do(func() {
+		f.files = &files{keyFile: f.KeyFile, certFile: f.CertFile, caFile: f.CAFile}
+	})
 
 	if f.KeyFile != "" || f.CertFile != "" {
-		cert, err := tls.LoadX509KeyPair(f.CertFile, f.KeyFile)
-		if err != nil {
-			//	klog.Fatal("failed to load TLS key pair: ", err)
+		if _, err := f.files.certificate(); err != nil {
+			klog.Error("failed to load TLS key pair: ", err)
 		}
 
-		cfg.Certificates = []tls.Certificate{cert}
+		cfg.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return f.files.certificate()
+		}
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return f.files.certificate()
+		}
 	}
 
 	if f.CAFile != "" {
-		data, err := ioutil.ReadFile(f.CAFile)
+		pool, err := f.files.caPool()
 		if err != nil {
-			//	klog.Fatal("failed to load TLS CA certificate: ", err)
-		}
-
-		pool := x509.NewCertPool()
-		if !pool.AppendCertsFromPEM(data) {
-			//	klog.Fatal("failed to parse CA certificate")
+			klog.Error("failed to load TLS CA certificate: ", err)
+			pool = x509.NewCertPool() // trust nothing rather than the system CAs
 		}
 
 		cfg.ClientCAs = pool
 		cfg.RootCAs = pool
+
+		// servers use the current CA for each client (cfg may be modified by the caller before)
+		cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			pool, err := f.files.caPool()
+			if err != nil {
+				return nil, err
+			}
+
+			clientCfg := cfg.Clone()
+			clientCfg.ClientCAs = pool
+			clientCfg.RootCAs = pool
+			clientCfg.GetConfigForClient = nil
+			return clientCfg, nil
+		}
 	}
 
 	return
 }
+
+var versions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+func parseVersion(version string) (uint16, error) {
+	if version == "" {
+		return tls.VersionTLS12, nil
+	}
+
+	v, ok := versions[version]
+	if !ok {
+		return 0, fmt.Errorf("unknown TLS version: %q", version)
+	}
+	return v, nil
+}
+
+func parseCipherSuites(names string) (ids []uint16, err error) {
+	if names == "" {
+		return
+	}
+
+	known := map[string]uint16{}
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure TLS cipher suite: %q", name)
+		}
+		ids = append(ids, id)
+	}
+
+	return
+}
+
+// files loads the TLS files, and reloads them when they change.
+type files struct {
+	keyFile, certFile, caFile string
+
+	mu sync.Mutex
+
+	cert    *tls.Certificate
+	certMod string
+
+	pool  *x509.CertPool
+	caMod string
+}
+
+// modification returns a string changing when one of the files change.
+func modification(paths ...string) (mod string, err error) {
+	for _, path := range paths {
+		var info os.FileInfo
+		info, err = os.Stat(path)
+		if err != nil {
+			return
+		}
+		mod += fmt.Sprint(info.ModTime().UnixNano(), "/", info.Size(), " ")
+	}
+	return
+}
+
+// certificate returns the key pair, reloaded if its files changed. If the reload fails, the
+// previous key pair is kept (ie: the key is written but not the certificate yet).
+func (fs *files) certificate() (*tls.Certificate, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	mod, err := modification(fs.certFile, fs.keyFile)
+	if err == nil && mod == fs.certMod {
+		return fs.cert, nil
+	}
+
+	if err == nil {
+		var cert tls.Certificate
+		cert, err = tls.LoadX509KeyPair(fs.certFile, fs.keyFile)
+		if err == nil {
+			if fs.cert != nil {
+				klog.Info("reloaded TLS key pair from ", fs.certFile)
+			}
+			fs.cert, fs.certMod = &cert, mod
+			return fs.cert, nil
+		}
+	}
+
+	if fs.cert == nil {
+		return nil, err
+	}
+
+	klog.Warning("failed to reload TLS key pair, keeping the previous one: ", err)
+	return fs.cert, nil
+}
+
+// caPool returns the CA certificates, reloaded if the file changed. If the reload fails, the
+// previous certificates are kept.
+func (fs *files) caPool() (*x509.CertPool, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	mod, err := modification(fs.caFile)
+	if err == nil && mod == fs.caMod {
+		return fs.pool, nil
+	}
+
+	if err == nil {
+		var data []byte
+		data, err = os.ReadFile(fs.caFile)
+		if err == nil {
+			pool := x509.NewCertPool()
+			if pool.AppendCertsFromPEM(data) {
+				if fs.pool != nil {
+					klog.Info("reloaded TLS CA certificate from ", fs.caFile)
+				}
+				fs.pool, fs.caMod = pool, mod
+				return fs.pool, nil
+			}
+			err = fmt.Errorf("no certificate found in %s", fs.caFile)
+		}
+	}
+
+	if fs.pool == nil {
+		return nil, err
+	}
+
+	klog.Warning("failed to reload TLS CA certificate, keeping the previous one: ", err)
+	return fs.pool, nil
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlsflags
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCert writes a certificate and its key, signed by the parent (self-signed if nil).
+func writeCert(t *testing.T, dir, name, cn string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, serial int64) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	if parent == nil {
+		template.IsCA = true
+		template.KeyUsage = x509.KeyUsageCertSign
+		template.BasicConstraintsValid = true
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(path string, block *pem.Block) {
+		if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+			t.Fatal(err)
+		}
+		// make sure the change is seen, whatever the timestamps resolution
+		mod := time.Now().Add(time.Duration(serial) * time.Second)
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(filepath.Join(dir, name+".crt"), &pem.Block{Type: "CERTIFICATE", Bytes: der})
+	write(filepath.Join(dir, name+".key"), &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, key
+}
+
+func TestReload(t *testing.T) {
+	dir := t.TempDir()
+
+	ca1, ca1Key := writeCert(t, dir, "ca", "CA 1", nil, nil, 1)
+	writeCert(t, dir, "tls", "server 1", ca1, ca1Key, 2)
+
+	f := &Flags{
+		KeyFile:  filepath.Join(dir, "tls.key"),
+		CertFile: filepath.Join(dir, "tls.crt"),
+		CAFile:   filepath.Join(dir, "ca.crt"),
+	}
+
+	cfg := f.Config()
+
+	checkCN := func(step, expected string) {
+		t.Helper()
+
+		cert, err := cfg.GetCertificate(nil)
+		if err != nil {
+			t.Fatal(step, ": ", err)
+		}
+
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if leaf.Subject.CommonName != expected {
+			t.Errorf("%s: expected %q, got %q", step, expected, leaf.Subject.CommonName)
+		}
+
+		clientCert, err := cfg.GetClientCertificate(nil)
+		if err != nil || clientCert != cert {
+			t.Errorf("%s: expected the same client certificate, got %v", step, err)
+		}
+	}
+
+	checkCN("initial", "server 1")
+
+	// rotation
+	writeCert(t, dir, "tls", "server 2", ca1, ca1Key, 3)
+	checkCN("rotated", "server 2")
+
+	// invalid files keep the previous key pair
+	if err := os.WriteFile(f.CertFile, []byte("not a certificate"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	checkCN("invalid", "server 2")
+
+	// CA rotation, seen by the servers at each handshake
+	ca2, ca2Key := writeCert(t, dir, "ca", "CA 2", nil, nil, 4)
+	client, _ := writeCert(t, dir, "client", "client", ca2, ca2Key, 5)
+
+	serverCfg, err := cfg.GetConfigForClient(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Verify(x509.VerifyOptions{
+		Roots:     serverCfg.ClientCAs,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		t.Errorf("expected the client to be verified with the new CA: %v", err)
+	}
+}
+
+func TestHandshakeAfterRotation(t *testing.T) {
+	dir := t.TempDir()
+
+	ca, caKey := writeCert(t, dir, "ca", "CA", nil, nil, 1)
+	writeCert(t, dir, "server", "server 1", ca, caKey, 2)
+	writeCert(t, dir, "client", "client", ca, caKey, 3)
+
+	server := &Flags{
+		KeyFile:  filepath.Join(dir, "server.key"),
+		CertFile: filepath.Join(dir, "server.crt"),
+		CAFile:   filepath.Join(dir, "ca.crt"),
+	}
+	client := &Flags{
+		KeyFile:    filepath.Join(dir, "client.key"),
+		CertFile:   filepath.Join(dir, "client.crt"),
+		CAFile:     filepath.Join(dir, "ca.crt"),
+		MinVersion: "1.3",
+	}
+
+	serverCfg := server.Config()
+	serverCfg.ClientAuth = tls.RequireAndVerifyClientCert
+
+	clientCfg := client.Config()
+	clientCfg.ServerName = "127.0.0.1"
+
+	handshake := func() string {
+		t.Helper()
+
+		c, s := net.Pipe()
+		defer c.Close()
+		defer s.Close()
+
+		errs := make(chan error, 1)
+		go func() {
+			errs <- tls.Server(s, serverCfg).Handshake()
+		}()
+
+		conn := tls.Client(c, clientCfg)
+		if err := conn.Handshake(); err != nil {
+			t.Fatal(err)
+		}
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+
+		if v := conn.ConnectionState().Version; v != tls.VersionTLS13 {
+			t.Errorf("expected TLS 1.3, got %x", v)
+		}
+
+		return conn.ConnectionState().PeerCertificates[0].Subject.CommonName
+	}
+
+	if cn := handshake(); cn != "server 1" {
+		t.Errorf("expected server 1, got %q", cn)
+	}
+
+	writeCert(t, dir, "server", "server 2", ca, caKey, 4)
+
+	if cn := handshake(); cn != "server 2" {
+		t.Errorf("expected server 2 after the rotation, got %q", cn)
+	}
+}
+
+func TestHardening(t *testing.T) {
+	if v, err := parseVersion("1.3"); err != nil || v != tls.VersionTLS13 {
+		t.Errorf("expected TLS 1.3, got %x, %v", v, err)
+	}
+	if _, err := parseVersion("1.4"); err == nil {
+		t.Error("expected an unknown version error")
+	}
+
+	suites, err := parseCipherSuites("TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256, TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suites) != 2 || suites[0] != tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256 {
+		t.Errorf("unexpected suites: %v", suites)
+	}
+
+	if _, err := parseCipherSuites("TLS_RSA_WITH_RC4_128_SHA"); err == nil {
+		t.Error("expected insecure suites to be rejected")
+	}
+}
@@ -83,6 +83,8 @@ func (s *sink) Send(op *localv1.OpItem) (err error) {
 			v = &localv1.Service{}
 		case localv1.Set_EndpointsSet:
 			v = &localv1.Endpoint{}
+		case localv1.Set_NodesSet:
+			v = &localv1.Node{}
 
 		default:
 			klog.Info("unknown set: ", set.Ref.Set)
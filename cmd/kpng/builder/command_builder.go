@@ -30,6 +30,7 @@ import (
 	"sigs.k8s.io/kpng/server/jobs/store2api"
 	"sigs.k8s.io/kpng/server/jobs/store2file"
 	"sigs.k8s.io/kpng/server/jobs/store2localdiff"
+	"sigs.k8s.io/kpng/server/pkg/endpoints"
 	"sigs.k8s.io/kpng/server/proxystore"
 
 	_ "sigs.k8s.io/kpng/backends/healthchecks"
@@ -69,7 +70,7 @@ func ToAPICmd(ctx context.Context, store *proxystore.Store, storeProducerJobSetu
 func ToFileCmd(ctx context.Context, store *proxystore.Store, storeProducerJobSetup func() (err error), storeProducerJobRun func()) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "to-file",
-		Short: "dump globalv1 state to a yaml db file",
+		Short: "dump globalv1 state to a file (yaml, json or proto)",
 	}
 
 	cfg := &store2file.Config{}
@@ -103,8 +104,12 @@ func ToLocalCmd(ctx context.Context, store *proxystore.Store, storeProducerJobSe
 
 	job := &store2localdiff.Job{}
 
+	endpointsCfg := &endpoints.Config{}
+	endpointsCfg.BindFlags(cmd.PersistentFlags())
+
 	cmd.PersistentPreRunE = func(_ *cobra.Command, _ []string) (err error) {
 		job.Store = store
+		job.Selector, err = endpointsCfg.Selector()
 		return
 	}
 
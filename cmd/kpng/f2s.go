@@ -18,17 +18,23 @@ package main
 
 import (
 	"context"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"sigs.k8s.io/kpng/server/jobs/file2store"
+	"sigs.k8s.io/kpng/server/jobs/kube2store"
 	"sigs.k8s.io/kpng/server/proxystore"
 
 	"sigs.k8s.io/kpng/cmd/kpng/builder"
 )
 
 // FIXME separate package
-var f2sInput string
+var (
+	f2sInput    string
+	f2sDebounce time.Duration
+	f2sK8sCfg   = &kube2store.K8sConfig{}
+)
 
 // file2storeCmd is a command that will read data from file, allowing you to locally simulate
 // a networking model easily and send it to a store (i.e. a backend) of your choosing.  Its commonly
@@ -38,11 +44,16 @@ func file2storeCmd() *cobra.Command {
 	// file to * command
 	f2sCmd := &cobra.Command{
 		Use:   "file",
-		Short: "poll a file to the globalv1 state",
+		Short: "watch a file to the globalv1 state",
 	}
 
 	flags := f2sCmd.PersistentFlags()
-	flags.StringVarP(&f2sInput, "input", "i", "globalv1-state.yaml", "Input file for the globalv1-state")
+	flags.StringVarP(&f2sInput, "input", "i", "globalv1-state.yaml", "Input file for the globalv1-state (YAML, JSON or protobuf-JSON) or Kubernetes manifests, or a directory of those")
+	flags.DurationVar(&f2sDebounce, "debounce", file2store.DefaultDebounce, "Time without changes to wait before reloading the input")
+
+	// f2sK8sCfg is the configuration of the conversion of Kubernetes manifests
+	f2sK8sCfg.BindFlags(flags)
+	flags.MarkHidden("record-events") // nothing to record
 
 	ctx := setupGlobal()
 	store := proxystore.New()
@@ -61,6 +72,8 @@ func file2storeCmdRun(ctx context.Context, store *proxystore.Store) {
 	f2s := &file2store.Job{
 		FilePath: f2sInput,
 		Store:    store,
+		Config:   f2sK8sCfg,
+		Debounce: f2sDebounce,
 	}
 	f2s.Run(ctx)
 }
@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
+	"sync"
 
 	"sigs.k8s.io/kpng/cmd/kpng/builder"
 
@@ -27,9 +29,12 @@ import (
 
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
 
 	// this depends on the kpng server to run the integrated app
 	"sigs.k8s.io/kpng/server/jobs/kube2store"
+	"sigs.k8s.io/kpng/server/jobs/leader2store"
+	"sigs.k8s.io/kpng/server/jobs/store2snapshot"
 	"sigs.k8s.io/kpng/server/proxystore"
 )
 
@@ -43,8 +48,18 @@ var (
 	// to in-cluster configuration using internal pod service accounts.
 	kubeServer string
 
-	kubeClient = &kubernetes.Clientset{}
-	k8sCfg     = &kube2store.K8sConfig{}
+	kubeClient  = &kubernetes.Clientset{}
+	k8sCfg      = &kube2store.K8sConfig{}
+	snapshotCfg = &store2snapshot.Config{}
+	haCfg       = &leader2store.Config{}
+
+	// clusterName is the name of the watched cluster, set in the topologies.
+	clusterName string
+
+	// clusterKubeConfigs are the kubeconfigs of the clusters to watch, by cluster name, when
+	// several clusters are merged in the store.
+	clusterKubeConfigs map[string]string
+	clusterClients     map[string]*kubernetes.Clientset
 )
 
 // kube2storeCmd generates the kube-to-store command, which is the "normal" way to run KPNG,
@@ -59,18 +74,34 @@ func kube2storeCmd() *cobra.Command {
 	flags := k2sCmd.PersistentFlags()
 	flags.StringVar(&kubeConfig, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster. Defaults to envvar KUBECONFIG.")
 	flags.StringVar(&kubeServer, "server", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	flags.StringVar(&clusterName, "cluster-name", "", "The name of the cluster, exposed in the topologies.")
+	flags.StringToStringVar(&clusterKubeConfigs, "clusters", nil, "Watch several clusters, merged in one state (name=kubeconfig,...). Services with the "+kube2store.AnnotationExport+"=true annotation have IPs and endpoints from every cluster exporting them. Nodes are named <cluster>/<node> in the store, so watchers request them that way.")
 
 	// k8sCfg is the configuration of how we interact w/ and watch the K8s APIServer
 	k8sCfg.BindFlags(k2sCmd.PersistentFlags())
 
+	// snapshotCfg allows a warm start from the last saved store state
+	snapshotCfg.BindFlags(k2sCmd.PersistentFlags())
+
+	// haCfg allows to run several replicas, with an elected leader watching the APIServer
+	haCfg.BindFlags(k2sCmd.PersistentFlags())
+
 	ctx := setupGlobal()
 	store := proxystore.New()
+	setup := func() error {
+		if err := kube2storeCmdSetup(); err != nil {
+			return err
+		}
+		snapshotCfg.Load(store)
+		return nil
+	}
 	run := func() {
+		go (&store2snapshot.Job{Store: store, Config: snapshotCfg}).Run(ctx)
 		kube2storeCmdRun(ctx, store)
 	}
-	k2sCmd.AddCommand(builder.ToAPICmd(ctx, store, kube2storeCmdSetup, run))
-	k2sCmd.AddCommand(builder.ToFileCmd(ctx, store, kube2storeCmdSetup, run))
-	k2sCmd.AddCommand(builder.ToLocalCmd(ctx, store, kube2storeCmdSetup, run))
+	k2sCmd.AddCommand(builder.ToAPICmd(ctx, store, setup, run))
+	k2sCmd.AddCommand(builder.ToFileCmd(ctx, store, setup, run))
+	k2sCmd.AddCommand(builder.ToLocalCmd(ctx, store, setup, run))
 
 	return k2sCmd
 }
@@ -78,6 +109,17 @@ func kube2storeCmd() *cobra.Command {
 // kube2storeCmdSetup performs any neccessary setup steps that need to happen
 // before the kube2store job starts.
 func kube2storeCmdSetup() error {
+	if len(clusterKubeConfigs) != 0 {
+		if err := clustersSetup(); err != nil {
+			return err
+		}
+
+		if !haCfg.Enabled {
+			return nil
+		}
+		// the leader election lease is in the cluster of --kubeconfig/--server
+	}
+
 	if kubeConfig == "" {
 		kubeConfig = os.Getenv("KUBECONFIG")
 	}
@@ -93,11 +135,91 @@ func kube2storeCmdSetup() error {
 	return nil
 }
 
-// kube2storeCmdRun kicks off the kube2store job.
+// clustersSetup builds the clients of the clusters to watch.
+func clustersSetup() error {
+	if clusterName != "" {
+		return fmt.Errorf("--cluster-name can't be used with --clusters")
+	}
+
+	clusterClients = make(map[string]*kubernetes.Clientset, len(clusterKubeConfigs))
+
+	for name, path := range clusterKubeConfigs {
+		cfg, err := clientcmd.BuildConfigFromFlags("", path)
+		if err != nil {
+			return fmt.Errorf("Error building kubeconfig of cluster %s: %w", name, err)
+		}
+
+		clusterClients[name], err = kubernetes.NewForConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("Error building kubernetes clientset of cluster %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// kube2storeCmdRun kicks off the kube2store job, or the leader election when several replicas
+// are running.
 func kube2storeCmdRun(ctx context.Context, store *proxystore.Store) {
-	kube2store.Job{
+	if !haCfg.Enabled {
+		kube2storeCmdFeed(ctx, store)
+		store.Close()
+		return
+	}
+
+	job := &leader2store.Job{
 		Kube:   kubeClient,
 		Store:  store,
-		Config: k8sCfg,
-	}.Run(ctx)
+		Config: haCfg,
+		Lead: func(ctx context.Context) {
+			kube2storeCmdFeed(ctx, store)
+		},
+	}
+
+	if err := job.Run(ctx); err != nil {
+		klog.Exit("leader election failed: ", err)
+	}
+}
+
+// kube2storeCmdFeed runs the kube2store job, or one job per cluster, until the context is done.
+func kube2storeCmdFeed(ctx context.Context, store *proxystore.Store) {
+	if len(clusterClients) == 0 {
+		kube2store.Job{
+			Kube:    kubeClient,
+			Store:   store,
+			Config:  k8sCfg,
+			Cluster: clusterName,
+		}.Feed(ctx)
+		return
+	}
+
+	names := make([]string, 0, len(clusterClients))
+	for name := range clusterClients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	clusters := kube2store.NewClusters(names...)
+
+	wg := sync.WaitGroup{}
+	for _, name := range names {
+		config := *k8sCfg
+		if config.RecordFile != "" {
+			config.RecordFile += "." + name
+		}
+
+		job := kube2store.Job{
+			Kube:     clusterClients[name],
+			Store:    store,
+			Config:   &config,
+			Cluster:  name,
+			Clusters: clusters,
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			job.Feed(ctx)
+		}()
+	}
+	wg.Wait()
 }
@@ -59,6 +59,7 @@ func main() {
 	cmd.AddCommand(
 		kube2storeCmd(), // no-op?
 		file2storeCmd(),
+		replay2storeCmd(),
 		api2storeCmd(),
 		local2sinkCmd(),
 		versionCmd(),
@@ -72,6 +73,17 @@ func main() {
 func setupPrometheusServer(ctx context.Context, address string) {
 	prometheus.MustRegister(metrics.Kpng_k8s_api_events)
 	prometheus.MustRegister(metrics.Kpng_node_local_events)
+	prometheus.MustRegister(metrics.Kpng_local_state_cache_hits)
+	prometheus.MustRegister(metrics.Kpng_local_state_cache_misses)
+	prometheus.MustRegister(metrics.Kpng_store_revision)
+	prometheus.MustRegister(metrics.Kpng_node_applied_revision)
+	prometheus.MustRegister(metrics.Kpng_node_apply_duration_seconds)
+	prometheus.MustRegister(metrics.Kpng_node_apply_failed)
+	prometheus.MustRegister(metrics.Kpng_node_apply_failures)
+	prometheus.MustRegister(metrics.Kpng_local_watchers)
+	prometheus.MustRegister(metrics.Kpng_local_send_duration_seconds)
+	prometheus.MustRegister(metrics.Kpng_local_stalled_watchers)
+	prometheus.MustRegister(metrics.Kpng_local_coalesced_revisions)
 	klog.Infof("exporting metrics to: %v ", address)
 	metrics.StartMetricsServer(address, ctx.Done())
 }
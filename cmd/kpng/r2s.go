@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/kpng/cmd/kpng/builder"
+	"sigs.k8s.io/kpng/server/jobs/kube2store"
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+// FIXME separate package
+var (
+	replayInput    string
+	replayRealTime bool
+	replayK8sCfg   = &kube2store.K8sConfig{}
+)
+
+// replay2storeCmd replays Kubernetes events recorded with kube --record-events, to reproduce
+// the states kube2store went through without a cluster.
+func replay2storeCmd() *cobra.Command {
+	// replay to * command
+	r2sCmd := &cobra.Command{
+		Use:   "replay",
+		Short: "replay recorded Kubernetes events to the globalv1 state",
+	}
+
+	flags := r2sCmd.PersistentFlags()
+	flags.StringVarP(&replayInput, "input", "i", "kube-events.json", "Input file with the recorded events")
+	flags.BoolVar(&replayRealTime, "real-time", false, "Replay the events at the recorded pace instead of as fast as possible")
+
+	replayK8sCfg.BindFlags(flags)
+	flags.MarkHidden("record-events") // the replay can't be recorded
+
+	ctx := setupGlobal()
+	store := proxystore.New()
+	run := func() {
+		replay2storeCmdRun(ctx, store)
+	}
+	r2sCmd.AddCommand(builder.ToAPICmd(ctx, store, nil, run))
+	r2sCmd.AddCommand(builder.ToFileCmd(ctx, store, nil, run))
+	r2sCmd.AddCommand(builder.ToLocalCmd(ctx, store, nil, run))
+
+	return r2sCmd
+}
+
+// replay2storeCmdRun kicks off the replay job.
+func replay2storeCmdRun(ctx context.Context, store *proxystore.Store) {
+	kube2store.ReplayJob{
+		FilePath: replayInput,
+		RealTime: replayRealTime,
+		Store:    store,
+		Config:   replayK8sCfg,
+	}.Run(ctx)
+}
@@ -32,7 +32,7 @@ import (
 )
 
 var (
-	extLBsOnly     = flag.Bool("load-balancers-only", false, "only manage services of type LoadBalancer")
+	extLBsOnly     = flag.Bool("load-balancers-only", false, "only manage services of type LoadBalancer (--watch-service-types=LoadBalancer also filters them server-side)")
 	iptChainPrefix = flag.String("iptables-chain-prefix", "k8s-", "prefix of iptables chains")
 	dryRun         = flag.Bool("dry-run", false, "dry run")
 )
@@ -56,7 +56,7 @@ func main() {
 
 	srv := grpc.NewServer()
 
-	localv1.RegisterSetsServer(srv, watchSrv{})
+	localv1.RegisterSetsServer(srv, watchSrv{specs: flag.Args()})
 
 	lis := server.MustListen(*bindSpec)
 	srv.Serve(lis)
@@ -66,6 +66,9 @@ var syncItem = &localv1.OpItem{Op: &localv1.OpItem_Sync{}}
 
 type watchSrv struct {
 	localv1.UnimplementedSetsServer
+
+	// specs of the states to send, one per request ("<services>:<endpoints per service>")
+	specs []string
 }
 
 func (s watchSrv) Watch(res localv1.Sets_WatchServer) error {
@@ -81,7 +84,11 @@ func (s watchSrv) Watch(res localv1.Sets_WatchServer) error {
 
 		log.Print("got watch request: ", req)
 
-		injectState(i, w)
+		if i == 0 && req.Batching != nil {
+			w.SetSink(localv1.NewBatcher(res, req.Batching))
+		}
+
+		s.injectState(i, w)
 		i++
 
 		// send diff
@@ -93,7 +100,7 @@ func (s watchSrv) Watch(res localv1.Sets_WatchServer) error {
 		w.Reset(lightdiffstore.ItemDeleted)
 
 		// change set sent
-		w.SendSync()
+		w.SendSync(i)
 
 		if w.Err != nil {
 			return w.Err
@@ -101,20 +108,18 @@ func (s watchSrv) Watch(res localv1.Sets_WatchServer) error {
 	}
 }
 
-func injectState(rev uint64, w *watchstate.WatchState) {
+func (s watchSrv) injectState(rev uint64, w *watchstate.WatchState) {
 	time.Sleep(*sleepFlag)
 
 	svcs := w.StoreFor(localv1.Set_ServicesSet)
 	seps := w.StoreFor(localv1.Set_EndpointsSet)
 
-	args := flag.Args()
-
-	if int(rev) >= len(args) {
+	if int(rev) >= len(s.specs) {
 		klog.Info("waiting forever (rev: ", rev, ")")
 		select {} // tests finished, sleep forever
 	}
 
-	spec := args[rev]
+	spec := s.specs[rev]
 
 	var nSvc, nEpPerSvc int
 
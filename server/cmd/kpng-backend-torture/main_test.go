@@ -0,0 +1,112 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+
+	"sigs.k8s.io/kpng/api/localv1"
+)
+
+// BenchmarkWatch measures the transfer of a full state of 50k endpoints, with and without
+// batching. Run with: go test -bench . -benchtime 10x ./cmd/kpng-backend-torture/
+func BenchmarkWatch(b *testing.B) {
+	log.SetOutput(io.Discard)
+	*sleepFlag = 0
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	srv := grpc.NewServer()
+	localv1.RegisterSetsServer(srv, watchSrv{specs: []string{"1000:50"}})
+
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer conn.Close()
+
+	for _, bc := range []struct {
+		name     string
+		batching *localv1.BatchingReq
+	}{
+		{"unbatched", nil},
+		{"batched", &localv1.BatchingReq{MaxBytes: 1 << 20}},
+		{"batched-gzip", &localv1.BatchingReq{MaxBytes: 1 << 20, Compressions: []localv1.Compression{localv1.Compression_Gzip}}},
+		{"batched-zstd", &localv1.BatchingReq{MaxBytes: 1 << 20, Compressions: []localv1.Compression{localv1.Compression_Zstd}}},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			msgs, wireBytes, ops := 0, 0, 0
+
+			for i := 0; i < b.N; i++ {
+				ctx, cancel := context.WithCancel(context.Background())
+
+				watch, err := localv1.NewSetsClient(conn).Watch(ctx)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				if err = watch.Send(&localv1.WatchReq{NodeName: "node", Batching: bc.batching}); err != nil {
+					b.Fatal(err)
+				}
+
+				for {
+					op, err := watch.Recv()
+					if err != nil {
+						b.Fatal(err)
+					}
+
+					msgs++
+					wireBytes += proto.Size(op)
+
+					if batch := op.GetBatch(); batch != nil {
+						batchOps, err := batch.Unpack()
+						if err != nil {
+							b.Fatal(err)
+						}
+						ops += len(batchOps)
+						continue
+					}
+
+					ops++
+
+					if op.GetSync() != nil {
+						break
+					}
+				}
+
+				cancel()
+			}
+
+			b.ReportMetric(float64(msgs)/float64(b.N), "msgs/op")
+			b.ReportMetric(float64(wireBytes)/float64(b.N), "wire-B/op")
+			b.ReportMetric(float64(ops)/float64(b.N), "ops/op")
+		})
+	}
+}
@@ -1,6 +1,6 @@
 module sigs.k8s.io/kpng/server
 
-go 1.20
+go 1.21
 
 require (
 	github.com/cespare/xxhash v1.1.0
@@ -21,9 +21,9 @@ require (
 	google.golang.org/protobuf v1.28.1
 	gopkg.in/yaml.v2 v2.4.0
 	gopkg.in/yaml.v3 v3.0.1 // indirect
-	k8s.io/api v0.25.2
-	k8s.io/apimachinery v0.25.4
-	k8s.io/client-go v0.25.2
+	k8s.io/api v0.29.15
+	k8s.io/apimachinery v0.29.15
+	k8s.io/client-go v0.29.15
 	k8s.io/klog/v2 v2.80.1
 	sigs.k8s.io/structured-merge-diff/v4 v4.2.3 // indirect
 )
@@ -41,13 +41,17 @@ require (
 	sigs.k8s.io/kpng/client v0.0.0-20221010162120-e8ab99a40f22
 )
 
-require github.com/prometheus/client_golang v1.12.1
+require (
+	github.com/fsnotify/fsnotify v1.6.0
+	github.com/prometheus/client_golang v1.12.1
+)
 
 require (
 	github.com/beorn7/perks v1.0.1 // indirect
 	github.com/cespare/xxhash/v2 v2.1.2 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/emicklei/go-restful/v3 v3.9.0 // indirect
+	github.com/evanphx/json-patch v4.12.0+incompatible // indirect
 	github.com/go-openapi/jsonpointer v0.19.5 // indirect
 	github.com/go-openapi/jsonreference v0.20.0 // indirect
 	github.com/go-openapi/swag v0.22.3 // indirect
@@ -65,6 +69,7 @@ require (
 	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
 	github.com/onsi/ginkgo/v2 v2.9.1 // indirect
 	github.com/onsi/gomega v1.27.3 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
 	github.com/prometheus/client_model v0.2.0 // indirect
 	github.com/prometheus/common v0.32.1 // indirect
 	github.com/prometheus/procfs v0.7.3 // indirect
@@ -28,6 +28,7 @@ import (
 
 	"sigs.k8s.io/kpng/api/localv1"
 	"sigs.k8s.io/kpng/client/localsink"
+	"sigs.k8s.io/kpng/client/statedigest"
 	"sigs.k8s.io/kpng/client/tlsflags"
 
 	"sigs.k8s.io/kpng/server/pkg/apiwatch"
@@ -52,6 +53,9 @@ func (c *Config) BindFlags(flags *pflag.FlagSet) {
 type Job struct {
 	apiwatch.Watch
 	Sink localsink.Sink
+
+	// digest of the sink's state, so the server can resume the watch on reconnect
+	digest *statedigest.Digest
 }
 
 func New(sink localsink.Sink) *Job {
@@ -66,6 +70,8 @@ func New(sink localsink.Sink) *Job {
 func (j *Job) Run(ctx context.Context) {
 	j.Sink.Setup()
 
+	j.digest = statedigest.New()
+
 	for {
 		err := j.run(ctx)
 
@@ -116,7 +122,10 @@ func (j *Job) runLoop(watch localv1.Sets_WatchClient) (err error) {
 	}
 
 	err = watch.Send(&localv1.WatchReq{
-		NodeName: nodeName,
+		NodeName:    nodeName,
+		StateDigest: j.digest.Sum(),
+		Filter:      localsink.WatchFilter(j.Sink),
+		Applied:     localsink.ApplyStatus(j.Sink),
 	})
 	if err != nil {
 		return
@@ -130,6 +139,8 @@ func (j *Job) runLoop(watch localv1.Sets_WatchClient) (err error) {
 			return
 		}
 
+		j.digest.Apply(op)
+
 		switch op.Op.(type) {
 		case *localv1.OpItem_Reset_:
 			j.Sink.Reset()
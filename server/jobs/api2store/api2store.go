@@ -40,16 +40,30 @@ type Job struct {
 func (j *Job) Run(ctx context.Context) {
 	defer j.Store.Close()
 
+	j.Feed(ctx)
+}
+
+// Feed feeds the store until the context is done, without closing it, so another producer can
+// take over the store afterwards.
+func (j *Job) Feed(ctx context.Context) {
 	for {
 		err := j.run(ctx)
 
-		if err == context.Canceled || grpc.Code(err) == codes.Canceled {
+		if ctx.Err() != nil || err == context.Canceled || grpc.Code(err) == codes.Canceled {
 			klog.Info("context canceled, closing watch")
 			return
 		}
 
 		klog.Error("watch error: ", err)
-		time.Sleep(5 * time.Second) // TODO parameter?
+
+		if j.Failover() {
+			continue // try the next server right away
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second): // TODO parameter?
+		}
 	}
 }
 
@@ -69,6 +83,11 @@ func (j *Job) run(ctx context.Context) (err error) {
 		return
 	}
 
+	// the server may have deleted entries while we were not watching, or be a new server: the
+	// entries it doesn't send again before the first sync are stale
+	j.Store.MarkProvisional()
+	synced := false
+
 	for {
 		if ctx.Err() != nil {
 			err = ctx.Err()
@@ -139,7 +158,8 @@ func (j *Job) run(ctx context.Context) (err error) {
 			}
 		}
 
-		if len(todo) != 0 {
+		if len(todo) != 0 || !synced {
+			synced = true
 			j.Store.Update(func(tx *proxystore.Tx) {
 				for _, op := range todo {
 					op(tx)
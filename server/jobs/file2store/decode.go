@@ -0,0 +1,66 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file2store
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/kpng/server/jobs/store2file"
+)
+
+var inputExts = map[string]bool{".yaml": true, ".yml": true, ".json": true, ".pb": true}
+
+// inputFiles returns the input files of dir, in name order.
+func inputFiles(dir string) (files []string, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !inputExts[filepath.Ext(entry.Name())] {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	return
+}
+
+// stateFormat returns the store2file format of a state file. Binary files are recognized by
+// their extension, JSON by their content.
+func stateFormat(path string, data []byte) string {
+	if format := store2file.FormatFromPath(path); format == store2file.Proto {
+		return format
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) != 0 && trimmed[0] == '{' {
+		return store2file.JSON
+	}
+
+	return store2file.YAML
+}
+
+// decodeState decodes a YAML, JSON (protobuf-JSON or Go field names) or binary protobuf state.
+func decodeState(path string, data []byte) (*store2file.GlobalState, error) {
+	state, err := store2file.Decode(stateFormat(path, data), data)
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
@@ -18,147 +18,263 @@ package file2store
 
 import (
 	"context"
-	"io/ioutil"
+	"errors"
 	"os"
 	"path"
+	"path/filepath"
 	"time"
 
 	"github.com/cespare/xxhash"
-	"gopkg.in/yaml.v2"
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/kpng/api/globalv1"
 	"sigs.k8s.io/kpng/client/lightdiffstore"
+	"sigs.k8s.io/kpng/server/jobs/kube2store"
 	"sigs.k8s.io/kpng/server/jobs/store2file"
 	"sigs.k8s.io/kpng/server/pkg/server/watchstate"
 	"sigs.k8s.io/kpng/server/proxystore"
 	"sigs.k8s.io/kpng/server/serde"
 )
 
+// DefaultDebounce is the default delay between a file change and the reload.
+const DefaultDebounce = 200 * time.Millisecond
+
 type Job struct {
+	// FilePath is a state file (YAML, JSON or protobuf-JSON), a Kubernetes manifest, or a
+	// directory of those. The files of a directory are merged.
 	FilePath string
 	Store    *proxystore.Store
+	// Config is the conversion configuration of the Kubernetes manifests
+	Config *kube2store.K8sConfig
+	// Debounce is the time without changes to wait before reloading (DefaultDebounce if 0)
+	Debounce time.Duration
+
+	w         *watchstate.WatchState
+	manifests *kube2store.StaticSource
 }
 
 func (j *Job) Run(ctx context.Context) {
-	configPath := j.FilePath
-	store := j.Store
+	j.w = watchstate.New(nil, proxystore.AllSets)
 
-	w := watchstate.New(nil, proxystore.AllSets)
+	for {
+		err := j.watch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
 
-	mtime := time.Time{}
+		klog.Info("failed to watch ", j.FilePath, ", retrying: ", err)
 
-	for range time.Tick(time.Second) {
-		if ctx.Err() != nil {
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
 			return
 		}
+	}
+}
 
-		stat, err := os.Stat(configPath)
-		if err != nil {
-			klog.Info("failed to stat config: ", err)
-			continue
+// watch loads the input then reloads it on changes, until the context is done or the watch fails.
+func (j *Job) watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	// files are often replaced instead of written (editors, ConfigMap volumes), so we
+	// watch their directory
+	dir := filepath.Dir(j.FilePath)
+	if stat, err := os.Stat(j.FilePath); err == nil && stat.IsDir() {
+		dir = j.FilePath
+	}
+
+	if err := watcher.Add(dir); err != nil {
+		return err
+	}
+
+	// load after the watch is set to not miss changes
+	j.load()
+
+	debounce := j.Debounce
+	if debounce == 0 {
+		debounce = DefaultDebounce
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return errors.New("watch closed")
+			}
+
+			if filepath.Clean(event.Name) == filepath.Clean(dir) && event.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				return errors.New("watched directory removed")
+			}
+
+			// any change in the directory may be a change of the input (ie: symlink
+			// swaps), and reloading an unchanged input has no effect.
+			timer.Reset(debounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return errors.New("watch closed")
+			}
+			return err
+
+		case <-timer.C:
+			j.load()
 		}
+	}
+}
 
-		if !stat.ModTime().After(mtime) {
-			continue
+// load reads the input and updates the store. The store is left unchanged if any file is invalid.
+func (j *Job) load() {
+	files := []string{j.FilePath}
+
+	stat, err := os.Stat(j.FilePath)
+	if err != nil {
+		klog.Info("failed to stat config: ", err)
+		return
+	}
+
+	if stat.IsDir() {
+		files, err = inputFiles(j.FilePath)
+		if err != nil {
+			klog.Info("failed to read config directory: ", err)
+			return
 		}
+	}
 
-		mtime = stat.ModTime()
+	state := &store2file.GlobalState{}
+	objects := []runtime.Object{}
 
-		configBytes, err := ioutil.ReadFile(configPath)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
 		if err != nil {
 			klog.Info("failed to read config: ", err)
+			return
+		}
+
+		if stateFormat(file, data) != store2file.Proto && isManifest(data) {
+			fileObjects, err := kube2store.DecodeManifests(data)
+			if err != nil {
+				klog.Infof("failed to parse manifests in %s: %v", file, err)
+				return
+			}
+
+			objects = append(objects, fileObjects...)
 			continue
 		}
 
-		state := &store2file.GlobalState{}
-		err = yaml.UnmarshalStrict(configBytes, state)
+		fileState, err := decodeState(file, data)
 		if err != nil {
-			klog.Info("failed to parse config: ", err)
-			continue
+			klog.Infof("failed to parse config %s: %v", file, err)
+			return
 		}
 
-		diffNodes := w.StoreFor(proxystore.Nodes)
-		diffSvcs := w.StoreFor(proxystore.Services)
-		diffEPs := w.StoreFor(proxystore.Endpoints)
+		state.Nodes = append(state.Nodes, fileState.Nodes...)
+		state.Services = append(state.Services, fileState.Services...)
+	}
 
-		for _, node := range state.Nodes {
-			diffNodes.Set([]byte(node.Name), serde.Hash(node), node)
-		}
+	if len(objects) != 0 || j.manifests != nil {
+		j.setObjects(objects)
+	}
 
-		for _, se := range state.Services {
-			svc := se.Service
+	j.setState(state)
+}
 
-			if svc.Namespace == "" {
-				svc.Namespace = "default"
-			}
+// setState updates the store with the changes of the state.
+func (j *Job) setState(state *store2file.GlobalState) {
+	w := j.w
 
-			si := &globalv1.ServiceInfo{
-				Service: se.Service,
-			}
+	diffNodes := w.StoreFor(proxystore.Nodes)
+	diffSvcs := w.StoreFor(proxystore.Services)
+	diffEPs := w.StoreFor(proxystore.Endpoints)
+
+	for _, node := range state.Nodes {
+		diffNodes.Set([]byte(node.Name), serde.Hash(node), node)
+	}
+
+	for _, se := range state.Services {
+		svc := se.Service
+
+		if svc.Namespace == "" {
+			svc.Namespace = "default"
+		}
 
-			fullName := []byte(svc.Namespace + "/" + svc.Name)
+		si := &globalv1.ServiceInfo{
+			Service: se.Service,
+		}
 
-			diffSvcs.Set(fullName, serde.Hash(si), si)
+		fullName := []byte(svc.Namespace + "/" + svc.Name)
 
-			if len(se.Endpoints) != 0 {
-				h := xxhash.New()
-				for _, ep := range se.Endpoints {
-					ep.Namespace = svc.Namespace
-					ep.SourceName = svc.Name
-					ep.ServiceName = svc.Name
+		diffSvcs.Set(fullName, serde.Hash(si), si)
 
-					if ep.Conditions == nil {
-						ep.Conditions = &globalv1.EndpointConditions{Ready: true}
-					}
+		if len(se.Endpoints) != 0 {
+			h := xxhash.New()
+			for _, ep := range se.Endpoints {
+				ep.Namespace = svc.Namespace
+				ep.SourceName = svc.Name
+				ep.ServiceName = svc.Name
 
-					h.Write(serde.Marshal(ep))
+				if ep.Conditions == nil {
+					ep.Conditions = &globalv1.EndpointConditions{Ready: true, Serving: true}
 				}
 
-				diffEPs.Set(fullName, h.Sum64(), se.Endpoints)
+				h.Write(serde.Marshal(ep))
 			}
-		}
 
-		store.Update(func(tx *proxystore.Tx) {
-			for _, u := range diffNodes.Updated() {
-				klog.Info("U node ", string(u.Key))
-				tx.SetNode(u.Value.(*globalv1.Node))
-			}
-			for _, u := range diffSvcs.Updated() {
-				klog.Info("U service ", string(u.Key))
-				si := u.Value.(*globalv1.ServiceInfo)
-				tx.SetService(si.Service)
-			}
-			for _, u := range diffEPs.Updated() {
-				klog.Info("U endpoints ", string(u.Key))
-				key := string(u.Key)
-				eis := u.Value.([]*globalv1.EndpointInfo)
+			diffEPs.Set(fullName, h.Sum64(), se.Endpoints)
+		}
+	}
 
-				tx.SetEndpointsOfSource(path.Dir(key), path.Base(key), eis)
-			}
+	j.Store.Update(func(tx *proxystore.Tx) {
+		for _, u := range diffNodes.Updated() {
+			klog.Info("U node ", string(u.Key))
+			tx.SetNode(u.Value.(*globalv1.Node))
+		}
+		for _, u := range diffSvcs.Updated() {
+			klog.Info("U service ", string(u.Key))
+			si := u.Value.(*globalv1.ServiceInfo)
+			tx.SetService(si.Service)
+		}
+		for _, u := range diffEPs.Updated() {
+			klog.Info("U endpoints ", string(u.Key))
+			key := string(u.Key)
+			eis := u.Value.([]*globalv1.EndpointInfo)
 
-			for _, d := range diffEPs.Deleted() {
-				klog.Info("D endpoints ", string(d.Key))
-				key := string(d.Key)
-				tx.DelEndpointsOfSource(path.Dir(key), path.Base(key))
-			}
-			for _, d := range diffSvcs.Deleted() {
-				klog.Info("D service ", string(d.Key))
-				key := string(d.Key)
-				tx.DelService(path.Dir(key), path.Base(key))
-			}
-			for _, d := range diffNodes.Deleted() {
-				klog.Info("D node ", string(d.Key))
-				tx.DelNode(string(d.Key))
-			}
+			tx.SetEndpointsOfSource(path.Dir(key), path.Base(key), eis)
+		}
 
-			for _, set := range proxystore.AllSets {
-				tx.SetSync(set)
-			}
-		})
+		for _, d := range diffEPs.Deleted() {
+			klog.Info("D endpoints ", string(d.Key))
+			key := string(d.Key)
+			tx.DelEndpointsOfSource(path.Dir(key), path.Base(key))
+		}
+		for _, d := range diffSvcs.Deleted() {
+			klog.Info("D service ", string(d.Key))
+			key := string(d.Key)
+			tx.DelService(path.Dir(key), path.Base(key))
+		}
+		for _, d := range diffNodes.Deleted() {
+			klog.Info("D node ", string(d.Key))
+			tx.DelNode(string(d.Key))
+		}
 
 		for _, set := range proxystore.AllSets {
-			w.StoreFor(set).Reset(lightdiffstore.ItemDeleted)
+			tx.SetSync(set)
 		}
+	})
+
+	for _, set := range proxystore.AllSets {
+		w.StoreFor(set).Reset(lightdiffstore.ItemDeleted)
 	}
 }
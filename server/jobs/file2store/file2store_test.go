@@ -0,0 +1,148 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file2store
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+const (
+	yamlState = `
+nodes:
+- name: node-1
+services:
+- service:
+    name: svc-yaml
+    type: ClusterIP
+  endpoints:
+  - endpoint:
+      ips:
+        v4: [10.1.0.1]
+`
+	goJSONState = `{
+  "Nodes": [{"Name": "node-2"}],
+  "Services": [{"Service": {"Name": "svc-json", "Type": "ClusterIP"}}]
+}`
+	protoJSONState = `{
+  "Services": [{
+    "Service": {
+      "Namespace": "ns",
+      "Name": "svc-protojson",
+      "IPs": {"ClusterIPs": {"V4": ["10.0.0.1"]}},
+      "Ports": [{"Protocol": "TCP", "Port": 80}]
+    },
+    "Endpoints": [{
+      "Endpoint": {"IPs": {"V4": ["10.1.0.2"]}},
+      "LastChangeTriggerTime": "1666000000000000000"
+    }]
+  }]
+}`
+)
+
+func TestDecodeState(t *testing.T) {
+	for _, data := range []string{yamlState, goJSONState, protoJSONState} {
+		state, err := decodeState("state", []byte(data))
+		if err != nil {
+			t.Errorf("failed to decode %s: %v", data, err)
+			continue
+		}
+		if len(state.Services) != 1 {
+			t.Errorf("expected 1 service in %s, got %d", data, len(state.Services))
+		}
+	}
+
+	if _, err := decodeState("state", []byte(`{"Services": [{"Service": {"Unknown": 1}}]}`)); err == nil {
+		t.Error("unknown fields should be rejected")
+	}
+}
+
+func TestJobDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, data string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("a.yaml", yamlState)
+	write("b.json", goJSONState)
+	write("c.json", protoJSONState)
+	write("ignored.txt", "not a state")
+
+	store := proxystore.New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go (&Job{FilePath: dir, Store: store, Debounce: 10 * time.Millisecond}).Run(ctx)
+
+	services := func() string {
+		names := []string{}
+		if store.Rev() == 0 {
+			return "" // not loaded yet
+		}
+		store.View(0, func(tx *proxystore.Tx) {
+			if !tx.AllSynced() {
+				return
+			}
+			tx.Each(proxystore.Services, func(kv *proxystore.KV) bool {
+				names = append(names, kv.Namespace+"/"+kv.Name)
+				return true
+			})
+		})
+		sort.Strings(names)
+		return strings.Join(names, " ")
+	}
+
+	waitFor := func(expected string) {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if services() == expected {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("expected services %q, got %q", expected, services())
+	}
+
+	waitFor("default/svc-json default/svc-yaml ns/svc-protojson")
+
+	// changes are detected
+	if err := os.Remove(filepath.Join(dir, "b.json")); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("default/svc-yaml ns/svc-protojson")
+
+	write("a.yaml", strings.Replace(yamlState, "svc-yaml", "svc-renamed", 1))
+	waitFor("default/svc-renamed ns/svc-protojson")
+
+	// invalid files don't change the state
+	write("d.yaml", "invalid: [")
+	time.Sleep(100 * time.Millisecond)
+	waitFor("default/svc-renamed ns/svc-protojson")
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file2store
+
+import (
+	"bytes"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kpng/server/jobs/kube2store"
+)
+
+// isManifest returns true if the first document of data is a Kubernetes object.
+func isManifest(data []byte) bool {
+	typeMeta := metav1.TypeMeta{}
+	err := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096).Decode(&typeMeta)
+	return err == nil && typeMeta.Kind != ""
+}
+
+func (j *Job) setObjects(objects []runtime.Object) {
+	if j.manifests == nil {
+		config := j.Config
+		if config == nil {
+			config = &kube2store.K8sConfig{}
+		}
+		j.manifests = kube2store.NewStaticSource(j.Store, config)
+	}
+
+	klog.Info("loading ", len(objects), " objects from manifests")
+	j.manifests.Set(objects)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube2store
+
+import (
+	"sort"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+
+	"sigs.k8s.io/kpng/api/globalv1"
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+// AnnotationExport marks a service as exported ("true") to the other clusters of the store.
+const AnnotationExport = "kpng.sigs.k8s.io/export"
+
+// Clusters coordinates the jobs of the clusters feeding a store (see Job.Cluster).
+//
+// The store's sets are synced once synced in every cluster.
+//
+// Following the namespace sameness principle, services with the same namespace and name are
+// the same service: the definition of the first cluster (by name) is used. The IPs and endpoints
+// of a cluster are available to the others if it exports the service (see
+// globalv1.ServiceInfo.ExportedBy), otherwise only in the cluster itself (see
+// globalv1.ServiceInfo.LocalIPs).
+type Clusters struct {
+	mu       sync.Mutex
+	names    []string
+	synced   map[proxystore.Set]map[string]bool
+	services map[serviceKey]map[string]clusterService
+}
+
+type serviceKey struct {
+	namespace, name string
+}
+
+type clusterService struct {
+	service  *localv1.Service
+	exported bool
+}
+
+// NewClusters returns the coordinator of the named clusters.
+func NewClusters(names ...string) *Clusters {
+	return &Clusters{
+		names:    names,
+		synced:   map[proxystore.Set]map[string]bool{},
+		services: map[serviceKey]map[string]clusterService{},
+	}
+}
+
+// SetSynced records that the set is synced in the cluster, and returns true if it's synced in
+// every cluster.
+func (c *Clusters) SetSynced(cluster string, set proxystore.Set) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	synced := c.synced[set]
+	if synced == nil {
+		synced = map[string]bool{}
+		c.synced[set] = synced
+	}
+
+	synced[cluster] = true
+
+	for _, name := range c.names {
+		if !synced[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// Set sets the service of a cluster.
+func (c *Clusters) SetService(tx *proxystore.Tx, cluster string, service *localv1.Service, exported bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := serviceKey{service.Namespace, service.Name}
+
+	clusters := c.services[key]
+	if clusters == nil {
+		clusters = map[string]clusterService{}
+		c.services[key] = clusters
+	}
+
+	clusters[cluster] = clusterService{service, exported}
+
+	c.updateService(tx, key)
+}
+
+// Del deletes the service of a cluster.
+func (c *Clusters) DelService(tx *proxystore.Tx, cluster, namespace, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := serviceKey{namespace, name}
+
+	delete(c.services[key], cluster)
+
+	c.updateService(tx, key)
+}
+
+// update writes the merged service to the store.
+func (c *Clusters) updateService(tx *proxystore.Tx, key serviceKey) {
+	clusters := c.services[key]
+
+	if len(clusters) == 0 {
+		delete(c.services, key)
+		tx.DelService(key.namespace, key.name)
+		return
+	}
+
+	names := make([]string, 0, len(clusters))
+	for name := range clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	si := &globalv1.ServiceInfo{
+		Service: proto.Clone(clusters[names[0]].service).(*localv1.Service),
+	}
+	si.Service.IPs = nil
+
+	for _, name := range names {
+		cs := clusters[name]
+
+		ips := cs.service.IPs
+		if ips == nil {
+			ips = &localv1.ServiceIPs{}
+		}
+
+		if !cs.exported {
+			if si.LocalIPs == nil {
+				si.LocalIPs = map[string]*localv1.ServiceIPs{}
+			}
+			si.LocalIPs[name] = ips
+			continue
+		}
+
+		si.ExportedBy = append(si.ExportedBy, name)
+
+		if si.Service.IPs == nil {
+			si.Service.IPs = &localv1.ServiceIPs{}
+		}
+		si.Service.IPs.Add(ips)
+	}
+
+	tx.SetServiceInfo(si)
+}
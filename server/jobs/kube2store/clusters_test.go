@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube2store
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	"sigs.k8s.io/kpng/api/globalv1"
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+func TestClusters(t *testing.T) {
+	store := proxystore.New()
+	clusters := NewClusters("east", "west")
+
+	service := func(clusterIP string) *localv1.Service {
+		return &localv1.Service{
+			Namespace: "ns",
+			Name:      "svc",
+			Type:      "ClusterIP",
+			IPs:       &localv1.ServiceIPs{ClusterIPs: localv1.NewIPSet(clusterIP)},
+		}
+	}
+
+	get := func() (si *globalv1.ServiceInfo) {
+		store.View(0, func(tx *proxystore.Tx) {
+			tx.Each(proxystore.Services, func(kv *proxystore.KV) bool {
+				si = kv.Service
+				return false
+			})
+		})
+		return
+	}
+
+	store.Update(func(tx *proxystore.Tx) {
+		clusters.SetService(tx, "west", service("10.0.1.1"), true)
+		clusters.SetService(tx, "east", service("10.0.0.1"), false)
+	})
+
+	// east doesn't export its IPs
+	expected := &globalv1.ServiceInfo{
+		Service:    service("10.0.1.1"),
+		ExportedBy: []string{"west"},
+		LocalIPs: map[string]*localv1.ServiceIPs{
+			"east": {ClusterIPs: localv1.NewIPSet("10.0.0.1")},
+		},
+	}
+
+	si := get()
+	si.Hash = 0
+	if !proto.Equal(si, expected) {
+		t.Errorf("expected merged service %v, got %v", expected, si)
+	}
+
+	eastService := service("10.0.0.1")
+	eastService.IPs.ClusterIPs.Add("10.0.1.1")
+
+	for cluster, expected := range map[string]*localv1.Service{
+		"east":  eastService,
+		"west":  service("10.0.1.1"),
+		"north": service("10.0.1.1"),
+	} {
+		if svc := si.ServiceFor(cluster); !proto.Equal(svc, expected) {
+			t.Errorf("%s: expected service %v, got %v", cluster, expected, svc)
+		}
+	}
+
+	// west's service is only available in west once not exported
+	store.Update(func(tx *proxystore.Tx) {
+		clusters.SetService(tx, "west", service("10.0.1.1"), false)
+	})
+
+	if svc := get().ServiceFor("north"); svc != nil {
+		t.Errorf("expected no service in north, got %v", svc)
+	}
+
+	// deleting from a cluster keeps the other cluster's service
+	store.Update(func(tx *proxystore.Tx) {
+		clusters.DelService(tx, "east", "ns", "svc")
+	})
+
+	if si := get(); si == nil || !proto.Equal(si.ServiceFor("west"), service("10.0.1.1")) {
+		t.Errorf("expected west's service, got %v", si)
+	}
+
+	store.Update(func(tx *proxystore.Tx) {
+		clusters.DelService(tx, "west", "ns", "svc")
+	})
+
+	if si := get(); si != nil {
+		t.Errorf("expected no service, got %v", si)
+	}
+
+	// sets are synced once synced in every cluster
+	if clusters.SetSynced("east", proxystore.Services) {
+		t.Error("services should not be synced before west is")
+	}
+	if !clusters.SetSynced("west", proxystore.Services) {
+		t.Error("services should be synced")
+	}
+	if clusters.SetSynced("west", proxystore.Nodes) {
+		t.Error("nodes should not be synced before east is")
+	}
+}
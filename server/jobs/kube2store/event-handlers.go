@@ -26,6 +26,8 @@ type eventHandler struct {
 	s         *proxystore.Store
 	informer  cache.SharedIndexInformer
 	syncSet   bool
+	cluster   string
+	clusters  *Clusters
 }
 
 func (h *eventHandler) updateSync(set proxystore.Set, tx *proxystore.Tx) {
@@ -34,7 +36,20 @@ func (h *eventHandler) updateSync(set proxystore.Set, tx *proxystore.Tx) {
 	}
 
 	if h.informer.HasSynced() {
+		if h.clusters != nil && !h.clusters.SetSynced(h.cluster, set) {
+			return // wait for the other clusters
+		}
+
 		tx.SetSync(set)
 		h.syncSet = true
 	}
 }
+
+// sourceName returns the store's endpoints source name of an object (qualified by the cluster's
+// name if set, to be unique in the store).
+func (h *eventHandler) sourceName(name string) string {
+	if h.cluster == "" {
+		return name
+	}
+	return h.cluster + "/" + name
+}
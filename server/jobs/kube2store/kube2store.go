@@ -52,6 +52,9 @@ type K8sConfig struct {
 
 	NodeLabelGlobs      []string
 	NodeAnnotationGlobs []string
+
+	// RecordFile is the file where the received events are recorded, if set (see Replay).
+	RecordFile string
 }
 
 // TODO: need to find a better home for this
@@ -71,17 +74,61 @@ func (c *K8sConfig) BindFlags(flags *pflag.FlagSet) {
 		"kubernetes.io/hostname", "topology.kubernetes.io/zone", "topology.kubernetes.io/region",
 	}, "node labels to include")
 	flags.StringSliceVar(&c.NodeAnnotationGlobs, "with-node-annotations", nil, "node annotations to include")
+
+	flags.StringVar(&c.RecordFile, "record-events", "", "record the received Kubernetes events to this file, for later replay")
 }
 
 type Job struct {
-	Kube   *kubernetes.Clientset
+	Kube   kubernetes.Interface
 	Store  *proxystore.Store
 	Config *K8sConfig
+
+	// Cluster is the name of the cluster, set in the topologies. When the store has several
+	// clusters, it must be unique and Clusters must be set.
+	Cluster string
+	// Clusters coordinates the jobs of the clusters feeding the store, if there are several.
+	Clusters *Clusters
 }
 
 func (j Job) Run(ctx context.Context) {
+	j.Feed(ctx)
+	j.Store.Close()
+}
+
+// Feed feeds the store until the context is done, without closing it, so another producer can
+// take over the store afterwards.
+func (j Job) Feed(ctx context.Context) {
 	stopCh := ctx.Done()
 
+	var wrap handlerWrapper
+	if path := j.Config.RecordFile; path != "" {
+		recorder, err := CreateRecorder(path)
+		if err != nil {
+			klog.Exit("failed to create the events record: ", err)
+		}
+		defer recorder.Close()
+
+		klog.Info("recording events to ", path)
+		wrap = recorder.Wrap
+	}
+
+	j.start(stopCh, wrap)
+
+	<-stopCh
+}
+
+// handlerWrapper allows to intercept the events of a kind received by kube2store.
+type handlerWrapper func(kind string, h cache.ResourceEventHandler) cache.ResourceEventHandler
+
+// start starts the informers. If wrap is not nil, it's applied to each event handler.
+func (j Job) start(stopCh <-chan struct{}, wrap handlerWrapper) {
+	handler := func(kind string, h cache.ResourceEventHandler) cache.ResourceEventHandler {
+		if wrap == nil {
+			return h
+		}
+		return wrap(kind, h)
+	}
+
 	// start informers
 	factory := informers.NewSharedInformerFactoryWithOptions(j.Kube, time.Second*30)
 	factory.Start(stopCh)
@@ -96,19 +143,16 @@ func (j Job) Run(ctx context.Context) {
 	coreFactory := factory.Core().V1()
 
 	servicesInformer := svcFactory.Core().V1().Services().Informer()
-	servicesInformer.AddEventHandler(&serviceEventHandler{j.eventHandler(servicesInformer)})
+	servicesInformer.AddEventHandler(handler(ServiceKind, &serviceEventHandler{j.eventHandler(servicesInformer)}))
 	go servicesInformer.Run(stopCh)
 
 	nodesInformer := coreFactory.Nodes().Informer()
-	nodesInformer.AddEventHandler(&nodeEventHandler{j.eventHandler(nodesInformer)})
+	nodesInformer.AddEventHandler(handler(NodeKind, &nodeEventHandler{j.eventHandler(nodesInformer)}))
 	go nodesInformer.Run(stopCh)
 
 	slicesInformer := factory.Discovery().V1().EndpointSlices().Informer()
-	slicesInformer.AddEventHandler(&sliceEventHandler{j.eventHandler(slicesInformer)})
+	slicesInformer.AddEventHandler(handler(EndpointSliceKind, &sliceEventHandler{j.eventHandler(slicesInformer)}))
 	go slicesInformer.Run(stopCh)
-
-	<-stopCh
-	j.Store.Close()
 }
 
 func (j Job) eventHandler(informer cache.SharedIndexInformer) eventHandler {
@@ -116,6 +160,8 @@ func (j Job) eventHandler(informer cache.SharedIndexInformer) eventHandler {
 		k8sConfig: j.Config,
 		s:         j.Store,
 		informer:  informer,
+		cluster:   j.Cluster,
+		clusters:  j.Clusters,
 	}
 }
 
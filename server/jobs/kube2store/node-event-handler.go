@@ -20,6 +20,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 
 	globalv1 "sigs.k8s.io/kpng/api/globalv1"
+	localv1 "sigs.k8s.io/kpng/api/localv1"
 	proxystore "sigs.k8s.io/kpng/server/proxystore"
 )
 
@@ -29,18 +30,38 @@ const (
 
 type nodeEventHandler struct{ eventHandler }
 
-func (h *nodeEventHandler) OnAdd(obj interface{}) {
+func (h *nodeEventHandler) OnAdd(obj interface{}, isInInitialList bool) {
 	node := obj.(*v1.Node)
 
 	// keep only what we want
 	n := &globalv1.Node{
-		Name: node.Name,
+		Name: h.nodeName(node),
 		Topology: &globalv1.TopologyInfo{
-			Node: node.Name,
-			Zone: node.Labels[nodeZoneLabel],
+			Node:    node.Name,
+			Zone:    node.Labels[nodeZoneLabel],
+			Cluster: h.cluster,
 		},
 		Labels:      globsFilter(node.Labels, h.k8sConfig.NodeLabelGlobs),
 		Annotations: globsFilter(node.Annotations, h.k8sConfig.NodeAnnotationGlobs),
+		InternalIPs: localv1.NewIPSet(),
+		ExternalIPs: localv1.NewIPSet(),
+		PodCIDRs:    node.Spec.PodCIDRs,
+
+		Unschedulable: node.Spec.Unschedulable,
+	}
+
+	for _, addr := range node.Status.Addresses {
+		switch addr.Type {
+		case v1.NodeInternalIP:
+			n.InternalIPs.Add(addr.Address)
+		case v1.NodeExternalIP:
+			n.ExternalIPs.Add(addr.Address)
+		}
+	}
+
+	if len(n.PodCIDRs) == 0 && node.Spec.PodCIDR != "" {
+		// clusters not supporting dual-stack only set the PodCIDR field
+		n.PodCIDRs = []string{node.Spec.PodCIDR}
 	}
 
 	h.s.Update(func(tx *proxystore.Tx) {
@@ -52,14 +73,23 @@ func (h *nodeEventHandler) OnAdd(obj interface{}) {
 
 func (h *nodeEventHandler) OnUpdate(oldObj, newObj interface{}) {
 	// same as adding
-	h.OnAdd(newObj)
+	h.OnAdd(newObj, false)
 }
 
 func (h *nodeEventHandler) OnDelete(oldObj interface{}) {
 	node := oldObj.(*v1.Node)
 
 	h.s.Update(func(tx *proxystore.Tx) {
-		tx.DelNode(node.Name)
+		tx.DelNode(h.nodeName(node))
 		h.updateSync(proxystore.Nodes, tx)
 	})
 }
+
+// nodeName returns the name of the node in the store, qualified by its cluster if the store holds
+// several clusters.
+func (h *nodeEventHandler) nodeName(node *v1.Node) string {
+	if h.clusters == nil {
+		return node.Name
+	}
+	return proxystore.NodeName(h.cluster, node.Name)
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube2store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// Kinds of recorded objects
+const (
+	ServiceKind       = "Service"
+	EndpointSliceKind = "EndpointSlice"
+	NodeKind          = "Node"
+)
+
+type EventType string
+
+const (
+	Added   EventType = "add"
+	Updated EventType = "update"
+	Deleted EventType = "delete"
+)
+
+// Event is an informer event, as recorded by a Recorder.
+type Event struct {
+	Time   time.Time       `json:"time"`
+	Kind   string          `json:"kind"`
+	Type   EventType       `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// Decode returns the event's object.
+func (e Event) Decode() (obj runtime.Object, err error) {
+	switch e.Kind {
+	case ServiceKind:
+		obj = &v1.Service{}
+	case EndpointSliceKind:
+		obj = &discovery.EndpointSlice{}
+	case NodeKind:
+		obj = &v1.Node{}
+	default:
+		return nil, fmt.Errorf("unknown kind %q", e.Kind)
+	}
+
+	err = json.Unmarshal(e.Object, obj)
+	return
+}
+
+// Recorder writes the informer events it receives as JSON lines.
+type Recorder struct {
+	mu  sync.Mutex
+	out *bufio.Writer
+	c   io.Closer
+	enc *json.Encoder
+}
+
+func NewRecorder(w io.Writer) *Recorder {
+	out := bufio.NewWriter(w)
+	return &Recorder{out: out, enc: json.NewEncoder(out)}
+}
+
+// CreateRecorder creates a recorder writing to a new file at path.
+func CreateRecorder(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r := NewRecorder(f)
+	r.c = f
+	return r, nil
+}
+
+// Record writes an event. Objects are written without their managed fields.
+func (r *Recorder) Record(kind string, eventType EventType, obj interface{}) {
+	if d, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = d.Obj
+	}
+
+	if o, ok := obj.(runtime.Object); ok {
+		obj = o.DeepCopyObject()
+		if m, err := meta.Accessor(obj); err == nil {
+			m.SetManagedFields(nil)
+		}
+	}
+
+	objJSON, err := json.Marshal(obj)
+	if err != nil {
+		klog.Error("failed to record ", kind, " event: ", err)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err = r.enc.Encode(Event{Time: time.Now(), Kind: kind, Type: eventType, Object: objJSON})
+	if err == nil {
+		// keep the recording usable if the process is killed
+		err = r.out.Flush()
+	}
+	if err != nil {
+		klog.Error("failed to record ", kind, " event: ", err)
+	}
+}
+
+// Close flushes the recorder and closes its file, if any.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	err := r.out.Flush()
+	if r.c != nil {
+		if closeErr := r.c.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// Wrap returns a handler recording the events before passing them to h.
+func (r *Recorder) Wrap(kind string, h cache.ResourceEventHandler) cache.ResourceEventHandler {
+	return recordingHandler{r, kind, h}
+}
+
+type recordingHandler struct {
+	r    *Recorder
+	kind string
+	next cache.ResourceEventHandler
+}
+
+func (h recordingHandler) OnAdd(obj interface{}, isInInitialList bool) {
+	h.r.Record(h.kind, Added, obj)
+	h.next.OnAdd(obj, isInInitialList)
+}
+
+func (h recordingHandler) OnUpdate(oldObj, newObj interface{}) {
+	if !isResync(oldObj, newObj) {
+		h.r.Record(h.kind, Updated, newObj)
+	}
+	h.next.OnUpdate(oldObj, newObj)
+}
+
+func (h recordingHandler) OnDelete(obj interface{}) {
+	h.r.Record(h.kind, Deleted, obj)
+	h.next.OnDelete(obj)
+}
+
+// isResync returns true if an update is a periodic resync of the informer, carrying no change.
+func isResync(oldObj, newObj interface{}) bool {
+	oldMeta, err := meta.Accessor(oldObj)
+	if err != nil {
+		return false
+	}
+	newMeta, err := meta.Accessor(newObj)
+	if err != nil {
+		return false
+	}
+	return oldMeta.GetResourceVersion() == newMeta.GetResourceVersion()
+}
+
+// ReadEvents reads the events written by a Recorder.
+func ReadEvents(r io.Reader) (events []Event, err error) {
+	dec := json.NewDecoder(r)
+	for {
+		e := Event{}
+		err = dec.Decode(&e)
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return
+		}
+		events = append(events, e)
+	}
+}
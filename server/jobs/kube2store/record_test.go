@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube2store
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kpng/api/globalv1"
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+func TestRecordReplay(t *testing.T) {
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "svc", ResourceVersion: "1"},
+		Spec: v1.ServiceSpec{
+			Type:       v1.ServiceTypeClusterIP,
+			ClusterIP:  "10.0.0.1",
+			ClusterIPs: []string{"10.0.0.1"},
+			Ports:      []v1.ServicePort{{Name: "http", Port: 80}},
+		},
+	}
+
+	slice := &discovery.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "default",
+			Name:            "svc-abcde",
+			ResourceVersion: "2",
+			Labels:          map[string]string{discovery.LabelServiceName: "svc"},
+		},
+		AddressType: discovery.AddressTypeIPv4,
+		Endpoints: []discovery.Endpoint{
+			{Addresses: []string{"10.1.0.1"}},
+			{Addresses: []string{"10.1.0.2"}},
+		},
+		Ports: []discovery.EndpointPort{{Name: ref("http"), Port: ref(int32(8080))}},
+	}
+
+	node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", ResourceVersion: "3"}}
+
+	// record a few events
+	buf := &bytes.Buffer{}
+	rec := NewRecorder(buf)
+
+	rec.Record(ServiceKind, Added, svc)
+	rec.Record(EndpointSliceKind, Added, slice)
+	rec.Record(NodeKind, Added, node)
+
+	slice = slice.DeepCopy()
+	slice.ResourceVersion = "4"
+	slice.Endpoints = slice.Endpoints[1:]
+	rec.Record(EndpointSliceKind, Updated, slice)
+
+	rec.Record(NodeKind, Deleted, node)
+
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := ReadEvents(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 5 {
+		t.Fatalf("expected 5 events, got %d", len(events))
+	}
+
+	// replay them
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	store := proxystore.New()
+	if err := Replay(ctx, store, &K8sConfig{}, events, false); err != nil {
+		t.Fatal(err)
+	}
+
+	store.View(0, func(tx *proxystore.Tx) {
+		services, nodes := 0, 0
+		tx.Each(proxystore.Services, func(*proxystore.KV) bool { services++; return true })
+		tx.Each(proxystore.Nodes, func(*proxystore.KV) bool { nodes++; return true })
+
+		if services != 1 {
+			t.Errorf("expected 1 service, got %d", services)
+		}
+		if nodes != 0 {
+			t.Errorf("expected no nodes, got %d", nodes)
+		}
+
+		ips := []string{}
+		tx.EachEndpointOfService("default", "svc", func(ei *globalv1.EndpointInfo) {
+			ips = append(ips, ei.Endpoint.IPs.V4...)
+		})
+		if len(ips) != 1 || ips[0] != "10.1.0.2" {
+			t.Errorf("expected endpoints [10.1.0.2], got %v", ips)
+		}
+	})
+}
+
+func TestReplayRealTime(t *testing.T) {
+	now := time.Now()
+
+	events := []Event{}
+	for i, name := range []string{"node-1", "node-2"} {
+		node := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: name}}
+
+		buf := &bytes.Buffer{}
+		rec := NewRecorder(buf)
+		rec.Record(NodeKind, Added, node)
+		rec.Close()
+
+		e, err := ReadEvents(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		e[0].Time = now.Add(time.Duration(i) * 100 * time.Millisecond)
+		events = append(events, e...)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if err := Replay(ctx, proxystore.New(), &K8sConfig{}, events, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := time.Since(start); d < 100*time.Millisecond {
+		t.Errorf("replay took %v, expected at least 100ms", d)
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube2store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+var kindResources = map[string]schema.GroupVersionResource{
+	ServiceKind:       v1.SchemeGroupVersion.WithResource("services"),
+	EndpointSliceKind: discovery.SchemeGroupVersion.WithResource("endpointslices"),
+	NodeKind:          v1.SchemeGroupVersion.WithResource("nodes"),
+}
+
+// ReplayJob replays the events recorded in a file (see K8sConfig.RecordFile), then keeps the
+// resulting state until the context is done.
+type ReplayJob struct {
+	FilePath string
+	// RealTime replays the events at the recorded pace instead of as fast as possible
+	RealTime bool
+	Store    *proxystore.Store
+	Config   *K8sConfig
+}
+
+func (j ReplayJob) Run(ctx context.Context) {
+	f, err := os.Open(j.FilePath)
+	if err != nil {
+		klog.Exit("failed to open the events record: ", err)
+	}
+
+	events, err := ReadEvents(f)
+	f.Close()
+	if err != nil {
+		klog.Exit("failed to read the events record: ", err)
+	}
+
+	klog.Info("replaying ", len(events), " events from ", j.FilePath)
+
+	err = Replay(ctx, j.Store, j.Config, events, j.RealTime)
+	if err != nil {
+		klog.Error("replay failed: ", err)
+	} else {
+		klog.Info("replay finished")
+	}
+
+	<-ctx.Done()
+	j.Store.Close()
+}
+
+// Replay feeds the events to a fake clientset watched by the kube2store event handlers, updating
+// the store the same way as the watched cluster did. It returns once each event has been handled;
+// the informers run until the context is done.
+func Replay(ctx context.Context, store *proxystore.Store, config *K8sConfig, events []Event, realTime bool) error {
+	client := fake.NewSimpleClientset()
+
+	// the fake clientset drops the changes made before a watch is established, so we have to
+	// know when the informers are ready
+	watching := make(chan struct{}, len(kindResources))
+	client.PrependWatchReactor("*", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		w, err := client.Tracker().Watch(action.GetResource(), action.GetNamespace())
+		if err == nil {
+			select {
+			case watching <- struct{}{}:
+			default:
+			}
+		}
+		return true, w, err
+	})
+
+	handled := make(chan struct{}, 1)
+
+	Job{
+		Kube:   client,
+		Store:  store,
+		Config: config,
+	}.start(ctx.Done(), func(_ string, h cache.ResourceEventHandler) cache.ResourceEventHandler {
+		return replayHandler{h, handled, ctx.Done()}
+	})
+
+	for range kindResources {
+		select {
+		case <-watching:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	start := time.Now()
+
+	for idx, e := range events {
+		if realTime {
+			delay := time.Until(start.Add(e.Time.Sub(events[0].Time)))
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if err := apply(client.Tracker(), e); err != nil {
+			klog.Warningf("event %d: failed to replay %s %s: %v", idx, e.Type, e.Kind, err)
+			continue
+		}
+
+		select {
+		case <-handled:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// apply applies an event to the fake clientset's objects.
+func apply(tracker k8stesting.ObjectTracker, e Event) error {
+	gvr, ok := kindResources[e.Kind]
+	if !ok {
+		return fmt.Errorf("unknown kind %q", e.Kind)
+	}
+
+	obj, err := e.Decode()
+	if err != nil {
+		return err
+	}
+
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	switch e.Type {
+	case Added:
+		return tracker.Create(gvr, obj, m.GetNamespace())
+	case Updated:
+		return tracker.Update(gvr, obj, m.GetNamespace())
+	case Deleted:
+		return tracker.Delete(gvr, m.GetNamespace(), m.GetName())
+	default:
+		return fmt.Errorf("unknown event type %q", e.Type)
+	}
+}
+
+// replayHandler notifies when an event is handled. Resyncs are not notified as they're not
+// caused by a replayed event.
+type replayHandler struct {
+	next    cache.ResourceEventHandler
+	handled chan<- struct{}
+	done    <-chan struct{}
+}
+
+func (h replayHandler) notify() {
+	select {
+	case h.handled <- struct{}{}:
+	case <-h.done:
+	}
+}
+
+func (h replayHandler) OnAdd(obj interface{}, isInInitialList bool) {
+	h.next.OnAdd(obj, isInInitialList)
+	h.notify()
+}
+
+func (h replayHandler) OnUpdate(oldObj, newObj interface{}) {
+	h.next.OnUpdate(oldObj, newObj)
+	if !isResync(oldObj, newObj) {
+		h.notify()
+	}
+}
+
+func (h replayHandler) OnDelete(obj interface{}) {
+	h.next.OnDelete(obj)
+	h.notify()
+}
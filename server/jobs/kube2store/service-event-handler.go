@@ -81,12 +81,37 @@ func (h *serviceEventHandler) onChange(obj interface{}) {
 	// load balancer IPs
 	if len(svc.Status.LoadBalancer.Ingress) != 0 {
 		ips := localv1.NewIPSet()
+		ingresses := make([]*localv1.LoadBalancerIngress, 0, len(svc.Status.LoadBalancer.Ingress))
+
 		for _, ingress := range svc.Status.LoadBalancer.Ingress {
 			if ingress.IP != "" {
 				ips.Add(ingress.IP)
 			}
+
+			lbIngress := &localv1.LoadBalancerIngress{
+				IP:       ingress.IP,
+				Hostname: ingress.Hostname,
+				IPMode:   ipMode(ingress.IPMode),
+				Ports:    make([]*localv1.LoadBalancerPortStatus, 0, len(ingress.Ports)),
+			}
+
+			for _, port := range ingress.Ports {
+				portStatus := &localv1.LoadBalancerPortStatus{
+					Port:     port.Port,
+					Protocol: localv1.ParseProtocol(string(port.Protocol)),
+				}
+				if port.Error != nil {
+					portStatus.Error = *port.Error
+				}
+
+				lbIngress.Ports = append(lbIngress.Ports, portStatus)
+			}
+
+			ingresses = append(ingresses, lbIngress)
 		}
+
 		service.IPs.LoadBalancerIPs = ips
+		service.IPs.LoadBalancerIngresses = ingresses
 	}
 
 	// load balancer source ranges
@@ -119,14 +144,22 @@ func (h *serviceEventHandler) onChange(obj interface{}) {
 	// healthcheck node port
 	service.HealthCheckNodePort = svc.Spec.HealthCheckNodePort
 
+	if svc.Spec.Type == v1.ServiceTypeExternalName {
+		service.ExternalName = svc.Spec.ExternalName
+	}
+
 	h.s.Update(func(tx *proxystore.Tx) {
 		klog.V(3).Info("service ", service.Namespace, "/", service.Name)
-		tx.SetService(service)
+		if h.clusters != nil {
+			h.clusters.SetService(tx, h.cluster, service, svc.Annotations[AnnotationExport] == "true")
+		} else {
+			tx.SetService(service)
+		}
 		h.updateSync(proxystore.Services, tx)
 	})
 }
 
-func (h *serviceEventHandler) OnAdd(obj interface{}) {
+func (h *serviceEventHandler) OnAdd(obj interface{}, isInInitialList bool) {
 	h.onChange(obj)
 }
 
@@ -138,7 +171,19 @@ func (h *serviceEventHandler) OnDelete(oldObj interface{}) {
 	svc := oldObj.(*v1.Service)
 
 	h.s.Update(func(tx *proxystore.Tx) {
-		tx.DelService(svc.Namespace, svc.Name)
+		if h.clusters != nil {
+			h.clusters.DelService(tx, h.cluster, svc.Namespace, svc.Name)
+		} else {
+			tx.DelService(svc.Namespace, svc.Name)
+		}
 		h.updateSync(proxystore.Services, tx)
 	})
 }
+
+// ipMode converts a load-balancer ingress ipMode; an unset mode means VIP.
+func ipMode(mode *v1.LoadBalancerIPMode) localv1.LoadBalancerIPMode {
+	if mode != nil && *mode == v1.LoadBalancerIPModeProxy {
+		return localv1.LoadBalancerIPMode_Proxy
+	}
+	return localv1.LoadBalancerIPMode_VIP
+}
@@ -6,6 +6,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"sigs.k8s.io/kpng/api/localv1"
 	"sigs.k8s.io/kpng/server/proxystore"
 )
 
@@ -62,6 +63,62 @@ func TestServiceEventHandlerTrafficPolicy(t *testing.T) {
 	}
 }
 
+func TestServiceEventHandlerLoadBalancerIPMode(t *testing.T) {
+	store := proxystore.New()
+
+	handler := serviceEventHandler{
+		eventHandler: eventHandler{
+			s:         store,
+			syncSet:   true,
+			k8sConfig: &K8sConfig{},
+		},
+	}
+
+	handler.onChange(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-lb",
+		},
+		Spec: v1.ServiceSpec{
+			Type: v1.ServiceTypeLoadBalancer,
+		},
+		Status: v1.ServiceStatus{
+			LoadBalancer: v1.LoadBalancerStatus{
+				Ingress: []v1.LoadBalancerIngress{
+					{IP: "192.0.2.1", IPMode: ref(v1.LoadBalancerIPModeVIP)},
+					{IP: "192.0.2.2", IPMode: ref(v1.LoadBalancerIPModeProxy)},
+				},
+			},
+		},
+	})
+
+	store.View(0, func(tx *proxystore.Tx) {
+		var svc *localv1.Service
+		tx.Each(proxystore.Services, func(kv *proxystore.KV) bool {
+			svc = kv.Service.Service
+			return false
+		})
+		if svc == nil {
+			t.Fatal("service not stored")
+		}
+
+		ingresses := svc.IPs.LoadBalancerIngresses
+		if len(ingresses) != 2 {
+			t.Fatalf("expected 2 ingresses, got %d", len(ingresses))
+		}
+		if mode := ingresses[0].IPMode; mode != localv1.LoadBalancerIPMode_VIP {
+			t.Errorf("192.0.2.1: expected VIP mode, got %v", mode)
+		}
+		if mode := ingresses[1].IPMode; mode != localv1.LoadBalancerIPMode_Proxy {
+			t.Errorf("192.0.2.2: expected Proxy mode, got %v", mode)
+		}
+
+		if vips := svc.IPs.LoadBalancerVIPs().All(); len(vips) != 1 || vips[0] != "192.0.2.1" {
+			t.Errorf("expected only 192.0.2.1 to be captured, got %v", vips)
+		}
+	})
+}
+
 func ref[T any](v T) *T {
 	return &v
 }
@@ -18,7 +18,9 @@ package kube2store
 
 import (
 	"sort"
+	"time"
 
+	v1 "k8s.io/api/core/v1"
 	discovery "k8s.io/api/discovery/v1"
 	"k8s.io/klog/v2"
 
@@ -38,7 +40,7 @@ func serviceNameFrom(eps *discovery.EndpointSlice) string {
 	return eps.Labels[discovery.LabelServiceName]
 }
 
-func (h sliceEventHandler) OnAdd(obj interface{}) {
+func (h sliceEventHandler) OnAdd(obj interface{}, isInInitialList bool) {
 	eps := obj.(*discovery.EndpointSlice)
 	serviceName := serviceNameFrom(eps)
 	if serviceName == "" {
@@ -46,6 +48,9 @@ func (h sliceEventHandler) OnAdd(obj interface{}) {
 		return
 	}
 
+	triggerTime := lastChangeTriggerTime(eps)
+	sourceName := h.sourceName(eps.Name)
+
 	// compute endpoints
 	infos := make([]*globalv1.EndpointInfo, 0, len(eps.Endpoints))
 
@@ -53,9 +58,11 @@ func (h sliceEventHandler) OnAdd(obj interface{}) {
 		info := &globalv1.EndpointInfo{
 			Namespace:   eps.Namespace,
 			ServiceName: serviceName,
-			SourceName:  eps.Name,
+			SourceName:  sourceName,
 			Endpoint:    &localv1.Endpoint{},
-			Topology:    &globalv1.TopologyInfo{},
+			Topology:    &globalv1.TopologyInfo{Cluster: h.cluster},
+
+			LastChangeTriggerTime: triggerTime,
 		}
 
 		if t := sliceEndpoint.TargetRef; t != nil && t.Kind == "Pod" {
@@ -84,9 +91,7 @@ func (h sliceEventHandler) OnAdd(obj interface{}) {
 			sort.Strings(info.Hints.Zones) // stable zone order
 		}
 
-		if r := sliceEndpoint.Conditions.Ready; r != nil {
-			info.Conditions = &globalv1.EndpointConditions{Ready: *r}
-		}
+		info.Conditions = endpointConditions(sliceEndpoint.Conditions)
 
 		for _, addr := range sliceEndpoint.Addresses {
 			info.Endpoint.AddAddress(addr)
@@ -102,7 +107,11 @@ func (h sliceEventHandler) OnAdd(obj interface{}) {
 	}
 
 	h.s.Update(func(tx *proxystore.Tx) {
-		tx.SetEndpointsOfSource(eps.Namespace, eps.Name, infos)
+		tx.SetEndpointsOfSource(eps.Namespace, sourceName, infos)
+		if triggerTime != 0 {
+			// also carried by the service, for the changes no endpoint carries (ie: scale-downs)
+			tx.SetServiceTriggerTime(eps.Namespace, serviceName, triggerTime)
+		}
 		h.updateSync(proxystore.Endpoints, tx)
 
 		if log := klog.V(3); log.Enabled() {
@@ -114,16 +123,52 @@ func (h sliceEventHandler) OnAdd(obj interface{}) {
 	})
 }
 
+// lastChangeTriggerTime returns the slice's last change trigger time in nanoseconds since the
+// epoch, or 0 if it's not known.
+func lastChangeTriggerTime(eps *discovery.EndpointSlice) int64 {
+	value, ok := eps.Annotations[v1.EndpointsLastChangeTriggerTime]
+	if !ok {
+		return 0
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		klog.V(1).Infof("invalid %s annotation on slice %s/%s: %v", v1.EndpointsLastChangeTriggerTime, eps.Namespace, eps.Name, err)
+		return 0
+	}
+
+	return t.UnixNano()
+}
+
+// endpointConditions converts the slice conditions, following the EndpointSlice API
+// semantics: an unknown readiness is ready, an unknown serving state is the readiness.
+func endpointConditions(c discovery.EndpointConditions) *globalv1.EndpointConditions {
+	conditions := &globalv1.EndpointConditions{
+		Ready:       c.Ready == nil || *c.Ready,
+		Terminating: c.Terminating != nil && *c.Terminating,
+	}
+
+	if c.Serving == nil {
+		conditions.Serving = conditions.Ready
+	} else {
+		conditions.Serving = *c.Serving
+	}
+
+	return conditions
+}
+
 func (h sliceEventHandler) OnUpdate(oldObj, newObj interface{}) {
 	// same as adding
-	h.OnAdd(newObj)
+	h.OnAdd(newObj, false)
 }
 
 func (h sliceEventHandler) OnDelete(oldObj interface{}) {
 	eps := oldObj.(*discovery.EndpointSlice)
 
+	// like kube-proxy, slice deletions have no trigger time: the annotation is the slice's last
+	// update time, not the deletion's one
 	h.s.Update(func(tx *proxystore.Tx) {
-		tx.DelEndpointsOfSource(eps.Namespace, eps.Name)
+		tx.DelEndpointsOfSource(eps.Namespace, h.sourceName(eps.Name))
 		h.updateSync(proxystore.Endpoints, tx)
 	})
 }
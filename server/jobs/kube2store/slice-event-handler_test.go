@@ -0,0 +1,32 @@
+package kube2store
+
+import (
+	"testing"
+
+	discovery "k8s.io/api/discovery/v1"
+)
+
+func TestEndpointConditions(t *testing.T) {
+	for testIdx, test := range []struct {
+		Ready, Serving, Terminating *bool
+
+		ExpectedReady, ExpectedServing, ExpectedTerminating bool
+	}{
+		{nil, nil, nil, true, true, false},
+		{ref(true), nil, nil, true, true, false},
+		{ref(false), nil, nil, false, false, false},
+		{ref(false), ref(true), ref(true), false, true, true},
+		{ref(false), ref(false), ref(true), false, false, true},
+	} {
+		c := endpointConditions(discovery.EndpointConditions{
+			Ready:       test.Ready,
+			Serving:     test.Serving,
+			Terminating: test.Terminating,
+		})
+
+		if c.Ready != test.ExpectedReady || c.Serving != test.ExpectedServing || c.Terminating != test.ExpectedTerminating {
+			t.Errorf("test[%d]: expected ready=%v serving=%v terminating=%v, got %v", testIdx,
+				test.ExpectedReady, test.ExpectedServing, test.ExpectedTerminating, c)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube2store
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+// StaticSource updates a store from given sets of objects instead of watches, converting them
+// the same way as Job.
+type StaticSource struct {
+	store         *proxystore.Store
+	labelSelector labels.Selector
+	handlers      map[string]cache.ResourceEventHandler
+	current       map[objectKey]runtime.Object
+}
+
+type objectKey struct {
+	kind, namespace, name string
+}
+
+func NewStaticSource(store *proxystore.Store, config *K8sConfig) *StaticSource {
+	// the handlers mustn't mark the sets as synced before the first Set completes
+	h := eventHandler{k8sConfig: config, s: store, syncSet: true}
+
+	return &StaticSource{
+		store:         store,
+		labelSelector: Job{Config: config}.getLabelSelector(),
+		handlers: map[string]cache.ResourceEventHandler{
+			ServiceKind:       &serviceEventHandler{h},
+			EndpointSliceKind: &sliceEventHandler{h},
+			NodeKind:          &nodeEventHandler{h},
+		},
+		current: map[objectKey]runtime.Object{},
+	}
+}
+
+// Set replaces the objects of the source. Objects other than Services, EndpointSlices and
+// Nodes are ignored, as are the services not selected by the configuration.
+func (s *StaticSource) Set(objects []runtime.Object) {
+	next := make(map[objectKey]runtime.Object, len(objects))
+
+	for _, obj := range objects {
+		var kind string
+		switch o := obj.(type) {
+		case *v1.Service:
+			if !s.labelSelector.Matches(labels.Set(o.Labels)) {
+				continue
+			}
+			kind = ServiceKind
+		case *discovery.EndpointSlice:
+			kind = EndpointSliceKind
+		case *v1.Node:
+			kind = NodeKind
+		default:
+			klog.V(1).Infof("ignoring object of type %T", obj)
+			continue
+		}
+
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			continue
+		}
+		if kind != NodeKind && m.GetNamespace() == "" {
+			m.SetNamespace("default")
+		}
+
+		key := objectKey{kind, m.GetNamespace(), m.GetName()}
+		next[key] = obj
+
+		s.handlers[kind].OnAdd(obj, false)
+	}
+
+	for key, obj := range s.current {
+		if _, ok := next[key]; !ok {
+			s.handlers[key.kind].OnDelete(obj)
+		}
+	}
+
+	s.current = next
+
+	s.store.Update(func(tx *proxystore.Tx) {
+		for _, set := range proxystore.AllSets {
+			tx.SetSync(set)
+		}
+	})
+}
+
+// DecodeManifests decodes the Kubernetes objects of a (possibly multi-document) YAML or JSON
+// manifest. Lists are flattened.
+func DecodeManifests(data []byte) (objects []runtime.Object, err error) {
+	decoder := scheme.Codecs.UniversalDeserializer()
+	reader := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+
+	for {
+		raw := runtime.RawExtension{}
+		err = reader.Decode(&raw)
+		if err == io.EOF {
+			return objects, nil
+		}
+		if err != nil {
+			return
+		}
+
+		raw.Raw = bytes.TrimSpace(raw.Raw)
+		if len(raw.Raw) == 0 || bytes.Equal(raw.Raw, []byte("null")) {
+			continue // empty document
+		}
+
+		obj, _, err := decoder.Decode(raw.Raw, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if !meta.IsListType(obj) {
+			objects = append(objects, obj)
+			continue
+		}
+
+		items, err := meta.ExtractList(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			if u, ok := item.(*runtime.Unknown); ok {
+				item, _, err = decoder.Decode(u.Raw, nil, nil)
+				if err != nil {
+					return nil, fmt.Errorf("list item: %w", err)
+				}
+			}
+			objects = append(objects, item)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube2store
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/kpng/api/globalv1"
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+const testManifests = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+spec:
+  type: ClusterIP
+  clusterIP: 10.0.0.1
+  ports:
+  - name: http
+    port: 80
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: other-proxy
+  labels:
+    service.kubernetes.io/service-proxy-name: other
+spec:
+  clusterIP: 10.0.0.2
+---
+# only comments
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ignored
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: discovery.k8s.io/v1
+  kind: EndpointSlice
+  metadata:
+    name: svc-abcde
+    labels:
+      kubernetes.io/service-name: svc
+  addressType: IPv4
+  endpoints:
+  - addresses: [10.1.0.1]
+    nodeName: node-1
+  ports:
+  - name: http
+    port: 8080
+- apiVersion: v1
+  kind: Node
+  metadata:
+    name: node-1
+    labels:
+      topology.kubernetes.io/zone: z1
+`
+
+func TestDecodeManifests(t *testing.T) {
+	objects, err := DecodeManifests([]byte(testManifests))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kinds := []string{}
+	for _, obj := range objects {
+		kinds = append(kinds, obj.GetObjectKind().GroupVersionKind().Kind)
+	}
+
+	if len(objects) != 5 {
+		t.Fatalf("expected 5 objects, got %v", kinds)
+	}
+}
+
+func TestStaticSource(t *testing.T) {
+	objects, err := DecodeManifests([]byte(testManifests))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := proxystore.New()
+	source := NewStaticSource(store, &K8sConfig{})
+
+	count := func(set proxystore.Set) (n int) {
+		store.View(0, func(tx *proxystore.Tx) {
+			tx.Each(set, func(*proxystore.KV) bool { n++; return true })
+		})
+		return
+	}
+
+	source.Set(objects)
+
+	store.View(0, func(tx *proxystore.Tx) {
+		if !tx.AllSynced() {
+			t.Error("store should be synced")
+		}
+
+		eps := 0
+		tx.EachEndpointOfService("default", "svc", func(ei *globalv1.EndpointInfo) {
+			eps++
+			if ei.Topology.Node != "node-1" {
+				t.Errorf("wrong endpoint topology: %v", ei.Topology)
+			}
+		})
+		if eps != 1 {
+			t.Errorf("expected 1 endpoint, got %d", eps)
+		}
+	})
+
+	// the service handled by another proxy is filtered
+	if n := count(proxystore.Services); n != 1 {
+		t.Errorf("expected 1 service, got %d", n)
+	}
+	if n := count(proxystore.Nodes); n != 1 {
+		t.Errorf("expected 1 node, got %d", n)
+	}
+
+	// removed objects are deleted
+	source.Set([]runtime.Object{objects[0]})
+
+	if n := count(proxystore.Services); n != 1 {
+		t.Errorf("expected 1 service, got %d", n)
+	}
+	if n := count(proxystore.Nodes); n != 0 {
+		t.Errorf("expected no nodes, got %d", n)
+	}
+	store.View(0, func(tx *proxystore.Tx) {
+		tx.EachEndpointOfService("default", "svc", func(ei *globalv1.EndpointInfo) {
+			t.Errorf("unexpected endpoint: %v", ei)
+		})
+	})
+}
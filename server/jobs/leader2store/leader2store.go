@@ -0,0 +1,242 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package leader2store runs one of several replicas of the kpng server. The replicas elect a
+// leader with a Lease: the leader feeds the store (ie: watching Kubernetes), and the followers
+// mirror the leader's store through its globalv1 API, so every replica can serve the local API.
+package leader2store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kpng/client/tlsflags"
+	"sigs.k8s.io/kpng/server/jobs/api2store"
+	"sigs.k8s.io/kpng/server/pkg/apiwatch"
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+type Config struct {
+	// Enabled makes the replicas elect a leader
+	Enabled bool
+
+	LeaseName      string
+	LeaseNamespace string
+
+	// Advertise is the address of this replica's globalv1 API, used by the followers to reach
+	// the leader. It's also the identity of the replica in the lease, so it must be unique.
+	Advertise string
+
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+
+	// TLS is the client configuration to reach the leader's API
+	TLS *tlsflags.Flags
+}
+
+func (c *Config) BindFlags(flags *pflag.FlagSet) {
+	namespace := os.Getenv("POD_NAMESPACE")
+	if namespace == "" {
+		namespace = "kube-system"
+	}
+
+	flags.BoolVar(&c.Enabled, "ha", false, "run as one of several replicas: the elected leader watches Kubernetes, the others mirror it through its globalv1 API")
+	flags.StringVar(&c.LeaseName, "ha-lease-name", "kpng", "name of the leader election lease")
+	flags.StringVar(&c.LeaseNamespace, "ha-lease-namespace", namespace, "namespace of the leader election lease (defaults to envvar POD_NAMESPACE, or kube-system)")
+	flags.StringVar(&c.Advertise, "ha-advertise", "", "address of this replica's globalv1 API, reachable by the other replicas (ie: $(POD_IP):12090)")
+	flags.DurationVar(&c.LeaseDuration, "ha-lease-duration", 15*time.Second, "duration the followers wait before taking over a leader that stopped renewing its lease")
+	flags.DurationVar(&c.RenewDeadline, "ha-renew-deadline", 10*time.Second, "duration the leader retries to renew its lease before giving up the leadership")
+	flags.DurationVar(&c.RetryPeriod, "ha-retry-period", 2*time.Second, "duration between the lease actions")
+
+	if c.TLS == nil {
+		c.TLS = &tlsflags.Flags{}
+	}
+
+	c.TLS.Bind(flags, "ha-")
+}
+
+type Job struct {
+	// Kube is the client used to hold the lease
+	Kube   kubernetes.Interface
+	Store  *proxystore.Store
+	Config *Config
+
+	// Lead feeds the store while this replica is the leader, until the context is done. It must
+	// not close the store.
+	Lead func(ctx context.Context)
+
+	l          sync.Mutex
+	leading    bool
+	leader     string
+	stopFollow func()
+
+	// serializes the leadership terms
+	lead sync.Mutex
+}
+
+// Run takes part in the election until the context is done, then closes the store.
+func (j *Job) Run(ctx context.Context) error {
+	defer j.Store.Close()
+
+	if j.Config.Advertise == "" {
+		return fmt.Errorf("the advertised address of the replica is required")
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Namespace: j.Config.LeaseNamespace,
+			Name:      j.Config.LeaseName,
+		},
+		Client: j.Kube.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: j.Config.Advertise,
+		},
+	}
+
+	for ctx.Err() == nil {
+		elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+			Lock:            lock,
+			Name:            j.Config.LeaseName,
+			LeaseDuration:   j.Config.LeaseDuration,
+			RenewDeadline:   j.Config.RenewDeadline,
+			RetryPeriod:     j.Config.RetryPeriod,
+			ReleaseOnCancel: true,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: j.startLeading,
+				OnStoppedLeading: j.stopLeading,
+				OnNewLeader: func(identity string) {
+					j.follow(ctx, identity)
+				},
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		// returns when the leadership is lost, so we can run for a new term
+		elector.Run(ctx)
+	}
+
+	j.l.Lock()
+	j.stopFollowing()
+	j.l.Unlock()
+
+	return nil
+}
+
+// Leader returns the identity of the current leader, as known by this replica ("" if unknown).
+func (j *Job) Leader() string {
+	j.l.Lock()
+	defer j.l.Unlock()
+
+	return j.leader
+}
+
+// Leading returns true if this replica is the leader.
+func (j *Job) Leading() bool {
+	j.l.Lock()
+	defer j.l.Unlock()
+
+	return j.leading
+}
+
+func (j *Job) startLeading(ctx context.Context) {
+	j.lead.Lock()
+	defer j.lead.Unlock()
+
+	j.l.Lock()
+	j.stopFollowing()
+	j.leading = true
+	j.leader = j.Config.Advertise
+	j.l.Unlock()
+
+	klog.Info("leading, feeding the store")
+
+	// the entries of the previous leader that are not set again by this one are stale
+	j.Store.MarkProvisional()
+
+	j.Lead(ctx)
+}
+
+func (j *Job) stopLeading() {
+	j.l.Lock()
+	defer j.l.Unlock()
+
+	if j.leading {
+		klog.Info("stopped leading")
+	}
+
+	j.leading = false
+}
+
+// follow mirrors the given leader in the store.
+func (j *Job) follow(ctx context.Context, leader string) {
+	j.l.Lock()
+	defer j.l.Unlock()
+
+	if j.leading || leader == j.leader || leader == j.Config.Advertise {
+		// we may be notified of a previous leader after taking the lead
+		return
+	}
+
+	j.stopFollowing()
+	j.leader = leader
+
+	if leader == "" {
+		return // the lease was released, keep the current state until the next leader
+	}
+
+	klog.Info("following the leader at ", leader)
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	job := &api2store.Job{
+		Watch: apiwatch.Watch{Server: leader, TLSFlags: j.Config.TLS},
+		Store: j.Store,
+	}
+
+	go func() {
+		defer close(done)
+		job.Feed(ctx)
+	}()
+
+	j.stopFollow = func() {
+		cancel()
+		<-done
+	}
+}
+
+// stopFollowing stops mirroring the leader, if needed. j.l must be held.
+func (j *Job) stopFollowing() {
+	if j.stopFollow == nil {
+		return
+	}
+
+	j.stopFollow()
+	j.stopFollow = nil
+}
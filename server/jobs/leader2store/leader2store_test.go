@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package leader2store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+func TestElection(t *testing.T) {
+	kube := fake.NewSimpleClientset()
+
+	type replica struct {
+		job    *Job
+		cancel func()
+		done   chan struct{}
+	}
+
+	leads := make(chan string, 10)
+
+	start := func(address string) *replica {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		r := &replica{
+			job: &Job{
+				Kube:  kube,
+				Store: proxystore.New(),
+				Config: &Config{
+					LeaseName:      "kpng",
+					LeaseNamespace: "kube-system",
+					Advertise:      address,
+					LeaseDuration:  2 * time.Second,
+					RenewDeadline:  time.Second,
+					RetryPeriod:    100 * time.Millisecond,
+				},
+				Lead: func(ctx context.Context) {
+					leads <- address
+					<-ctx.Done()
+				},
+			},
+			cancel: cancel,
+			done:   make(chan struct{}),
+		}
+
+		go func() {
+			defer close(r.done)
+			if err := r.job.Run(ctx); err != nil {
+				t.Error(err)
+			}
+		}()
+
+		return r
+	}
+
+	a := start("127.0.0.1:1")
+	b := start("127.0.0.1:2")
+
+	defer func() {
+		a.cancel()
+		b.cancel()
+		<-a.done
+		<-b.done
+	}()
+
+	waitLead := func() string {
+		select {
+		case leader := <-leads:
+			return leader
+		case <-time.After(10 * time.Second):
+			t.Fatal("no leader elected")
+			return ""
+		}
+	}
+
+	waitFor := func(r *replica, leader string) {
+		deadline := time.Now().Add(10 * time.Second)
+		for r.job.Leader() != leader {
+			if time.Now().After(deadline) {
+				t.Fatalf("%s: expected leader %q, got %q", r.job.Config.Advertise, leader, r.job.Leader())
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	leader := waitLead()
+
+	leading, following := a, b
+	if leader == b.job.Config.Advertise {
+		leading, following = b, a
+	}
+
+	if !leading.job.Leading() || following.job.Leading() {
+		t.Fatal("expected exactly one leading replica")
+	}
+
+	waitFor(following, leader)
+
+	// stopping the leader releases the lease, the follower takes over
+	leading.cancel()
+	<-leading.done
+
+	if newLeader := waitLead(); newLeader != following.job.Config.Advertise {
+		t.Fatalf("expected %s to take over, got %s", following.job.Config.Advertise, newLeader)
+	}
+
+	waitFor(following, following.job.Config.Advertise)
+
+	if !following.job.Leading() {
+		t.Fatal("expected the follower to lead")
+	}
+}
@@ -19,13 +19,23 @@ package store2api
 import (
 	"context"
 	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"time"
 
 	"github.com/spf13/pflag"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/kpng/client/tlsflags"
+	"sigs.k8s.io/kpng/server/jobs/store2diff"
+	pkgendpoints "sigs.k8s.io/kpng/server/pkg/endpoints"
 	"sigs.k8s.io/kpng/server/pkg/server"
+	"sigs.k8s.io/kpng/server/pkg/server/admin"
+	"sigs.k8s.io/kpng/server/pkg/server/authz"
 	"sigs.k8s.io/kpng/server/pkg/server/endpoints"
 	"sigs.k8s.io/kpng/server/pkg/server/global"
 	"sigs.k8s.io/kpng/server/proxystore"
@@ -36,12 +46,47 @@ type Config struct {
 	GlobalAPI bool
 	LocalAPI  bool
 	TLS       *tlsflags.Flags
+
+	// ResumeTTL is how long the state of a disconnected local watcher is kept to resume its watch
+	ResumeTTL time.Duration
+
+	// SendTimeout is how long a send to a local watcher can block before the watcher is dropped
+	SendTimeout time.Duration
+
+	// MinInterval and MaxDelay coalesce the store revisions sent to each local watcher
+	MinInterval time.Duration
+	MaxDelay    time.Duration
+
+	// KeepaliveTime is the idle duration after which clients are pinged (0 to disable)
+	KeepaliveTime time.Duration
+	// KeepaliveTimeout is how long to wait for a ping ack before closing the connection
+	KeepaliveTimeout time.Duration
+	// KeepaliveMinTime is the minimum interval between the clients' pings
+	KeepaliveMinTime time.Duration
+
+	Endpoints pkgendpoints.Config
+
+	// Authz authorizes the clients from their certificate
+	Authz authz.Config
+
+	// DebugBindSpec is the address of the debug HTTP endpoints (empty to disable)
+	DebugBindSpec string
 }
 
 func (c *Config) BindFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&c.BindSpec, "listen", "tcp://:12090", "serve globalv1 API")
 	flags.BoolVar(&c.GlobalAPI, "globalv1-api", true, "serve globalv1 API")
 	flags.BoolVar(&c.LocalAPI, "local-api", true, "serve local API")
+	flags.DurationVar(&c.ResumeTTL, "local-resume-ttl", 2*time.Minute, "how long disconnected local watchers can resume without a full resync (0 to disable)")
+	flags.DurationVar(&c.SendTimeout, "local-send-timeout", 30*time.Second, "drop the local watchers not receiving within this delay (ie: a wedged backend); they get the whole state on reconnect (0 to disable)")
+	flags.DurationVar(&c.MinInterval, "local-min-interval", 0, "minimum interval between two syncs of a local watcher, the revisions arriving meanwhile being collapsed into the next sync (0 to disable)")
+	flags.DurationVar(&c.MaxDelay, "local-max-delay", time.Second, "maximum delay of a local watcher's sync after a revision, even within --local-min-interval (0 for no limit)")
+	flags.DurationVar(&c.KeepaliveTime, "keepalive-time", time.Minute, "ping the clients after this idle duration, to detect dead connections (0 to disable)")
+	flags.DurationVar(&c.KeepaliveTimeout, "keepalive-timeout", 20*time.Second, "close the connections not acknowledging a ping within this delay")
+	flags.DurationVar(&c.KeepaliveMinTime, "keepalive-min-time", 5*time.Second, "minimum interval between the clients' keepalive pings; clients pinging more often are disconnected")
+	flags.StringVar(&c.DebugBindSpec, "debug-listen", "", "serve debug HTTP endpoints on this address (ie: 127.0.0.1:12091; /debug/nodes shows the nodes' apply statuses, /debug/watchers the connected watchers, and /debug/store, /debug/services, /debug/endpoints and /debug/fornode?node=<name> the state, unauthenticated: must be a loopback address with --authorize-nodes)")
+	c.Endpoints.BindFlags(flags)
+	c.Authz.BindFlags(flags)
 
 	if c.TLS == nil {
 		c.TLS = &tlsflags.Flags{}
@@ -56,26 +101,71 @@ type Job struct {
 }
 
 func (j *Job) Run(ctx context.Context) error {
+	selector, err := j.Config.Endpoints.Selector()
+	if err != nil {
+		return err
+	}
+
+	if j.Config.Authz.Enabled && j.Config.TLS.Config() == nil {
+		return errors.New("authorizing the nodes requires TLS")
+	}
+
+	if j.Config.Authz.Enabled && j.Config.DebugBindSpec != "" && !isLoopback(j.Config.DebugBindSpec) {
+		// the debug endpoints expose every node's state without authorization
+		return errors.New("authorizing the nodes requires a loopback --debug-listen address")
+	}
+
 	lis := server.MustListen(j.Config.BindSpec)
 
 	// setup gRPC server
-	var srv *grpc.Server
-	if tlsCfg := j.Config.TLS.Config(); tlsCfg == nil {
-		srv = grpc.NewServer()
-	} else {
+	opts := []grpc.ServerOption{
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             j.Config.KeepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
+	}
+
+	if j.Config.KeepaliveTime != 0 {
+		opts = append(opts, grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    j.Config.KeepaliveTime,
+			Timeout: j.Config.KeepaliveTimeout,
+		}))
+	}
+
+	if tlsCfg := j.Config.TLS.Config(); tlsCfg != nil {
 		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
 		tlsCfg.ClientCAs = tlsCfg.RootCAs
 
-		creds := credentials.NewTLS(tlsCfg)
-		srv = grpc.NewServer(grpc.Creds(creds))
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+	}
+
+	if j.Config.Authz.Enabled {
+		opts = append(opts, grpc.StreamInterceptor(j.Config.Authz.StreamInterceptor()))
 	}
 
+	srv := grpc.NewServer(opts...)
+
+	debug := http.NewServeMux()
+	(&admin.Handler{Store: j.Store, Selector: selector}).Register(debug)
+
 	// setup server
 	if j.Config.GlobalAPI {
 		global.Setup(srv, j.Store)
 	}
 	if j.Config.LocalAPI {
-		endpoints.Setup(srv, j.Store)
+		localSrv := endpoints.Setup(srv, j.Store, j.Config.ResumeTTL, selector)
+		localSrv.SendTimeout = j.Config.SendTimeout
+		localSrv.Coalescing = store2diff.Coalescing{MinInterval: j.Config.MinInterval, MaxDelay: j.Config.MaxDelay}
+		if j.Config.Authz.Enabled {
+			localSrv.Authorize = j.Config.Authz.AuthorizeNode
+		}
+		debug.Handle("/debug/nodes", localSrv.Statuses)
+		go localSrv.Statuses.Prune(ctx)
+		debug.Handle("/debug/watchers", localSrv.Watchers)
+	}
+
+	if j.Config.DebugBindSpec != "" {
+		go serveDebug(ctx, j.Config.DebugBindSpec, debug)
 	}
 
 	// handle exit
@@ -86,3 +176,36 @@ func (j *Job) Run(ctx context.Context) error {
 
 	return srv.Serve(lis)
 }
+
+// isLoopback returns true if the address only listens on the loopback interface.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// serveDebug serves the debug endpoints until the context is done.
+func serveDebug(ctx context.Context, addr string, handler http.Handler) {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	klog.Info("serving debug endpoints on ", addr)
+
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		klog.Error("debug server failed: ", err)
+	}
+}
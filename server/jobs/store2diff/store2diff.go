@@ -18,8 +18,11 @@ package store2diff
 
 import (
 	"context"
+	"time"
 
 	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/client/lightdiffstore"
+	"sigs.k8s.io/kpng/server/pkg/metrics"
 	"sigs.k8s.io/kpng/server/pkg/server/watchstate"
 	"sigs.k8s.io/kpng/server/proxystore"
 )
@@ -28,6 +31,24 @@ type Job struct {
 	Store *proxystore.Store
 	Sets  []localv1.Set
 	Sink  Sink
+
+	// Views, if not nil, retains the state sent to watchers when they disconnect,
+	// allowing them to resume.
+	Views *Views
+
+	// Resume is the digest of the state the watcher already has (0 if none).
+	Resume uint64
+
+	// Coalescing collapses bursts of revisions into one Sync (disabled if zero).
+	Coalescing Coalescing
+}
+
+// Coalescing works like async.BoundedFrequencyRunner's minimum interval: a watcher's Syncs are
+// at least MinInterval apart, the revisions arriving meanwhile going in the next one. A revision
+// is never held more than MaxDelay (if not zero).
+type Coalescing struct {
+	MinInterval time.Duration
+	MaxDelay    time.Duration
 }
 
 type Sink interface {
@@ -36,6 +57,23 @@ type Sink interface {
 	Wait() error
 	Update(tx *proxystore.Tx, w *watchstate.WatchState)
 	SendDiff(w *watchstate.WatchState) (updated bool)
+
+	// ViewKey returns the key of the view requested by the watcher (ie: its node name).
+	ViewKey() string
+}
+
+// ViewChanger is implemented by sinks whose view can change between requests (ie: filters).
+type ViewChanger interface {
+	// ViewChanged returns true if the last request changed the view, so the watcher's state must
+	// be updated even if the store didn't change.
+	ViewChanged() bool
+}
+
+// ResumeChecker is implemented by sinks which can refuse the watcher's resume.
+type ResumeChecker interface {
+	// CanResume returns false if the watcher must get the whole state, whatever it has (ie: it
+	// may have missed ops). It's called once the first request is received.
+	CanResume() bool
 }
 
 func (j *Job) Run(ctx context.Context) (err error) {
@@ -44,8 +82,26 @@ func (j *Job) Run(ctx context.Context) (err error) {
 	var (
 		rev    uint64
 		closed bool
+
+		// inSync is true when the watcher's state is the one in w; sentRev is its revision
+		inSync  bool
+		sentRev uint64
+
+		// checkDigest is true when the watcher's state must be compared to the first computed one
+		checkDigest bool
+
+		// lastSync is the time of the last Sync sent, for the Coalescing
+		lastSync time.Time
 	)
 
+	if j.Views != nil {
+		defer func() {
+			if inSync && !closed && w.Err == nil {
+				j.Views.put(j.Sink.ViewKey(), w, sentRev)
+			}
+		}()
+	}
+
 	for {
 		if err = ctx.Err(); err != nil {
 			// check the context is still active; we expect the wtachstate/sink to fail fast in this case
@@ -58,16 +114,51 @@ func (j *Job) Run(ctx context.Context) (err error) {
 			return
 		}
 
+		if rev == 0 && j.Resume != 0 {
+			if rc, ok := j.Sink.(ResumeChecker); ok && !rc.CanResume() {
+				j.Resume = 0
+			}
+		}
+
 		if rev == 0 {
-			w.SendReset()
+			if view, viewRev, ok := j.resumeView(); ok {
+				// resume from the watcher's state
+				w, rev = view, viewRev
+				w.SetSink(j.Sink)
+				inSync, sentRev = true, rev
+
+			} else if j.Resume != 0 {
+				checkDigest = true
+
+			} else {
+				w.SendReset()
+			}
+		}
+
+		afterRev := rev
+		if vc, ok := j.Sink.(ViewChanger); ok && vc.ViewChanged() && rev != 0 {
+			afterRev = rev - 1 // update from the current revision
 		}
 
 		updated := false
 		for !updated {
+			synced := false
+
+			if j.Coalescing.MinInterval > 0 && afterRev == rev && rev != 0 && !checkDigest {
+				if closed = j.coalesce(afterRev, lastSync); closed {
+					return
+				}
+			}
+
 			// block until the revision has been
 			// incremented... then, we update our state from the
 			// proxystore
-			rev, closed = j.Store.View(rev, func(tx *proxystore.Tx) {
+			rev, closed = j.Store.View(afterRev, func(tx *proxystore.Tx) {
+				if ctx.Err() != nil {
+					return // the watcher is gone, keep its state as sent
+				}
+
+				synced = tx.AllSynced()
 				j.Sink.Update(tx, w)
 			})
 
@@ -75,19 +166,89 @@ func (j *Job) Run(ctx context.Context) (err error) {
 				return
 			}
 
+			afterRev = rev
+
+			if err = ctx.Err(); err != nil {
+				return
+			}
+
 			if w.Err != nil {
 				return w.Err
 			}
 
+			if checkDigest {
+				if !synced {
+					continue
+				}
+
+				checkDigest = false
+
+				if w.Digest() == j.Resume {
+					// the watcher already has this state, only confirm it
+					w.Reset(lightdiffstore.ItemDeleted)
+					break
+				}
+
+				w.SendReset()
+			}
+
 			// send the diff
 			updated = j.Sink.SendDiff(w)
+
+			if updated {
+				inSync = false
+			}
 		}
 
 		// signal the change set is fully sent
-		w.SendSync()
+		w.SendSync(rev)
 
 		if w.Err != nil {
 			return w.Err
 		}
+
+		inSync, sentRev = true, rev
+		lastSync = time.Now()
+	}
+}
+
+// coalesce waits for a revision after afterRev, then holds it until MinInterval after the last
+// Sync, but for at most MaxDelay (if not zero). The revisions arriving meanwhile go in the same Sync.
+func (j *Job) coalesce(afterRev uint64, lastSync time.Time) (closed bool) {
+	last, closed := j.Store.WaitRev(afterRev, 0)
+	if closed {
+		return
+	}
+
+	deadline := lastSync.Add(j.Coalescing.MinInterval)
+	if maxDelay := j.Coalescing.MaxDelay; maxDelay > 0 {
+		if maxDeadline := time.Now().Add(maxDelay); maxDeadline.Before(deadline) {
+			deadline = maxDeadline
+		}
+	}
+
+	for {
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			break
+		}
+
+		rev, closed := j.Store.WaitRev(last, wait)
+		if closed {
+			return true
+		}
+		last = rev
 	}
+
+	// all the revisions since the watcher's one go in the next Sync
+	metrics.Kpng_local_coalesced_revisions.Add(float64(last - afterRev - 1))
+	return false
+}
+
+func (j *Job) resumeView() (w *watchstate.WatchState, rev uint64, ok bool) {
+	if j.Views == nil || j.Resume == 0 {
+		return
+	}
+
+	return j.Views.take(j.Sink.ViewKey(), j.Resume)
 }
@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store2diff
+
+import (
+	"sync"
+	"time"
+
+	"sigs.k8s.io/kpng/server/pkg/server/watchstate"
+)
+
+// Views retains the state last sent to disconnected watchers, so they can resume
+// their watch without receiving the whole state again.
+type Views struct {
+	// TTL is how long a view is retained after its watcher disconnected.
+	TTL time.Duration
+
+	l     sync.Mutex
+	views map[viewID]*view
+}
+
+type viewID struct {
+	key    string
+	digest uint64
+}
+
+type view struct {
+	w       *watchstate.WatchState
+	rev     uint64
+	expires time.Time
+}
+
+func NewViews(ttl time.Duration) *Views {
+	return &Views{
+		TTL:   ttl,
+		views: map[viewID]*view{},
+	}
+}
+
+// put retains the view of a watcher that disconnected after receiving the revision rev.
+func (v *Views) put(key string, w *watchstate.WatchState, rev uint64) {
+	id := viewID{key, w.Digest()}
+	if id.digest == 0 {
+		return // empty state, nothing to resume
+	}
+
+	now := time.Now()
+
+	v.l.Lock()
+	defer v.l.Unlock()
+
+	// cleanup expired views
+	for id, view := range v.views {
+		if now.After(view.expires) {
+			delete(v.views, id)
+		}
+	}
+
+	v.views[id] = &view{
+		w:       w,
+		rev:     rev,
+		expires: now.Add(v.TTL),
+	}
+}
+
+// take removes and returns the view matching the watcher's key and state digest, if any.
+func (v *Views) take(key string, digest uint64) (w *watchstate.WatchState, rev uint64, ok bool) {
+	v.l.Lock()
+	defer v.l.Unlock()
+
+	id := viewID{key, digest}
+
+	view, ok := v.views[id]
+	if !ok {
+		return
+	}
+
+	delete(v.views, id)
+
+	if time.Now().After(view.expires) {
+		return nil, 0, false
+	}
+
+	return view.w, view.rev, true
+}
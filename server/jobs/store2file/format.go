@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store2file
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+
+	"sigs.k8s.io/kpng/api/globalv1"
+)
+
+const (
+	YAML  = "yaml"
+	JSON  = "json"
+	Proto = "proto"
+)
+
+// Formats are the supported state file formats. JSON is the protobuf-JSON encoding of a
+// globalv1.State, and Proto its binary protobuf encoding.
+var Formats = []string{YAML, JSON, Proto}
+
+var encoders = map[string]func(GlobalState) ([]byte, error){
+	YAML: func(state GlobalState) ([]byte, error) {
+		return yaml.Marshal(state)
+	},
+	JSON: func(state GlobalState) ([]byte, error) {
+		return protojson.MarshalOptions{Multiline: true}.Marshal(state.ToProto())
+	},
+	Proto: func(state GlobalState) ([]byte, error) {
+		return proto.Marshal(state.ToProto())
+	},
+}
+
+// FormatFromPath returns the format of a state file from its extension, defaulting to YAML.
+func FormatFromPath(path string) string {
+	switch filepath.Ext(path) {
+	case ".json":
+		return JSON
+	case ".pb":
+		return Proto
+	default:
+		return YAML
+	}
+}
+
+// Decode decodes a state in the given format. Unknown fields are errors.
+func Decode(format string, data []byte) (state GlobalState, err error) {
+	switch format {
+	case YAML:
+		err = yaml.UnmarshalStrict(data, &state)
+		return
+
+	case JSON, Proto:
+		pb := &globalv1.State{}
+		if format == JSON {
+			err = protojson.Unmarshal(data, pb)
+		} else {
+			err = proto.Unmarshal(data, pb)
+		}
+		if err != nil {
+			return
+		}
+		return GlobalStateFromProto(pb), nil
+
+	default:
+		err = fmt.Errorf("unknown format %q", format)
+		return
+	}
+}
+
+func (s GlobalState) ToProto() *globalv1.State {
+	pb := &globalv1.State{
+		Rev:      s.Rev,
+		Nodes:    s.Nodes,
+		Services: make([]*globalv1.ServiceEndpoints, 0, len(s.Services)),
+	}
+
+	for _, se := range s.Services {
+		pb.Services = append(pb.Services, &globalv1.ServiceEndpoints{
+			Service:   se.Service,
+			Endpoints: se.Endpoints,
+		})
+	}
+
+	return pb
+}
+
+func GlobalStateFromProto(pb *globalv1.State) GlobalState {
+	s := GlobalState{
+		Rev:      pb.Rev,
+		Nodes:    pb.Nodes,
+		Services: make([]ServiceAndEndpoints, 0, len(pb.Services)),
+	}
+
+	for _, se := range pb.Services {
+		s.Services = append(s.Services, ServiceAndEndpoints{
+			Service:   se.Service,
+			Endpoints: se.Endpoints,
+		})
+	}
+
+	return s
+}
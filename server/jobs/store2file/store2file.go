@@ -18,10 +18,15 @@ package store2file
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
-	"gopkg.in/yaml.v2"
 
 	"k8s.io/klog/v2"
 
@@ -32,10 +37,20 @@ import (
 
 type Config struct {
 	FilePath string
+	// Format is the output format (see Formats), guessed from the file extension if empty
+	Format string
+	// MinInterval is the minimum time between two writes; changes are coalesced meanwhile
+	MinInterval time.Duration
+	// History is the number of previous states kept next to the output (and a copy of the current
+	// one), suffixed by their revision
+	History int
 }
 
 func (c *Config) BindFlags(flags *pflag.FlagSet) {
 	flags.StringVarP(&c.FilePath, "output", "o", "global-state.yaml", "Output file for the global state")
+	flags.StringVar(&c.Format, "output-format", "", "Output format ("+strings.Join(Formats, ", ")+"); guessed from the output file extension if not set")
+	flags.DurationVar(&c.MinInterval, "output-min-interval", 0, "Minimum time between two writes of the output")
+	flags.IntVar(&c.History, "output-history", 0, "Number of previous states to keep as <output>.<revision>")
 }
 
 type Job struct {
@@ -44,12 +59,31 @@ type Job struct {
 }
 
 func (j *Job) Run(ctx context.Context) (err error) {
+	format := j.Config.Format
+	if format == "" {
+		format = FormatFromPath(j.Config.FilePath)
+	}
+
+	encode, ok := encoders[format]
+	if !ok {
+		return fmt.Errorf("unknown output format %q", format)
+	}
+
 	var (
-		rev    uint64
-		closed = false
+		rev       uint64
+		closed    = false
+		lastWrite time.Time
 	)
 
 	for !closed {
+		if wait := time.Until(lastWrite.Add(j.Config.MinInterval)); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return
+			}
+		}
+
 		state := GlobalState{}
 		ok := false
 
@@ -58,6 +92,8 @@ func (j *Job) Run(ctx context.Context) (err error) {
 				return
 			}
 
+			state.Rev = tx.Rev()
+
 			tx.Each(proxystore.Nodes, func(kv *proxystore.KV) bool {
 				state.Nodes = append(state.Nodes, kv.Node.Node)
 				return true
@@ -85,25 +121,104 @@ func (j *Job) Run(ctx context.Context) (err error) {
 		}
 
 		// write the output
-		var out *os.File
-		out, err = os.Create(j.Config.FilePath)
+		var data []byte
+		data, err = encode(state)
 		if err != nil {
 			return
 		}
 
-		err = yaml.NewEncoder(out).Encode(state)
-		out.Close()
+		err = writeFile(j.Config.FilePath, data)
 		if err != nil {
 			return
 		}
 
-		klog.Info("wrote global state")
+		lastWrite = time.Now()
+
+		if j.Config.History > 0 {
+			err = writeFile(j.Config.FilePath+"."+strconv.FormatUint(state.Rev, 10), data)
+			if err != nil {
+				return
+			}
+
+			j.pruneHistory()
+		}
+
+		klog.Info("wrote global state at revision ", state.Rev)
 	}
 
 	return
 }
 
+// writeFile atomically replaces the file at path with data, so readers never see partial writes.
+func writeFile(path string, data []byte) (err error) {
+	// the temporary file must be on the same filesystem for the rename to be atomic
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Chmod(0o644)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// pruneHistory removes the oldest previous states beyond the configured history. The current
+// state's copy is not a previous state, so it's kept on top of them.
+func (j *Job) pruneHistory() {
+	keep := j.Config.History + 1
+
+	prefix := j.Config.FilePath + "."
+
+	matches, err := filepath.Glob(prefix + "*")
+	if err != nil {
+		klog.Error("failed to list the output history: ", err)
+		return
+	}
+
+	type entry struct {
+		path string
+		rev  uint64
+	}
+
+	history := make([]entry, 0, len(matches))
+	for _, path := range matches {
+		rev, err := strconv.ParseUint(strings.TrimPrefix(path, prefix), 10, 64)
+		if err != nil {
+			continue // not a history file
+		}
+		history = append(history, entry{path, rev})
+	}
+
+	if len(history) <= keep {
+		return
+	}
+
+	sort.Slice(history, func(i, k int) bool { return history[i].rev < history[k].rev })
+
+	for _, e := range history[:len(history)-keep] {
+		if err := os.Remove(e.path); err != nil {
+			klog.Error("failed to remove old state: ", err)
+		}
+	}
+}
+
 type GlobalState struct {
+	Rev      uint64 `yaml:",omitempty"`
 	Nodes    []*globalv1.Node
 	Services []ServiceAndEndpoints
 }
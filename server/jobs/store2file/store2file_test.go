@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store2file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"sigs.k8s.io/kpng/api/globalv1"
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+func testState() GlobalState {
+	return GlobalState{
+		Rev:   42,
+		Nodes: []*globalv1.Node{{Name: "node-1", Labels: map[string]string{"a": "b"}}},
+		Services: []ServiceAndEndpoints{
+			{
+				Service: &localv1.Service{
+					Namespace: "ns",
+					Name:      "svc",
+					Ports:     []*localv1.PortMapping{{Protocol: localv1.Protocol_TCP, Port: 80}},
+				},
+				Endpoints: []*globalv1.EndpointInfo{
+					{Namespace: "ns", ServiceName: "svc", Endpoint: &localv1.Endpoint{IPs: localv1.NewIPSet("10.1.0.1")}},
+				},
+			},
+		},
+	}
+}
+
+func TestFormats(t *testing.T) {
+	state := testState()
+
+	for _, format := range Formats {
+		data, err := encoders[format](state)
+		if err != nil {
+			t.Errorf("%s: failed to encode: %v", format, err)
+			continue
+		}
+
+		decoded, err := Decode(format, data)
+		if err != nil {
+			t.Errorf("%s: failed to decode: %v", format, err)
+			continue
+		}
+
+		if !proto.Equal(state.ToProto(), decoded.ToProto()) {
+			t.Errorf("%s: decoded state differs:\n%s", format, string(data))
+		}
+	}
+}
+
+func TestJobHistory(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "state.json")
+
+	store := proxystore.New()
+	setNode := func(name string) {
+		store.Update(func(tx *proxystore.Tx) {
+			tx.SetNode(&globalv1.Node{Name: name})
+			for _, set := range proxystore.AllSets {
+				tx.SetSync(set)
+			}
+		})
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- (&Job{Store: store, Config: &Config{FilePath: output, History: 2}}).Run(context.Background())
+	}()
+
+	waitOutput := func() {
+		t.Helper()
+
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			data, err := os.ReadFile(output)
+			if err == nil {
+				state, err := Decode(JSON, data)
+				if err != nil {
+					t.Fatal("invalid output: ", err)
+				}
+				if state.Rev == store.Rev() {
+					return
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatal("output not written")
+	}
+
+	for _, name := range []string{"a", "b", "c", "d"} {
+		setNode(name)
+		waitOutput()
+	}
+
+	store.Close()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	rev := store.Rev()
+	expected := []string{
+		"state.json",
+		"state.json." + strconv.FormatUint(rev-2, 10),
+		"state.json." + strconv.FormatUint(rev-1, 10),
+		"state.json." + strconv.FormatUint(rev, 10),
+	}
+
+	if len(names) != len(expected) {
+		t.Fatalf("expected files %v, got %v", expected, names)
+	}
+	for i := range names {
+		if names[i] != expected[i] {
+			t.Errorf("expected files %v, got %v", expected, names)
+			break
+		}
+	}
+}
@@ -57,6 +57,11 @@ func (j *Job) Wait() (err error) {
 	return j.Sink.Wait()
 }
 
+// ViewKey implements store2diff.Sink; every watcher has the same view of the global state.
+func (j *Job) ViewKey() string {
+	return ""
+}
+
 func (j *Job) Update(tx *proxystore.Tx, w *watchstate.WatchState) {
 	if !tx.AllSynced() {
 		return
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store2localdiff
+
+import (
+	"sync"
+
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/server/pkg/metrics"
+)
+
+// Cache shares the endpoints selected for a service between the watchers of a revision, when
+// the selection doesn't depend on the node itself: the node has no endpoint of the service and
+// the selector gives it the same key as other nodes (ie: the same zone, see endpoints.NodeKeyer).
+//
+// The watchers sharing a cache must use the same selector.
+type Cache struct {
+	mu      sync.Mutex
+	rev     uint64
+	entries map[cacheKey][]localEndpoint
+}
+
+type cacheKey struct {
+	service string
+	node    string
+}
+
+// localEndpoint is an endpoint selected for a node, as set in the watch state.
+type localEndpoint struct {
+	anonymous bool // no pod name
+	key       []byte
+	hash      uint64
+	endpoint  *localv1.Endpoint
+}
+
+// get returns the endpoints for the key at rev, calling compute if they're not known yet.
+// Only the latest revision is kept.
+func (c *Cache) get(rev uint64, key cacheKey, compute func() []localEndpoint) []localEndpoint {
+	c.mu.Lock()
+	if rev > c.rev {
+		c.rev = rev
+		c.entries = map[cacheKey][]localEndpoint{}
+	}
+
+	current := rev == c.rev
+	if current {
+		if eps, ok := c.entries[key]; ok {
+			c.mu.Unlock()
+			metrics.Kpng_local_state_cache_hits.Inc()
+			return eps
+		}
+	}
+	c.mu.Unlock()
+
+	metrics.Kpng_local_state_cache_misses.Inc()
+
+	eps := compute()
+
+	if current {
+		c.mu.Lock()
+		if rev == c.rev {
+			c.entries[key] = eps
+		}
+		c.mu.Unlock()
+	}
+
+	return eps
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store2localdiff
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/labels"
+
+	"sigs.k8s.io/kpng/api/localv1"
+)
+
+// filter is a parsed localv1.WatchFilter. A nil filter matches all services.
+type filter struct {
+	namespaces map[string]bool
+	selector   labels.Selector
+	types      map[string]bool
+}
+
+func newFilter(f *localv1.WatchFilter) (*filter, error) {
+	if len(f.GetNamespaces()) == 0 && f.GetLabelSelector() == "" && len(f.GetServiceTypes()) == 0 {
+		return nil, nil
+	}
+
+	flt := &filter{}
+
+	if len(f.Namespaces) != 0 {
+		flt.namespaces = make(map[string]bool, len(f.Namespaces))
+		for _, ns := range f.Namespaces {
+			flt.namespaces[ns] = true
+		}
+	}
+
+	if f.LabelSelector != "" {
+		selector, err := labels.Parse(f.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector %q: %w", f.LabelSelector, err)
+		}
+		flt.selector = selector
+	}
+
+	if len(f.ServiceTypes) != 0 {
+		flt.types = make(map[string]bool, len(f.ServiceTypes))
+		for _, t := range f.ServiceTypes {
+			flt.types[t] = true
+		}
+	}
+
+	return flt, nil
+}
+
+// match returns true if the service passes the filter.
+func (f *filter) match(svc *localv1.Service) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.namespaces != nil && !f.namespaces[svc.Namespace] {
+		return false
+	}
+
+	if f.types != nil && !f.types[svc.Type] {
+		return false
+	}
+
+	if f.selector != nil && !f.selector.Matches(labels.Set(svc.Labels)) {
+		return false
+	}
+
+	return true
+}
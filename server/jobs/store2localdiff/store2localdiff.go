@@ -21,6 +21,11 @@ import (
 	"runtime/trace"
 	"strconv"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"sigs.k8s.io/kpng/api/globalv1"
 	"sigs.k8s.io/kpng/api/localv1"
 	"sigs.k8s.io/kpng/client/lightdiffstore"
 	"sigs.k8s.io/kpng/client/localsink"
@@ -31,26 +36,53 @@ import (
 	"sigs.k8s.io/kpng/server/serde"
 )
 
+var sets = []localv1.Set{
+	// Each one of these Sets will be used in a diffstore below.
+	localv1.Set_ServicesSet,  // setN 0
+	localv1.Set_EndpointsSet, // setN 0
+	localv1.Set_EndpointsSet, // setN 1
+	// 2nd endpoints set for endpoints which do not have a corresponding pod name
+	localv1.Set_NodesSet, // setN 0
+}
+
 type Job struct {
 	Store *proxystore.Store
 	Sink  localsink.Sink
+
+	// Views and Resume allow the watcher to resume from its current state (see store2diff.Job)
+	Views  *store2diff.Views
+	Resume uint64
+
+	// Selector selects the node's endpoints (nil for the default selection)
+	Selector endpoints.Selector
+
+	// Cache, if not nil, shares the endpoints selected for this watcher with other ones
+	Cache *Cache
+
+	// Coalescing collapses bursts of revisions into one Sync (see store2diff.Job)
+	Coalescing store2diff.Coalescing
 }
 
 func (j *Job) Run(ctx context.Context) error {
+	selector := j.Selector
+	if selector == nil {
+		selector = endpoints.Default{}
+	}
+
 	run := &jobRun{
-		Sink: j.Sink,
+		Sink:     j.Sink,
+		selector: selector,
+		cache:    j.Cache,
 	}
 
 	job := &store2diff.Job{
-		Store: j.Store,
-		Sets: []localv1.Set{
-			// Each one of these Sets will be used in a diffstore below.
-			localv1.Set_ServicesSet,  // setN 0
-			localv1.Set_EndpointsSet, // setN 0
-			localv1.Set_EndpointsSet, // setN 1
-			// 2nd endpoints set for endpoints which do not have a corresponding pod name
-		},
-		Sink: run,
+		Store:  j.Store,
+		Sets:   sets,
+		Sink:   run,
+		Views:  j.Views,
+		Resume: j.Resume,
+
+		Coalescing: j.Coalescing,
 	}
 
 	j.Sink.Setup()
@@ -61,13 +93,57 @@ func (j *Job) Run(ctx context.Context) error {
 type jobRun struct {
 	localsink.Sink
 	nodeName string
+	selector endpoints.Selector
+	cache    *Cache
+
+	// the services filter, and the request it comes from
+	filter        *filter
+	filterReq     *localv1.WatchFilter
+	filterChanged bool
 }
 
+var (
+	_ store2diff.ViewChanger   = &jobRun{}
+	_ store2diff.ResumeChecker = &jobRun{}
+)
+
 func (s *jobRun) Wait() (err error) {
-	s.nodeName, err = s.WaitRequest()
+	nodeName, err := s.WaitRequest()
+	if err != nil {
+		return
+	}
+
+	s.nodeName = nodeName
+
+	req := localsink.WatchFilter(s.Sink)
+
+	s.filterChanged = !proto.Equal(req, s.filterReq)
+	if s.filterChanged {
+		s.filter, err = newFilter(req)
+		if err != nil {
+			err = status.Error(codes.InvalidArgument, err.Error())
+			return
+		}
+		s.filterReq = req
+	}
+
 	return
 }
 
+func (s *jobRun) ViewChanged() bool {
+	return s.filterChanged
+}
+
+func (s *jobRun) ViewKey() string {
+	return s.nodeName
+}
+
+// CanResume forwards to the sink, if it can refuse the watcher's resume.
+func (s *jobRun) CanResume() bool {
+	rc, ok := s.Sink.(store2diff.ResumeChecker)
+	return !ok || rc.CanResume()
+}
+
 func (s *jobRun) Update(tx *proxystore.Tx, w *watchstate.WatchState) {
 	if !tx.AllSynced() {
 		return
@@ -84,58 +160,155 @@ func (s *jobRun) Update(tx *proxystore.Tx, w *watchstate.WatchState) {
 	svcs := w.StoreForN(localv1.Set_ServicesSet, 0)
 	seps := w.StoreForN(localv1.Set_EndpointsSet, 0)
 	sepsAnonymous := w.StoreForN(localv1.Set_EndpointsSet, 1)
+	nodes := w.StoreFor(localv1.Set_NodesSet)
+
+	node := tx.GetNode(nodeName)
+
+	// the node itself, so sinks can get its addresses and pod CIDRs from the cluster
+	if node != nil {
+		_, name := proxystore.SplitNodeName(node.Name)
+		localNode := &localv1.Node{
+			Name:        name,
+			Labels:      node.Labels,
+			Annotations: node.Annotations,
+			InternalIPs: node.InternalIPs,
+			ExternalIPs: node.ExternalIPs,
+			PodCIDRs:    node.PodCIDRs,
+		}
+
+		nodes.Set([]byte(nodeName), serde.Hash(localNode), localNode)
+	}
+
+	// the node as seen by the selections, and its cluster to see the services from
+	selNode := endpoints.GetNode(tx, nodeName)
+	cluster := selNode.GetTopology().GetCluster()
+
+	// the node's key, if its selections can be shared with other nodes
+	nodeKey, shared := "", false
+	if s.cache != nil {
+		nodeKey, shared = endpoints.NodeKey(s.selector, selNode)
+	}
 
 	// set all new values
 	tx.Each(proxystore.Services, func(kv *proxystore.KV) bool {
+		svc := kv.Service.ServiceFor(cluster)
+		if svc == nil {
+			return true // not available in the node's cluster
+		}
+
+		if !s.filter.match(svc) {
+			return true // filtered out, with its endpoints
+		}
+
 		key := []byte(kv.Namespace + "/" + kv.Name)
 
 		if trace.IsEnabled() {
 			trace.Log(ctx, "service", string(key))
 		}
-		svcs.Set(key, kv.Service.Hash, kv.Service.Service)
-
-		// iterate through ONLY the endpoints which are valid for
-		// this node to loadbalance to (i.e. in cases of
-		// topology constraints or trafficPolicy=Local,
-		// some endpoints may not be available for
-		// node to route to).
-		for _, ei := range endpoints.ForNode(tx, kv.Service, nodeName) {
-			// endpoints are not hashed, so hash, but hash ONLY the endpoint.
-			// to avoid false diff triggering in cases where endpoint metadata
-			// not relevant for "local" decision making (i.e. an endpoint
-			// annotation or label that is non-consequential).
-			hash := serde.Hash(ei.Endpoint)
-
-			var epKey []byte
-			// set is a localv1.Set
-			var set *lightdiffstore.DiffStore
-
-			if ei.PodName == "" {
+
+		hash := kv.Service.Hash
+		if svc != kv.Service.Service {
+			hash = serde.Hash(svc) // with the cluster's own IPs
+		} else if t := svc.LastChangeTriggerTime; t != 0 {
+			// not in the service's hash, but sent again on each change of its endpoints, so
+			// backends also observe the changes no endpoint carries (ie: deletions)
+			hash ^= uint64(t) * 0x9e3779b97f4a7c15
+		}
+		svcs.Set(key, hash, svc)
+
+		var eps []localEndpoint
+		if shared && !hasEndpointOnNode(tx, svc, selNode) {
+			eps = s.cache.get(tx.Rev(), cacheKey{service: string(key), node: nodeKey}, func() []localEndpoint {
+				return s.selectEndpoints(tx, kv.Service, key)
+			})
+		} else {
+			eps = s.selectEndpoints(tx, kv.Service, key)
+		}
+
+		for _, ep := range eps {
+			set := seps
+			if ep.anonymous {
 				set = sepsAnonymous
-				// key is service key + endpoint hash (64 bits, in hex)
-				epKey = append(make([]byte, 0, len(key)+1+64/8*2), key...)
-				epKey = append(epKey, '/')
-				epKey = strconv.AppendUint(epKey, hash, 16)
-			} else {
-				set = seps
-				// key is service key + podName
-				epKey = append(make([]byte, 0, len(key)+1+len(ei.PodName)), key...)
-				epKey = append(epKey, '/')
-				epKey = append(epKey, []byte(ei.PodName)...)
 			}
 
 			if trace.IsEnabled() {
-				trace.Log(ctx, "endpoint", string(epKey))
+				trace.Log(ctx, "endpoint", string(ep.key))
 			}
 
 			// Insert or update this key in the diffstore
-			set.Set(epKey, hash, ei.Endpoint)
+			set.Set(ep.key, ep.hash, ep.endpoint)
 		}
 
 		return true
 	})
 }
 
+// selectEndpoints returns the endpoints of the service for the node, keyed for the watch state.
+func (s *jobRun) selectEndpoints(tx *proxystore.Tx, si *globalv1.ServiceInfo, key []byte) (eps []localEndpoint) {
+	// iterate through ONLY the endpoints which are valid for
+	// this node to loadbalance to (i.e. in cases of
+	// topology constraints or trafficPolicy=Local,
+	// some endpoints may not be available for
+	// node to route to).
+	infos := endpoints.ForNodeWith(s.selector, tx, si, s.nodeName)
+
+	eps = make([]localEndpoint, 0, len(infos))
+
+	for _, ei := range infos {
+		// endpoints are not hashed, so hash, but hash ONLY the endpoint.
+		// to avoid false diff triggering in cases where endpoint metadata
+		// not relevant for "local" decision making (i.e. an endpoint
+		// annotation or label that is non-consequential).
+		hash := serde.Hash(ei.Endpoint)
+
+		// the trigger time is carried outside the hash, so a new trigger time alone doesn't
+		// produce a diff; the store's endpoint is shared, so it's set on a copy.
+		endpoint := ei.Endpoint
+		if ei.LastChangeTriggerTime != 0 {
+			endpoint = proto.Clone(ei.Endpoint).(*localv1.Endpoint)
+			endpoint.LastChangeTriggerTime = ei.LastChangeTriggerTime
+		}
+
+		var epKey []byte
+
+		if ei.PodName == "" {
+			// key is service key + endpoint hash (64 bits, in hex)
+			epKey = append(make([]byte, 0, len(key)+1+64/8*2), key...)
+			epKey = append(epKey, '/')
+			epKey = strconv.AppendUint(epKey, hash, 16)
+		} else {
+			// key is service key + podName (qualified by the cluster, if any)
+			podName := ei.PodName
+			if cluster := ei.Topology.GetCluster(); cluster != "" {
+				podName = cluster + "/" + podName
+			}
+
+			epKey = append(make([]byte, 0, len(key)+1+len(podName)), key...)
+			epKey = append(epKey, '/')
+			epKey = append(epKey, []byte(podName)...)
+		}
+
+		eps = append(eps, localEndpoint{
+			anonymous: ei.PodName == "",
+			key:       epKey,
+			hash:      hash,
+			endpoint:  endpoint,
+		})
+	}
+
+	return
+}
+
+// hasEndpointOnNode returns true if the service has an endpoint on the node.
+func hasEndpointOnNode(tx *proxystore.Tx, svc *localv1.Service, node *globalv1.Node) (found bool) {
+	tx.EachEndpointOfService(svc.Namespace, svc.Name, func(ei *globalv1.EndpointInfo) {
+		if endpoints.OnNode(ei, node) {
+			found = true
+		}
+	})
+	return
+}
+
 // SendDiff implements the store2diff interface.  Called whenever
 // the store2diff implementation recieves an updated from the underlying store.
 // See the store2diff impl for this logic.
@@ -145,6 +318,9 @@ func (*jobRun) SendDiff(w *watchstate.WatchState) (updated bool) {
 
 	count := 0
 
+	// Update the node first, so it's known when services are handled.
+	count += w.SendUpdates(localv1.Set_NodesSet)
+
 	// Create any service first, to avoid orphan endpoints being sent.
 	count += w.SendUpdates(localv1.Set_ServicesSet)
 
@@ -163,6 +339,8 @@ func (*jobRun) SendDiff(w *watchstate.WatchState) (updated bool) {
 	// prematurely.
 	count += w.SendDeletes(localv1.Set_ServicesSet)
 
+	count += w.SendDeletes(localv1.Set_NodesSet)
+
 	// Tell the diffstore that every item is now in the previous
 	// window, so the store is empty.
 	w.Reset(lightdiffstore.ItemDeleted)
@@ -0,0 +1,622 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store2localdiff
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/protobuf/proto"
+
+	"sigs.k8s.io/kpng/api/globalv1"
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/client/lightdiffstore"
+	"sigs.k8s.io/kpng/client/statedigest"
+	"sigs.k8s.io/kpng/server/jobs/store2diff"
+	"sigs.k8s.io/kpng/server/pkg/endpoints"
+	"sigs.k8s.io/kpng/server/pkg/metrics"
+	"sigs.k8s.io/kpng/server/pkg/server/watchstate"
+	"sigs.k8s.io/kpng/server/proxystore"
+	"sigs.k8s.io/kpng/server/serde"
+)
+
+// testSink answers the first request (or all of them if continuous), then waits for its context
+// to be canceled.
+type testSink struct {
+	ctx        context.Context
+	ops        chan *localv1.OpItem
+	requested  bool
+	continuous bool
+}
+
+func (s *testSink) Setup() {}
+func (s *testSink) Reset() {}
+
+func (s *testSink) WaitRequest() (string, error) {
+	if !s.requested || s.continuous {
+		s.requested = true
+		return "node-a", nil
+	}
+
+	<-s.ctx.Done()
+	return "", s.ctx.Err()
+}
+
+func (s *testSink) Send(op *localv1.OpItem) error {
+	s.ops <- op
+	return nil
+}
+
+func setService(store *proxystore.Store, name string) {
+	store.Update(func(tx *proxystore.Tx) {
+		tx.SetService(&localv1.Service{
+			Namespace: "default",
+			Name:      name,
+			Type:      "ClusterIP",
+			IPs:       &localv1.ServiceIPs{ClusterIPs: localv1.NewIPSet("10.1.1.1")},
+		})
+
+		for _, set := range proxystore.AllSets {
+			tx.SetSync(set)
+		}
+	})
+}
+
+// runWatch runs a watch until the first sync, then disconnects it, returning the received ops.
+func runWatch(t *testing.T, store *proxystore.Store, views *store2diff.Views, digest *statedigest.Digest) (ops []string) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sink := &testSink{ctx: ctx, ops: make(chan *localv1.OpItem, 100)}
+
+	job := &Job{
+		Store:  store,
+		Sink:   sink,
+		Views:  views,
+		Resume: digest.Sum(),
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- job.Run(ctx) }()
+
+	for {
+		op := <-sink.ops
+		digest.Apply(op)
+
+		switch v := op.Op.(type) {
+		case *localv1.OpItem_Reset_:
+			ops = append(ops, "reset")
+		case *localv1.OpItem_Set:
+			ops = append(ops, fmt.Sprint("set ", v.Set.Ref.Set, " ", v.Set.Ref.Path))
+		case *localv1.OpItem_Delete:
+			ops = append(ops, fmt.Sprint("delete ", v.Delete.Set, " ", v.Delete.Path))
+		case *localv1.OpItem_Sync:
+			cancel()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("watch did not stop")
+			}
+			return
+		}
+	}
+}
+
+func TestResume(t *testing.T) {
+	store := proxystore.New()
+	views := store2diff.NewViews(time.Minute)
+	digest := statedigest.New()
+
+	setService(store, "svc-a")
+
+	check := func(step string, ops []string, expected ...string) {
+		if fmt.Sprint(ops) != fmt.Sprint(expected) {
+			t.Errorf("%s: expected ops %q, got %q", step, expected, ops)
+		}
+	}
+
+	check("first watch", runWatch(t, store, views, digest),
+		"reset", "set ServicesSet default/svc-a")
+
+	setService(store, "svc-b")
+
+	check("resumed watch", runWatch(t, store, views, digest),
+		"set ServicesSet default/svc-b")
+
+	// new server instance, state unchanged
+	check("restarted server", runWatch(t, store, store2diff.NewViews(time.Minute), digest))
+
+	// unknown client state
+	digest.Set(localv1.Set_ServicesSet, "default/svc-c", []byte("unknown"))
+	check("unknown state", runWatch(t, store, views, digest),
+		"reset", "set ServicesSet default/svc-a", "set ServicesSet default/svc-b")
+}
+
+// runCoalescing runs a continuous watch with the coalescing, and returns its store and sink with a
+// function waiting for the next sync, returning its number of sets.
+func runCoalescing(t *testing.T, coalescing store2diff.Coalescing) (store *proxystore.Store, sink *testSink, nextSync func() (sets int)) {
+	store = proxystore.New()
+	setService(store, "svc-a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	sink = &testSink{ctx: ctx, ops: make(chan *localv1.OpItem, 100), continuous: true}
+
+	job := &Job{
+		Store:      store,
+		Sink:       sink,
+		Coalescing: coalescing,
+	}
+	go job.Run(ctx)
+
+	nextSync = func() (sets int) {
+		for {
+			select {
+			case op := <-sink.ops:
+				switch op.Op.(type) {
+				case *localv1.OpItem_Set:
+					sets++
+				case *localv1.OpItem_Sync:
+					return
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("no sync received")
+			}
+		}
+	}
+
+	if sets := nextSync(); sets != 1 {
+		t.Fatalf("expected 1 set in the first sync, got %d", sets)
+	}
+
+	return
+}
+
+func TestCoalescing(t *testing.T) {
+	store, sink, nextSync := runCoalescing(t, store2diff.Coalescing{MinInterval: 100 * time.Millisecond, MaxDelay: 10 * time.Second})
+
+	coalesced := testutil.ToFloat64(metrics.Kpng_local_coalesced_revisions)
+
+	for _, name := range []string{"svc-b", "svc-c", "svc-d"} {
+		setService(store, name)
+	}
+
+	if sets := nextSync(); sets != 3 {
+		t.Errorf("expected the burst in one sync with 3 sets, got %d", sets)
+	}
+
+	if delta := testutil.ToFloat64(metrics.Kpng_local_coalesced_revisions) - coalesced; delta != 2 {
+		t.Errorf("expected 2 coalesced revisions, got %v", delta)
+	}
+
+	select {
+	case op := <-sink.ops:
+		t.Errorf("unexpected op after the burst: %v", op)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestCoalescingTrickle(t *testing.T) {
+	minInterval := 100 * time.Millisecond
+
+	store, _, nextSync := runCoalescing(t, store2diff.Coalescing{MinInterval: minInterval, MaxDelay: 10 * time.Second})
+
+	// a revision every 10ms for 1s, so the store is never quiet for MinInterval
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			setService(store, fmt.Sprint("svc-", i))
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	start := time.Now()
+
+	syncs, sets := 0, 0
+	for sets < 100 {
+		sets += nextSync()
+		syncs++
+	}
+	<-done
+
+	// syncs keep coming during the trickle, but at most one per MinInterval
+	if max := int(time.Since(start)/minInterval) + 1; syncs < 3 || syncs > max {
+		t.Errorf("expected between 3 and %d syncs during the trickle, got %d", max, syncs)
+	}
+}
+
+func TestCoalescingMaxDelay(t *testing.T) {
+	maxDelay := 100 * time.Millisecond
+
+	store, _, nextSync := runCoalescing(t, store2diff.Coalescing{MinInterval: 10 * time.Second, MaxDelay: maxDelay})
+
+	start := time.Now()
+	setService(store, "svc-b")
+
+	if sets := nextSync(); sets != 1 {
+		t.Errorf("expected 1 set, got %d", sets)
+	}
+
+	if delay := time.Since(start); delay < maxDelay*9/10 || delay > 5*maxDelay {
+		t.Errorf("expected the sync after MaxDelay (%v), got it after %v", maxDelay, delay)
+	}
+}
+
+func TestNode(t *testing.T) {
+	store := proxystore.New()
+
+	store.Update(func(tx *proxystore.Tx) {
+		tx.SetNode(&globalv1.Node{Name: "node-a", PodCIDRs: []string{"10.244.1.0/24"}})
+		tx.SetNode(&globalv1.Node{Name: "node-b", PodCIDRs: []string{"10.244.2.0/24"}})
+	})
+	setService(store, "svc-a")
+
+	ops := runWatch(t, store, nil, statedigest.New())
+
+	expected := []string{"reset", "set NodesSet node-a", "set ServicesSet default/svc-a"}
+	if fmt.Sprint(ops) != fmt.Sprint(expected) {
+		t.Errorf("expected ops %q, got %q", expected, ops)
+	}
+}
+
+// discardSink ignores the ops sent to it.
+type discardSink struct{}
+
+func (discardSink) Send(*localv1.OpItem) error { return nil }
+
+// zonedStore returns a store with nodes spread over 3 zones, and services having one hinted
+// endpoint per zone.
+func zonedStore(nodes, services int) *proxystore.Store {
+	store := proxystore.New()
+
+	zone := func(i int) string { return fmt.Sprint("zone-", i%3) }
+
+	store.Update(func(tx *proxystore.Tx) {
+		for i := 0; i < nodes; i++ {
+			tx.SetNode(&globalv1.Node{
+				Name:     fmt.Sprint("node-", i),
+				Topology: &globalv1.TopologyInfo{Node: fmt.Sprint("node-", i), Zone: zone(i)},
+			})
+		}
+
+		for i := 0; i < services; i++ {
+			name := fmt.Sprint("svc-", i)
+
+			tx.SetService(&localv1.Service{
+				Namespace: "default",
+				Name:      name,
+				Type:      "ClusterIP",
+				IPs:       &localv1.ServiceIPs{ClusterIPs: localv1.NewIPSet(fmt.Sprintf("10.1.%d.%d", i/256, i%256))},
+			})
+
+			infos := make([]*globalv1.EndpointInfo, 0, 3)
+			for n := 0; n < 3; n++ {
+				infos = append(infos, &globalv1.EndpointInfo{
+					Namespace:   "default",
+					SourceName:  name + "-abcde",
+					ServiceName: name,
+					PodName:     fmt.Sprint(name, "-", n),
+					Endpoint:    &localv1.Endpoint{IPs: localv1.NewIPSet(fmt.Sprintf("10.2.%d.%d", i%256, n))},
+					Conditions:  &globalv1.EndpointConditions{Ready: true},
+					Topology:    &globalv1.TopologyInfo{Node: fmt.Sprint("node-", n), Zone: zone(n)},
+					Hints:       &globalv1.TopologyHints{Zones: []string{zone(n)}},
+				})
+			}
+			tx.SetEndpointsOfSource("default", name+"-abcde", infos)
+		}
+
+		for _, set := range proxystore.AllSets {
+			tx.SetSync(set)
+		}
+	})
+
+	return store
+}
+
+// localEndpoints computes the local state of the node, and returns its endpoints.
+func localEndpoints(store *proxystore.Store, nodeName string, cache *Cache) string {
+	run := &jobRun{nodeName: nodeName, selector: endpoints.Default{}, cache: cache}
+	w := watchstate.New(discardSink{}, sets)
+
+	store.View(0, func(tx *proxystore.Tx) {
+		run.Update(tx, w)
+	})
+
+	return fmt.Sprint(w.StoreFor(localv1.Set_EndpointsSet).Updated())
+}
+
+func TestCache(t *testing.T) {
+	store := zonedStore(6, 1)
+	cache := &Cache{}
+
+	hits := testutil.ToFloat64(metrics.Kpng_local_state_cache_hits)
+
+	for _, nodeName := range []string{"node-3", "node-4", "node-0", "node-5", "node-6"} {
+		expected := localEndpoints(store, nodeName, nil)
+
+		if eps := localEndpoints(store, nodeName, cache); eps != expected {
+			t.Errorf("%s: expected endpoints %s, got %s", nodeName, expected, eps)
+		}
+	}
+
+	// node-0 has an endpoint, node-3 computed zone-0, node-4 zone-1, node-5 zone-2, node-6 is unknown
+	if h := testutil.ToFloat64(metrics.Kpng_local_state_cache_hits) - hits; h != 0 {
+		t.Errorf("expected no cache hit, got %v", h)
+	}
+
+	if n := len(cache.entries); n != 4 {
+		t.Errorf("expected 4 cache entries, got %d", n)
+	}
+
+	for _, nodeName := range []string{"node-3", "node-4", "node-5"} {
+		localEndpoints(store, nodeName, cache)
+	}
+
+	if h := testutil.ToFloat64(metrics.Kpng_local_state_cache_hits) - hits; h != 3 {
+		t.Errorf("expected 3 cache hits, got %v", h)
+	}
+
+	// a new revision clears the cache (svc-1 has no endpoint but is cached too)
+	setService(store, "svc-1")
+	localEndpoints(store, "node-3", cache)
+
+	if n := len(cache.entries); n != 2 {
+		t.Errorf("expected 2 cache entries, got %d", n)
+	}
+}
+
+func TestTriggerTime(t *testing.T) {
+	store := zonedStore(3, 1)
+
+	// move node-0's endpoint, triggered at 42
+	var infos []*globalv1.EndpointInfo
+	store.View(0, func(tx *proxystore.Tx) {
+		tx.EachEndpointOfService("default", "svc-0", func(ei *globalv1.EndpointInfo) {
+			if ei.Topology.Node == "node-0" {
+				ei = proto.Clone(ei).(*globalv1.EndpointInfo)
+				ei.Endpoint.IPs = localv1.NewIPSet("10.3.0.0")
+				ei.LastChangeTriggerTime = 42
+			}
+			infos = append(infos, ei)
+		})
+	})
+	store.Update(func(tx *proxystore.Tx) {
+		tx.SetEndpointsOfSource("default", "svc-0-abcde", infos)
+	})
+
+	run := &jobRun{nodeName: "node-0", selector: endpoints.Default{}}
+
+	var eps []localEndpoint
+	store.View(0, func(tx *proxystore.Tx) {
+		tx.Each(proxystore.Services, func(kv *proxystore.KV) bool {
+			eps = run.selectEndpoints(tx, kv.Service, []byte("default/svc-0"))
+			return false
+		})
+	})
+
+	if len(eps) != 1 {
+		t.Fatalf("expected 1 endpoint, got %d", len(eps))
+	}
+
+	ep := eps[0]
+	if tt := ep.endpoint.LastChangeTriggerTime; tt != 42 {
+		t.Errorf("expected trigger time 42, got %d", tt)
+	}
+	untriggered := proto.Clone(ep.endpoint).(*localv1.Endpoint)
+	untriggered.LastChangeTriggerTime = 0
+	if hash := serde.Hash(untriggered); ep.hash != hash {
+		t.Errorf("the trigger time changed the hash: %x != %x", ep.hash, hash)
+	}
+
+	store.View(0, func(tx *proxystore.Tx) {
+		tx.EachEndpointOfService("default", "svc-0", func(ei *globalv1.EndpointInfo) {
+			if tt := ei.Endpoint.LastChangeTriggerTime; tt != 0 {
+				t.Errorf("store endpoint modified: trigger time %d", tt)
+			}
+		})
+	})
+}
+
+func TestServiceTriggerTime(t *testing.T) {
+	store := proxystore.New()
+	views := store2diff.NewViews(time.Minute)
+	digest := statedigest.New()
+
+	setService(store, "svc-a")
+
+	// endpoints on other nodes, so node-a's endpoints are the same after a scale-down
+	setEndpoints := func(triggerTime int64, nodes ...string) {
+		infos := make([]*globalv1.EndpointInfo, 0, len(nodes))
+		for i, node := range nodes {
+			infos = append(infos, &globalv1.EndpointInfo{
+				Namespace:   "default",
+				ServiceName: "svc-a",
+				SourceName:  "svc-a-abcde",
+				Endpoint:    &localv1.Endpoint{IPs: localv1.NewIPSet(fmt.Sprintf("10.2.0.%d", i+1))},
+				Topology:    &globalv1.TopologyInfo{Node: node},
+				Conditions:  &globalv1.EndpointConditions{Ready: true},
+
+				LastChangeTriggerTime: triggerTime,
+			})
+		}
+
+		store.Update(func(tx *proxystore.Tx) {
+			tx.SetEndpointsOfSource("default", "svc-a-abcde", infos)
+			tx.SetServiceTriggerTime("default", "svc-a", triggerTime)
+		})
+	}
+
+	setEndpoints(1, "node-b", "node-c")
+	runWatch(t, store, views, digest)
+
+	setEndpoints(2, "node-b")
+
+	ops := runWatch(t, store, views, digest)
+	if len(ops) != 2 || ops[0] != "set ServicesSet default/svc-a" || !strings.HasPrefix(ops[1], "delete EndpointsSet default/svc-a/") {
+		t.Errorf("expected the service to be sent again with the deletion, got %q", ops)
+	}
+}
+
+// BenchmarkLocalState measures the local state computation for 1000 watchers, at each revision.
+func BenchmarkLocalState(b *testing.B) {
+	const watchers = 1000
+
+	for _, withCache := range []bool{false, true} {
+		b.Run(fmt.Sprint("cache=", withCache), func(b *testing.B) {
+			store := zonedStore(watchers, 100)
+
+			var cache *Cache
+			if withCache {
+				cache = &Cache{}
+			}
+
+			runs := make([]*jobRun, watchers)
+			states := make([]*watchstate.WatchState, watchers)
+			for i := range runs {
+				runs[i] = &jobRun{nodeName: fmt.Sprint("node-", i), selector: endpoints.Default{}, cache: cache}
+				states[i] = watchstate.New(discardSink{}, sets)
+			}
+
+			hits := testutil.ToFloat64(metrics.Kpng_local_state_cache_hits)
+			misses := testutil.ToFloat64(metrics.Kpng_local_state_cache_misses)
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				setService(store, fmt.Sprint("svc-new-", i))
+
+				for n, run := range runs {
+					store.View(0, func(tx *proxystore.Tx) {
+						run.Update(tx, states[n])
+					})
+					states[n].Reset(lightdiffstore.ItemDeleted)
+				}
+			}
+
+			b.StopTimer()
+
+			hits = testutil.ToFloat64(metrics.Kpng_local_state_cache_hits) - hits
+			misses = testutil.ToFloat64(metrics.Kpng_local_state_cache_misses) - misses
+			if hits+misses != 0 {
+				b.ReportMetric(hits/(hits+misses), "hit-ratio")
+			}
+		})
+	}
+}
+
+// filterSink requests the given filters in order, then waits for its context to be canceled.
+type filterSink struct {
+	testSink
+	filters []*localv1.WatchFilter
+	filter  *localv1.WatchFilter
+}
+
+func (s *filterSink) WaitRequest() (string, error) {
+	if len(s.filters) == 0 {
+		<-s.ctx.Done()
+		return "", s.ctx.Err()
+	}
+
+	s.filter, s.filters = s.filters[0], s.filters[1:]
+	return "node-a", nil
+}
+
+func (s *filterSink) WatchFilter() *localv1.WatchFilter {
+	return s.filter
+}
+
+func TestFilter(t *testing.T) {
+	store := proxystore.New()
+
+	store.Update(func(tx *proxystore.Tx) {
+		tx.SetService(&localv1.Service{Namespace: "default", Name: "svc-a", Type: "ClusterIP", Labels: map[string]string{"app": "a"}})
+		tx.SetService(&localv1.Service{Namespace: "default", Name: "svc-lb", Type: "LoadBalancer", Labels: map[string]string{"app": "lb"}})
+		tx.SetService(&localv1.Service{Namespace: "other", Name: "svc-b", Type: "ClusterIP", Labels: map[string]string{"app": "b"}})
+
+		for _, set := range proxystore.AllSets {
+			tx.SetSync(set)
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sink := &filterSink{
+		testSink: testSink{ctx: ctx, ops: make(chan *localv1.OpItem, 100)},
+		filters: []*localv1.WatchFilter{
+			{ServiceTypes: []string{"LoadBalancer"}},
+			{LabelSelector: "app in (a,b)"},
+			{Namespaces: []string{"other"}},
+			nil,
+		},
+	}
+
+	go (&Job{Store: store, Sink: sink}).Run(ctx)
+
+	// ops until the next sync
+	next := func() (ops []string) {
+		for {
+			select {
+			case op := <-sink.ops:
+				switch v := op.Op.(type) {
+				case *localv1.OpItem_Reset_:
+					ops = append(ops, "reset")
+				case *localv1.OpItem_Set:
+					ops = append(ops, fmt.Sprint("set ", v.Set.Ref.Path))
+				case *localv1.OpItem_Delete:
+					ops = append(ops, fmt.Sprint("delete ", v.Delete.Path))
+				case *localv1.OpItem_Sync:
+					return
+				}
+
+			case <-time.After(time.Second):
+				t.Fatal("no sync received")
+			}
+		}
+	}
+
+	for _, expected := range [][]string{
+		{"reset", "set default/svc-lb"},
+		{"set default/svc-a", "set other/svc-b", "delete default/svc-lb"},
+		{"delete default/svc-a"},
+		{"set default/svc-a", "set default/svc-lb"},
+	} {
+		if ops := next(); fmt.Sprint(ops) != fmt.Sprint(expected) {
+			t.Errorf("expected ops %q, got %q", expected, ops)
+		}
+	}
+}
+
+func TestFilterInvalid(t *testing.T) {
+	store := proxystore.New()
+	setService(store, "svc-a")
+
+	sink := &filterSink{
+		testSink: testSink{ctx: context.Background()},
+		filters:  []*localv1.WatchFilter{{LabelSelector: "app in (a"}},
+	}
+
+	if err := (&Job{Store: store, Sink: sink}).Run(context.Background()); err == nil {
+		t.Error("expected an error")
+	}
+}
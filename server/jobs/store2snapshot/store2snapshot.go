@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package store2snapshot
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+type Config struct {
+	FilePath string
+	Interval time.Duration
+}
+
+func (c *Config) BindFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&c.FilePath, "snapshot-file", "", "file where the store is periodically saved, and loaded from at startup for a warm start (disabled if empty)")
+	flags.DurationVar(&c.Interval, "snapshot-interval", 30*time.Second, "minimum interval between store snapshots")
+}
+
+// Load loads the snapshot in the store, if enabled and available.
+func (c *Config) Load(store *proxystore.Store) {
+	if c.FilePath == "" {
+		return
+	}
+
+	in, err := os.Open(c.FilePath)
+	if os.IsNotExist(err) {
+		klog.Info("no store snapshot to load from ", c.FilePath)
+		return
+	} else if err != nil {
+		klog.Warning("failed to open the store snapshot: ", err)
+		return
+	}
+
+	defer in.Close()
+
+	if _, err := store.LoadSnapshot(in); err != nil {
+		klog.Warning("failed to load the store snapshot, starting from an empty state: ", err)
+	}
+}
+
+// Job writes a snapshot of the store when it changed, at most once per interval.
+type Job struct {
+	Store  *proxystore.Store
+	Config *Config
+}
+
+func (j *Job) Run(ctx context.Context) {
+	if j.Config.FilePath == "" {
+		return
+	}
+
+	var (
+		rev    uint64
+		closed bool
+	)
+
+	for {
+		buf := new(bytes.Buffer)
+		ok := false
+
+		rev, closed = j.Store.View(rev, func(tx *proxystore.Tx) {
+			// only save complete states
+			if !tx.AllSynced() || tx.Provisional() {
+				return
+			}
+
+			if err := tx.WriteSnapshot(buf); err != nil {
+				klog.Error("failed to snapshot the store: ", err)
+				return
+			}
+
+			ok = true
+		})
+
+		if closed {
+			return
+		}
+
+		if ok {
+			if err := j.write(buf.Bytes()); err != nil {
+				klog.Error("failed to write the store snapshot: ", err)
+			} else {
+				klog.V(1).Info("wrote store snapshot at rev ", rev)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(j.Config.Interval):
+		}
+	}
+}
+
+// write writes the snapshot atomically, so a crash can't leave a partial snapshot.
+func (j *Job) write(ba []byte) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(j.Config.FilePath), "."+filepath.Base(j.Config.FilePath)+".*")
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(ba); err != nil {
+		tmp.Close()
+		return
+	}
+
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return
+	}
+
+	if err = tmp.Close(); err != nil {
+		return
+	}
+
+	return os.Rename(tmp.Name(), j.Config.FilePath)
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package apiwatch
 
 import (
+	"strings"
+
 	"github.com/spf13/pflag"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -25,12 +27,15 @@ import (
 )
 
 type Watch struct {
+	// Server is the API to reach. It can be a comma-separated list of servers to fail over.
 	Server   string
 	TLSFlags *tlsflags.Flags
+
+	current int
 }
 
 func (w *Watch) BindFlags(flags *pflag.FlagSet) {
-	flags.StringVar(&w.Server, "api", "127.0.0.1:12090", "Remote API server to query")
+	flags.StringVar(&w.Server, "api", "127.0.0.1:12090", "Remote API server to query (several comma-separated servers to fail over between replicas)")
 	w.TLSFlags.Bind(flags, "api-client-")
 }
 
@@ -44,10 +49,26 @@ func (w *Watch) Dial() (conn *grpc.ClientConn, err error) {
 		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(cfg)))
 	}
 
-	conn, err = grpc.Dial(w.Server, opts...)
+	conn, err = grpc.Dial(w.target(), opts...)
 	if err != nil {
 		return
 	}
 
 	return
 }
+
+func (w *Watch) targets() []string {
+	return strings.Split(w.Server, ",")
+}
+
+func (w *Watch) target() string {
+	targets := w.targets()
+	return strings.TrimSpace(targets[w.current%len(targets)])
+}
+
+// Failover makes the next Dial use the next server. It returns false when every server has been
+// tried, so the caller should wait before trying again.
+func (w *Watch) Failover() bool {
+	w.current = (w.current + 1) % len(w.targets())
+	return w.current != 0
+}
@@ -24,61 +24,119 @@ import (
 	"sigs.k8s.io/kpng/server/proxystore"
 )
 
-const hostnameLabel = "kubernetes.io/hostname"
+const (
+	hostnameLabel = "kubernetes.io/hostname"
+	regionLabel   = "topology.kubernetes.io/region"
+)
 
+// ForNode returns the endpoints of the service for the node, selected by the Default selector.
 func ForNode(tx *proxystore.Tx, si *globalv1.ServiceInfo, nodeName string) (endpoints []*globalv1.EndpointInfo) {
-	node := tx.GetNode(nodeName)
-
-	if node == nil {
-		// node is unknown, simulate a basic node
-		node = &globalv1.Node{
-			Name: nodeName,
-			Topology: &globalv1.TopologyInfo{
-				Node: nodeName,
-			},
-		}
-	}
+	return ForNodeWith(Default{}, tx, si, nodeName)
+}
+
+// ForNodeWith returns the endpoints of the service for the node, selected by the given selector.
+// The node is named as in the store (see proxystore.NodeName).
+func ForNodeWith(selector Selector, tx *proxystore.Tx, si *globalv1.ServiceInfo, nodeName string) (endpoints []*globalv1.EndpointInfo) {
+	node := GetNode(tx, nodeName)
 
 	svc := si.Service
 
 	infos := make([]*globalv1.EndpointInfo, 0)
 	tx.EachEndpointOfService(svc.Namespace, svc.Name, func(info *globalv1.EndpointInfo) {
-		info = proto.Clone(info).(*globalv1.EndpointInfo)
+		// selectors are free to modify the endpoints
+		infos = append(infos, proto.Clone(info).(*globalv1.EndpointInfo))
+	})
+
+	return selector.Select(&Context{Tx: tx, Service: si, Node: node}, infos)
+}
+
+// GetNode returns the node from the store, or a basic node if it's unknown (in the cluster
+// qualifying its name, if any).
+func GetNode(tx *proxystore.Tx, nodeName string) *globalv1.Node {
+	if node := tx.GetNode(nodeName); node != nil {
+		return node
+	}
+
+	// node is unknown, simulate a basic node
+	cluster, name := proxystore.SplitNodeName(nodeName)
+	return &globalv1.Node{
+		Name: name,
+		Topology: &globalv1.TopologyInfo{
+			Node:    name,
+			Cluster: cluster,
+		},
+	}
+}
+
+// Default is the default endpoint selection: ready endpoints (or serving and terminating ones
+// if there's none), filtered by topology hints, and scoped according to the traffic policies.
+// With several clusters, only the endpoints of the node's cluster and of the clusters exporting
+// the service are available.
+type Default struct {
+	// PreferSameRegion selects the endpoints in the node's region, if any, when topology
+	// hints are not used.
+	PreferSameRegion bool
+	// PreferSameCluster selects the endpoints in the node's cluster, if any.
+	PreferSameCluster bool
+}
+
+var _ Selector = Default{}
+
+func (d Default) Select(ctx *Context, infos []*globalv1.EndpointInfo) (endpoints []*globalv1.EndpointInfo) {
+	node := ctx.Node
 
-		info.Endpoint.Local = info.Topology.Node == nodeName
+	// a node of no known cluster sees the endpoints of every cluster
+	cluster := node.GetTopology().GetCluster()
 
-		if info.Conditions != nil && !info.Conditions.Ready {
-			return
+	candidates := make([]*globalv1.EndpointInfo, 0, len(infos))
+	for _, info := range infos {
+		if !info.Conditions.IsReady() && !info.Conditions.IsServingTerminating() {
+			continue
 		}
 
-		if hints := info.Hints; hints != nil {
-			if len(hints.Zones) != 0 {
-				// filter by zone
-				isForNodeZone := false
-				for _, z := range hints.Zones {
-					if z == node.Topology.Zone {
-						isForNodeZone = true
-						break
-					}
-				}
-
-				if !isForNodeZone {
-					return
-				}
-			}
+		if cluster != "" && info.Topology.GetCluster() != cluster && !exportedBy(ctx.Service, info.Topology.GetCluster()) {
+			continue
 		}
 
-		infos = append(infos, info)
-	})
+		info.Endpoint.Local = OnNode(info, node)
+		info.Endpoint.Terminating = info.Conditions.GetTerminating()
+
+		candidates = append(candidates, info)
+	}
+	infos = candidates
+
+	// usable endpoints for each scope (cluster-wide or node-local)
+	clusterEndpoints := usableEndpoints(infos, false)
+	localEndpoints := usableEndpoints(infos, true)
+
+	// topology only applies to cluster-wide traffic
+	if d.PreferSameCluster {
+		clusterEndpoints = sameCluster(cluster, clusterEndpoints)
+	}
+
+	if hinted, ok := zoneHinted(clusterEndpoints, node); ok {
+		clusterEndpoints = hinted
+	} else if d.PreferSameRegion {
+		clusterEndpoints = sameRegion(ctx, clusterEndpoints)
+	}
+
+	inScope := func(info *globalv1.EndpointInfo, toLocal bool) bool {
+		if toLocal {
+			return localEndpoints[info]
+		}
+		return clusterEndpoints[info]
+	}
 
 	endpoints = make([]*globalv1.EndpointInfo, 0, len(infos))
 
 	// select endpoints for this service
 
+	svc := ctx.Service.Service
+
 	for _, info := range infos {
 		info.Endpoint.Scopes = &localv1.EndpointScopes{
-			Internal: info.Endpoint.Local || !si.Service.InternalTrafficToLocal,
-			External: info.Endpoint.Local || !si.Service.ExternalTrafficToLocal,
+			Internal: inScope(info, svc.InternalTrafficToLocal),
+			External: inScope(info, svc.ExternalTrafficToLocal),
 		}
 
 		if info.Endpoint.Scopes.Any() {
@@ -88,3 +146,134 @@ func ForNode(tx *proxystore.Tx, si *globalv1.ServiceInfo, nodeName string) (endp
 
 	return
 }
+
+// NodeKey implements NodeKeyer: without endpoints on the node, the selection only depends on its
+// cluster, its zone and, if PreferSameRegion is set, its region.
+func (d Default) NodeKey(node *globalv1.Node) (key string, ok bool) {
+	key = node.GetTopology().GetZone()
+	if d.PreferSameRegion {
+		key += "/" + node.GetLabels()[regionLabel]
+	}
+	if cluster := node.GetTopology().GetCluster(); cluster != "" {
+		key = cluster + "/" + key
+	}
+	return key, true
+}
+
+// OnNode returns true if the endpoint is on the node (in the same cluster).
+func OnNode(info *globalv1.EndpointInfo, node *globalv1.Node) bool {
+	name := node.GetTopology().GetNode()
+	if name == "" {
+		name = node.GetName()
+	}
+	return info.GetTopology().GetNode() == name && info.GetTopology().GetCluster() == node.GetTopology().GetCluster()
+}
+
+// exportedBy returns true if the cluster exports the service.
+func exportedBy(si *globalv1.ServiceInfo, cluster string) bool {
+	for _, c := range si.GetExportedBy() {
+		if c == cluster {
+			return true
+		}
+	}
+	return false
+}
+
+// sameCluster filters the endpoints in the cluster, or returns them all if none is.
+func sameCluster(cluster string, infos map[*globalv1.EndpointInfo]bool) map[*globalv1.EndpointInfo]bool {
+	filtered := make(map[*globalv1.EndpointInfo]bool, len(infos))
+	for info := range infos {
+		if info.Topology.GetCluster() == cluster {
+			filtered[info] = true
+		}
+	}
+
+	if len(filtered) == 0 {
+		return infos
+	}
+
+	return filtered
+}
+
+// zoneHinted filters the endpoints by the node's zone using the topology hints.
+// Like upstream kube-proxy, hints are ignored (ok is false) if the node has no zone,
+// if any endpoint has no zone hint, or if no endpoint is hinted for the node's zone.
+func zoneHinted(infos map[*globalv1.EndpointInfo]bool, node *globalv1.Node) (hinted map[*globalv1.EndpointInfo]bool, ok bool) {
+	zone := node.GetTopology().GetZone()
+	if zone == "" || len(infos) == 0 {
+		return
+	}
+
+	hinted = make(map[*globalv1.EndpointInfo]bool, len(infos))
+
+	for info := range infos {
+		zones := info.GetHints().GetZones()
+		if len(zones) == 0 {
+			return nil, false
+		}
+
+		for _, z := range zones {
+			if z == zone {
+				hinted[info] = true
+				break
+			}
+		}
+	}
+
+	if len(hinted) == 0 {
+		return nil, false
+	}
+
+	return hinted, true
+}
+
+// sameRegion filters the endpoints in the node's region, or returns them all if none is.
+func sameRegion(ctx *Context, infos map[*globalv1.EndpointInfo]bool) map[*globalv1.EndpointInfo]bool {
+	region := ctx.Node.Labels[regionLabel]
+	if region == "" {
+		return infos
+	}
+
+	filtered := make(map[*globalv1.EndpointInfo]bool, len(infos))
+	for info := range infos {
+		if ctx.NodeOf(info).GetLabels()[regionLabel] == region {
+			filtered[info] = true
+		}
+	}
+
+	if len(filtered) == 0 {
+		return infos
+	}
+
+	return filtered
+}
+
+// usableEndpoints selects the ready endpoints or, if there's none, falls back to
+// serving and terminating endpoints (like upstream kube-proxy does).
+func usableEndpoints(infos []*globalv1.EndpointInfo, localOnly bool) (usable map[*globalv1.EndpointInfo]bool) {
+	usable = make(map[*globalv1.EndpointInfo]bool, len(infos))
+
+	for _, info := range infos {
+		if localOnly && !info.Endpoint.Local {
+			continue
+		}
+		if info.Conditions.IsReady() {
+			usable[info] = true
+		}
+	}
+
+	if len(usable) != 0 {
+		return
+	}
+
+	for _, info := range infos {
+		if localOnly && !info.Endpoint.Local {
+			continue
+		}
+		if info.Conditions.IsServingTerminating() {
+			usable[info] = true
+		}
+	}
+
+	return
+}
@@ -18,6 +18,8 @@ package endpoints
 
 import (
 	"fmt"
+	"sort"
+	"testing"
 
 	"sigs.k8s.io/kpng/api/globalv1"
 	"sigs.k8s.io/kpng/api/localv1"
@@ -95,3 +97,242 @@ func ExampleForNodeWithTopology() {
 	//   - service test:
 	//     - ep V4:"10.2.1.1"
 }
+
+func ExampleForNode_withTerminating() {
+	store := proxystore.New()
+
+	store.Update(func(tx *proxystore.Tx) {
+		tx.SetService(&localv1.Service{
+			Namespace: "test",
+			Name:      "test",
+			Type:      "LoadBalancer",
+			IPs:       &localv1.ServiceIPs{ClusterIPs: localv1.NewIPSet("10.1.2.3")},
+			Ports: []*localv1.PortMapping{
+				{Port: 1234},
+			},
+
+			ExternalTrafficToLocal: true,
+		})
+
+		tx.SetEndpointsOfSource("test", "test-abcde", []*globalv1.EndpointInfo{
+			{
+				Namespace:   "test",
+				SourceName:  "test-abcde",
+				ServiceName: "test",
+				Endpoint:    &localv1.Endpoint{IPs: localv1.NewIPSet("10.2.0.1")},
+				Topology:    &globalv1.TopologyInfo{Node: "host-a"},
+				Conditions:  &globalv1.EndpointConditions{Serving: true, Terminating: true},
+			},
+			{
+				Namespace:   "test",
+				SourceName:  "test-abcde",
+				ServiceName: "test",
+				Endpoint:    &localv1.Endpoint{IPs: localv1.NewIPSet("10.2.0.2")},
+				Topology:    &globalv1.TopologyInfo{Node: "host-a"},
+				Conditions:  &globalv1.EndpointConditions{Terminating: true},
+			},
+			{
+				Namespace:   "test",
+				SourceName:  "test-abcde",
+				ServiceName: "test",
+				Endpoint:    &localv1.Endpoint{IPs: localv1.NewIPSet("10.2.1.1")},
+				Topology:    &globalv1.TopologyInfo{Node: "host-b"},
+				Conditions:  &globalv1.EndpointConditions{Ready: true, Serving: true},
+			},
+		})
+	})
+
+	store.View(0, func(tx *proxystore.Tx) {
+		for _, host := range []string{"host-a", "host-b"} {
+			fmt.Print("host ", host, ":\n")
+			tx.Each(proxystore.Services, func(kv *proxystore.KV) (cont bool) {
+				for _, epi := range ForNode(tx, kv.Service, host) {
+					ep := epi.Endpoint
+					fmt.Print("  - ep ", ep.IPs.First(), " terminating=", ep.Terminating,
+						" internal=", ep.Scopes.Internal, " external=", ep.Scopes.External, "\n")
+				}
+				return true
+			})
+		}
+	})
+
+	// Output:
+	// host host-a:
+	//   - ep 10.2.1.1 terminating=false internal=true external=false
+	//   - ep 10.2.0.1 terminating=true internal=false external=true
+	// host host-b:
+	//   - ep 10.2.1.1 terminating=false internal=true external=true
+}
+
+func TestForNodeTopology(t *testing.T) {
+	nodes := []*globalv1.Node{
+		{Name: "host-a", Topology: &globalv1.TopologyInfo{Zone: "z1"}, Labels: map[string]string{regionLabel: "r1"}},
+		{Name: "host-b", Topology: &globalv1.TopologyInfo{Zone: "z2"}, Labels: map[string]string{regionLabel: "r1"}},
+		{Name: "host-c", Topology: &globalv1.TopologyInfo{Zone: "z3"}, Labels: map[string]string{regionLabel: "r2"}},
+		{Name: "host-nozone", Topology: &globalv1.TopologyInfo{}, Labels: map[string]string{regionLabel: "r1"}},
+		{Name: "host-r3", Topology: &globalv1.TopologyInfo{Zone: "z4"}, Labels: map[string]string{regionLabel: "r3"}},
+	}
+
+	for _, test := range []struct {
+		name     string
+		hints    map[string][]string // endpoint node => zone hints
+		selector Selector
+		node     string
+		expected []string
+	}{
+		{"hinted", map[string][]string{"host-a": {"z1"}, "host-b": {"z2"}, "host-c": {"z3"}}, Default{}, "host-a", []string{"host-a"}},
+		{"missing hint", map[string][]string{"host-a": {"z1"}, "host-b": {"z2"}}, Default{}, "host-a", []string{"host-a", "host-b", "host-c"}},
+		{"no endpoint for zone", map[string][]string{"host-a": {"z2"}, "host-b": {"z2"}, "host-c": {"z3"}}, Default{}, "host-a", []string{"host-a", "host-b", "host-c"}},
+		{"node without zone", map[string][]string{"host-a": {"z1"}, "host-b": {"z2"}, "host-c": {"z3"}}, Default{}, "host-nozone", []string{"host-a", "host-b", "host-c"}},
+		{"no hints", nil, Default{}, "host-a", []string{"host-a", "host-b", "host-c"}},
+		{"same region", nil, Default{PreferSameRegion: true}, "host-a", []string{"host-a", "host-b"}},
+		{"same region r2", nil, Default{PreferSameRegion: true}, "host-c", []string{"host-c"}},
+		{"no endpoint in region", nil, Default{PreferSameRegion: true}, "host-r3", []string{"host-a", "host-b", "host-c"}},
+		{"hints before region", map[string][]string{"host-a": {"z1"}, "host-b": {"z2"}, "host-c": {"z3"}}, Default{PreferSameRegion: true}, "host-b", []string{"host-b"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			store := proxystore.New()
+
+			store.Update(func(tx *proxystore.Tx) {
+				for _, node := range nodes {
+					tx.SetNode(node)
+				}
+
+				tx.SetService(&localv1.Service{Namespace: "test", Name: "test", Type: "ClusterIP"})
+
+				infos := make([]*globalv1.EndpointInfo, 0)
+				for i, node := range []string{"host-a", "host-b", "host-c"} {
+					info := &globalv1.EndpointInfo{
+						Namespace:   "test",
+						SourceName:  "test-abcde",
+						ServiceName: "test",
+						PodName:     node,
+						Endpoint:    &localv1.Endpoint{IPs: localv1.NewIPSet(fmt.Sprintf("10.2.0.%d", i+1))},
+						Topology:    &globalv1.TopologyInfo{Node: node},
+						Conditions:  &globalv1.EndpointConditions{Ready: true},
+					}
+					if zones, ok := test.hints[node]; ok {
+						info.Hints = &globalv1.TopologyHints{Zones: zones}
+					}
+					infos = append(infos, info)
+				}
+
+				tx.SetEndpointsOfSource("test", "test-abcde", infos)
+			})
+
+			store.View(0, func(tx *proxystore.Tx) {
+				tx.Each(proxystore.Services, func(kv *proxystore.KV) bool {
+					got := make([]string, 0)
+					for _, info := range ForNodeWith(test.selector, tx, kv.Service, test.node) {
+						if info.Endpoint.Scopes.Internal {
+							got = append(got, info.Topology.Node)
+						}
+					}
+					sort.Strings(got)
+
+					if fmt.Sprint(got) != fmt.Sprint(test.expected) {
+						t.Errorf("expected endpoints on %v, got %v", test.expected, got)
+					}
+					return true
+				})
+			})
+		})
+	}
+}
+
+func TestForNodeClusters(t *testing.T) {
+	store := proxystore.New()
+
+	// the same node name in each cluster, qualified in the store
+	store.Update(func(tx *proxystore.Tx) {
+		for i, cluster := range []string{"east", "west"} {
+			tx.SetNode(&globalv1.Node{Name: proxystore.NodeName(cluster, "host"), Topology: &globalv1.TopologyInfo{Node: "host", Cluster: cluster}})
+
+			tx.SetEndpointsOfSource("test", cluster+"/test-abcde", []*globalv1.EndpointInfo{
+				{
+					Namespace:   "test",
+					SourceName:  cluster + "/test-abcde",
+					ServiceName: "test",
+					Endpoint:    &localv1.Endpoint{IPs: localv1.NewIPSet(fmt.Sprintf("10.2.%d.1", i))},
+					Topology:    &globalv1.TopologyInfo{Node: "host", Cluster: cluster},
+					Conditions:  &globalv1.EndpointConditions{Ready: true},
+				},
+			})
+		}
+	})
+
+	for _, test := range []struct {
+		name       string
+		exportedBy []string
+		selector   Selector
+		node       string
+		expected   []string
+	}{
+		{"not exported", nil, Default{}, "east/host", []string{"10.2.0.1"}},
+		{"not exported, other cluster", nil, Default{}, "west/host", []string{"10.2.1.1"}},
+		{"exported by west", []string{"west"}, Default{}, "east/host", []string{"10.2.0.1", "10.2.1.1"}},
+		{"exported by west, from west", []string{"west"}, Default{}, "west/host", []string{"10.2.1.1"}},
+		{"exported by all", []string{"east", "west"}, Default{}, "west/host", []string{"10.2.0.1", "10.2.1.1"}},
+		{"prefer same cluster", []string{"east", "west"}, Default{PreferSameCluster: true}, "west/host", []string{"10.2.1.1"}},
+		{"unknown node", []string{"west"}, Default{}, "north/host", []string{"10.2.1.1"}},
+		{"unknown node of no cluster", nil, Default{}, "host", []string{"10.2.0.1", "10.2.1.1"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			si := &globalv1.ServiceInfo{
+				Service: &localv1.Service{
+					Namespace: "test",
+					Name:      "test",
+					Type:      "ClusterIP",
+				},
+				ExportedBy: test.exportedBy,
+			}
+
+			ips := []string{}
+			store.View(0, func(tx *proxystore.Tx) {
+				for _, ei := range ForNodeWith(test.selector, tx, si, test.node) {
+					ips = append(ips, ei.Endpoint.IPs.First())
+				}
+			})
+			sort.Strings(ips)
+
+			if fmt.Sprint(ips) != fmt.Sprint(test.expected) {
+				t.Errorf("expected %v, got %v", test.expected, ips)
+			}
+		})
+	}
+}
+
+func TestForNodeSingleCluster(t *testing.T) {
+	store := proxystore.New()
+
+	// with a single cluster, the node keeps its bare name
+	store.Update(func(tx *proxystore.Tx) {
+		tx.SetNode(&globalv1.Node{Name: "host-a", Topology: &globalv1.TopologyInfo{Node: "host-a", Cluster: "main"}})
+
+		tx.SetEndpointsOfSource("test", "test-abcde", []*globalv1.EndpointInfo{
+			{
+				Namespace:   "test",
+				SourceName:  "test-abcde",
+				ServiceName: "test",
+				Endpoint:    &localv1.Endpoint{IPs: localv1.NewIPSet("10.2.0.1")},
+				Topology:    &globalv1.TopologyInfo{Node: "host-a", Cluster: "main"},
+				Conditions:  &globalv1.EndpointConditions{Ready: true},
+			},
+		})
+	})
+
+	si := &globalv1.ServiceInfo{
+		Service: &localv1.Service{Namespace: "test", Name: "test", Type: "ClusterIP"},
+	}
+
+	store.View(0, func(tx *proxystore.Tx) {
+		if node := tx.GetNodeOf(&globalv1.TopologyInfo{Node: "host-a", Cluster: "main"}); node == nil || node.Name != "host-a" {
+			t.Errorf("expected the node of the topology, got %v", node)
+		}
+
+		eps := ForNode(tx, si, "host-a")
+		if len(eps) != 1 || !eps[0].Endpoint.Local {
+			t.Errorf("expected the local endpoint, got %v", eps)
+		}
+	})
+}
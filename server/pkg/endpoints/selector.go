@@ -0,0 +1,283 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"sigs.k8s.io/kpng/api/globalv1"
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+// Selector selects the endpoints of a service for a node.
+type Selector interface {
+	// Select returns the endpoints to use among the given ones. The endpoints are owned by
+	// the selection and can be modified (ie to change their scopes).
+	Select(ctx *Context, infos []*globalv1.EndpointInfo) []*globalv1.EndpointInfo
+}
+
+// NodeKeyer is implemented by selectors able to tell which nodes get the same selection: for
+// a service without endpoint on the node, nodes with the same key get the same endpoints. This
+// allows the selection to be shared between nodes (ok is false if it can't be).
+type NodeKeyer interface {
+	NodeKey(node *globalv1.Node) (key string, ok bool)
+}
+
+// NodeKey returns the selector's key for the node (see NodeKeyer). The node may be nil if unknown.
+func NodeKey(selector Selector, node *globalv1.Node) (key string, ok bool) {
+	keyer, ok := selector.(NodeKeyer)
+	if !ok {
+		return "", false
+	}
+	return keyer.NodeKey(node)
+}
+
+// Context is the context of a selection.
+type Context struct {
+	Tx      *proxystore.Tx
+	Service *globalv1.ServiceInfo
+	// Node is the node the endpoints are selected for
+	Node *globalv1.Node
+
+	nodes map[string]*globalv1.Node
+}
+
+// NodeOf returns the node of the endpoint, or nil if it's not known.
+func (ctx *Context) NodeOf(info *globalv1.EndpointInfo) *globalv1.Node {
+	if info.GetTopology().GetNode() == "" {
+		return nil
+	}
+
+	// cache key, distinct in each cluster
+	name := proxystore.NodeName(info.Topology.Cluster, info.Topology.Node)
+
+	if ctx.nodes == nil {
+		ctx.nodes = map[string]*globalv1.Node{}
+	}
+
+	node, ok := ctx.nodes[name]
+	if !ok {
+		node = ctx.Tx.GetNodeOf(info.Topology)
+		ctx.nodes[name] = node
+	}
+
+	return node
+}
+
+// Chain applies selectors in order, each one selecting among the previous one's endpoints.
+type Chain []Selector
+
+var _ Selector = Chain{}
+
+func (c Chain) Select(ctx *Context, infos []*globalv1.EndpointInfo) []*globalv1.EndpointInfo {
+	for _, selector := range c {
+		infos = selector.Select(ctx, infos)
+	}
+	return infos
+}
+
+var _ NodeKeyer = Chain{}
+
+func (c Chain) NodeKey(node *globalv1.Node) (key string, ok bool) {
+	keys := make([]string, 0, len(c))
+	for _, selector := range c {
+		k, ok := NodeKey(selector, node)
+		if !ok {
+			return "", false
+		}
+		keys = append(keys, k)
+	}
+	return strings.Join(keys, "\x00"), true
+}
+
+// SelectorFactory builds a selector from its argument (the part after ':' in the selector spec).
+type SelectorFactory func(arg string) (Selector, error)
+
+var selectorFactories = map[string]SelectorFactory{}
+
+// RegisterSelector registers a selector, so it can be used from the command line.
+func RegisterSelector(name string, factory SelectorFactory) {
+	selectorFactories[name] = factory
+}
+
+// ParseSelector builds a selector from a spec in the form "name" or "name:arg".
+func ParseSelector(spec string) (Selector, error) {
+	name, arg, _ := strings.Cut(spec, ":")
+
+	factory, ok := selectorFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown endpoint selector %q (known: %s)", name, strings.Join(SelectorNames(), ", "))
+	}
+
+	selector, err := factory(arg)
+	if err != nil {
+		return nil, fmt.Errorf("endpoint selector %q: %w", spec, err)
+	}
+
+	return selector, nil
+}
+
+// SelectorNames returns the names of the registered selectors.
+func SelectorNames() (names []string) {
+	names = make([]string, 0, len(selectorFactories))
+	for name := range selectorFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// Config configures the endpoint selection from the command line.
+type Config struct {
+	PreferSameRegion  bool
+	PreferSameCluster bool
+	Selectors         []string
+}
+
+func (c *Config) BindFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&c.PreferSameRegion, "prefer-same-region", false, "prefer endpoints in the node's region ("+regionLabel+") when topology hints are not used")
+	flags.BoolVar(&c.PreferSameCluster, "prefer-same-cluster", false, "prefer endpoints in the node's cluster, when the store has several clusters")
+	flags.StringSliceVar(&c.Selectors, "endpoint-selectors", nil, "endpoint selectors applied in order after the default selection (name or name:arg; known: "+strings.Join(SelectorNames(), ", ")+")")
+}
+
+// Selector returns the default selector, chained with the configured ones.
+func (c *Config) Selector() (Selector, error) {
+	chain := Chain{Default{PreferSameRegion: c.PreferSameRegion, PreferSameCluster: c.PreferSameCluster}}
+
+	for _, spec := range c.Selectors {
+		selector, err := ParseSelector(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		chain = append(chain, selector)
+	}
+
+	if len(chain) == 1 {
+		return chain[0], nil
+	}
+
+	return chain, nil
+}
+
+func init() {
+	RegisterSelector("prefer-label", func(label string) (Selector, error) {
+		if label == "" {
+			return nil, fmt.Errorf("a node label is required")
+		}
+		return PreferLabel{Label: label}, nil
+	})
+
+	RegisterSelector("exclude-unschedulable", func(arg string) (Selector, error) {
+		if arg != "" {
+			return nil, fmt.Errorf("no argument expected")
+		}
+		return ExcludeUnschedulable{}, nil
+	})
+}
+
+// PreferLabel selects, for cluster-wide traffic, the endpoints on nodes having the same
+// value for the label as the node (ie a rack label), if any.
+type PreferLabel struct {
+	Label string
+}
+
+func (p PreferLabel) Select(ctx *Context, infos []*globalv1.EndpointInfo) []*globalv1.EndpointInfo {
+	value, ok := ctx.Node.GetLabels()[p.Label]
+	if !ok {
+		return infos
+	}
+
+	return preferInClusterScopes(ctx, infos, func(info *globalv1.EndpointInfo) bool {
+		v, ok := ctx.NodeOf(info).GetLabels()[p.Label]
+		return ok && v == value
+	})
+}
+
+func (p PreferLabel) NodeKey(node *globalv1.Node) (key string, ok bool) {
+	value, ok := node.GetLabels()[p.Label]
+	if !ok {
+		return "", true
+	}
+	return "=" + value, true
+}
+
+// ExcludeUnschedulable excludes, for cluster-wide traffic, the endpoints on unschedulable
+// (cordoned) nodes, unless there's no other endpoint.
+type ExcludeUnschedulable struct{}
+
+func (ExcludeUnschedulable) Select(ctx *Context, infos []*globalv1.EndpointInfo) []*globalv1.EndpointInfo {
+	return preferInClusterScopes(ctx, infos, func(info *globalv1.EndpointInfo) bool {
+		return !ctx.NodeOf(info).GetUnschedulable()
+	})
+}
+
+func (ExcludeUnschedulable) NodeKey(node *globalv1.Node) (key string, ok bool) {
+	return "", true
+}
+
+// preferInClusterScopes removes the cluster-wide scopes of the endpoints not matching, unless
+// no endpoint in the scope matches. Endpoints left without scope are removed.
+func preferInClusterScopes(ctx *Context, infos []*globalv1.EndpointInfo, match func(*globalv1.EndpointInfo) bool) []*globalv1.EndpointInfo {
+	svc := ctx.Service.Service
+
+	matches := make(map[*globalv1.EndpointInfo]bool, len(infos))
+	for _, info := range infos {
+		matches[info] = match(info)
+	}
+
+	narrow := func(toLocal bool, scope func(*localv1.EndpointScopes) *bool) {
+		if toLocal {
+			return
+		}
+
+		found := false
+		for _, info := range infos {
+			if *scope(info.Endpoint.Scopes) && matches[info] {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return
+		}
+
+		for _, info := range infos {
+			if !matches[info] {
+				*scope(info.Endpoint.Scopes) = false
+			}
+		}
+	}
+
+	narrow(svc.InternalTrafficToLocal, func(s *localv1.EndpointScopes) *bool { return &s.Internal })
+	narrow(svc.ExternalTrafficToLocal, func(s *localv1.EndpointScopes) *bool { return &s.External })
+
+	selected := infos[:0]
+	for _, info := range infos {
+		if info.Endpoint.Scopes.Any() {
+			selected = append(selected, info)
+		}
+	}
+
+	return selected
+}
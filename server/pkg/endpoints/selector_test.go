@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"sigs.k8s.io/kpng/api/globalv1"
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+// selectorFixture returns a store with 4 nodes in 2 racks (host-b being cordoned), and
+// 2 services with endpoints on host-a, host-b and host-c.
+func selectorFixture() *proxystore.Store {
+	store := proxystore.New()
+
+	store.Update(func(tx *proxystore.Tx) {
+		for _, node := range []*globalv1.Node{
+			{Name: "host-a", Labels: map[string]string{"rack": "r1"}},
+			{Name: "host-b", Labels: map[string]string{"rack": "r1"}, Unschedulable: true},
+			{Name: "host-c", Labels: map[string]string{"rack": "r2"}},
+			{Name: "host-d", Labels: map[string]string{"rack": "r2"}},
+		} {
+			node.Topology = &globalv1.TopologyInfo{Node: node.Name}
+			tx.SetNode(node)
+		}
+
+		for _, svc := range []*localv1.Service{
+			{Namespace: "test", Name: "cluster", Type: "ClusterIP"},
+			{Namespace: "test", Name: "local", Type: "LoadBalancer", ExternalTrafficToLocal: true},
+		} {
+			tx.SetService(svc)
+
+			infos := make([]*globalv1.EndpointInfo, 0)
+			for i, node := range []string{"host-a", "host-b", "host-c"} {
+				infos = append(infos, &globalv1.EndpointInfo{
+					Namespace:   svc.Namespace,
+					SourceName:  svc.Name + "-abcde",
+					ServiceName: svc.Name,
+					PodName:     svc.Name + "-" + node,
+					Endpoint:    &localv1.Endpoint{IPs: localv1.NewIPSet(fmt.Sprintf("10.2.0.%d", i+1))},
+					Topology:    &globalv1.TopologyInfo{Node: node},
+					Conditions:  &globalv1.EndpointConditions{Ready: true},
+				})
+			}
+			tx.SetEndpointsOfSource(svc.Namespace, svc.Name+"-abcde", infos)
+		}
+	})
+
+	return store
+}
+
+func TestSelectors(t *testing.T) {
+	store := selectorFixture()
+
+	for _, test := range []struct {
+		selectors []string
+		service   string
+		node      string
+		expected  string
+	}{
+		{nil, "cluster", "host-a", "internal=[host-a host-b host-c] external=[host-a host-b host-c]"},
+		{[]string{"prefer-label:rack"}, "cluster", "host-a", "internal=[host-a host-b] external=[host-a host-b]"},
+		{[]string{"prefer-label:rack"}, "cluster", "host-d", "internal=[host-c] external=[host-c]"},
+		{[]string{"prefer-label:zone"}, "cluster", "host-a", "internal=[host-a host-b host-c] external=[host-a host-b host-c]"},
+		{[]string{"exclude-unschedulable"}, "cluster", "host-c", "internal=[host-a host-c] external=[host-a host-c]"},
+		{[]string{"prefer-label:rack", "exclude-unschedulable"}, "cluster", "host-a", "internal=[host-a] external=[host-a]"},
+		{[]string{"exclude-unschedulable", "prefer-label:rack"}, "cluster", "host-d", "internal=[host-c] external=[host-c]"},
+		// node-local traffic is not changed
+		{[]string{"exclude-unschedulable"}, "local", "host-b", "internal=[host-a host-c] external=[host-b]"},
+		{[]string{"prefer-label:rack"}, "local", "host-c", "internal=[host-c] external=[host-c]"},
+		{[]string{"prefer-label:rack"}, "local", "host-d", "internal=[host-c] external=[]"},
+	} {
+		name := fmt.Sprint(test.selectors, " ", test.service, " on ", test.node)
+
+		t.Run(name, func(t *testing.T) {
+			cfg := &Config{Selectors: test.selectors}
+			selector, err := cfg.Selector()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			store.View(0, func(tx *proxystore.Tx) {
+				tx.Each(proxystore.Services, func(kv *proxystore.KV) bool {
+					if kv.Name != test.service {
+						return true
+					}
+
+					internal, external := []string{}, []string{}
+					for _, info := range ForNodeWith(selector, tx, kv.Service, test.node) {
+						if info.Endpoint.Scopes.Internal {
+							internal = append(internal, info.Topology.Node)
+						}
+						if info.Endpoint.Scopes.External {
+							external = append(external, info.Topology.Node)
+						}
+					}
+					sort.Strings(internal)
+					sort.Strings(external)
+
+					if got := fmt.Sprint("internal=", internal, " external=", external); got != test.expected {
+						t.Errorf("expected %s, got %s", test.expected, got)
+					}
+					return false
+				})
+			})
+		})
+	}
+}
+
+func TestSelectorErrors(t *testing.T) {
+	for _, spec := range []string{"unknown", "prefer-label", "exclude-unschedulable:x"} {
+		if _, err := (&Config{Selectors: []string{spec}}).Selector(); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+}
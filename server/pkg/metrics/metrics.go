@@ -29,6 +29,62 @@ var Kpng_node_local_events = prometheus.NewCounter(prometheus.CounterOpts{
 	Help: "The total number of received events from the Kubernetes API for a given node",
 })
 
+var Kpng_local_state_cache_hits = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "kpng_local_state_cache_hits_total",
+	Help: "The total number of service endpoints selections shared between watchers",
+})
+
+var Kpng_local_state_cache_misses = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "kpng_local_state_cache_misses_total",
+	Help: "The total number of service endpoints selections computed for watchers that could share them",
+})
+
+var Kpng_store_revision = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "kpng_store_revision",
+	Help: "The current revision of the store",
+})
+
+var Kpng_node_applied_revision = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "kpng_node_applied_revision",
+	Help: "The last store revision applied by the node",
+}, []string{"node"})
+
+var Kpng_node_apply_duration_seconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "kpng_node_apply_duration_seconds",
+	Help: "The duration of the node's last apply",
+}, []string{"node"})
+
+var Kpng_node_apply_failed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "kpng_node_apply_failed",
+	Help: "1 if the node's last apply failed, 0 otherwise",
+}, []string{"node"})
+
+var Kpng_node_apply_failures = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "kpng_node_apply_failures_total",
+	Help: "The total number of failed applies reported by the node",
+}, []string{"node"})
+
+var Kpng_local_watchers = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "kpng_local_watchers",
+	Help: "The number of connected local API watchers",
+})
+
+var Kpng_local_send_duration_seconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Name:    "kpng_local_send_duration_seconds",
+	Help:    "The duration of the sends to local API watchers",
+	Buckets: prometheus.ExponentialBuckets(0.0001, 4, 10),
+})
+
+var Kpng_local_stalled_watchers = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "kpng_local_stalled_watchers_total",
+	Help: "The total number of local API watchers disconnected because a send exceeded its deadline",
+})
+
+var Kpng_local_coalesced_revisions = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "kpng_local_coalesced_revisions_total",
+	Help: "The total number of store revisions merged into the next one before being sent to local API watchers",
+})
+
 // StartMetricsServer runs the prometheus listener so that KPNG metrics can be collected
 // TODO add TLS Auth if configured
 func StartMetricsServer(bindAddress string,
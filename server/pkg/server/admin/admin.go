@@ -0,0 +1,296 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package admin serves HTTP endpoints to inspect the state of a running server.
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"strconv"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	pkgendpoints "sigs.k8s.io/kpng/server/pkg/endpoints"
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+// Handler serves the introspection endpoints of a store.
+type Handler struct {
+	Store *proxystore.Store
+
+	// Selector selects the endpoints of the nodes (nil for the default selection)
+	Selector pkgendpoints.Selector
+}
+
+// Register registers the introspection endpoints in the mux:
+//   - /debug/store: the store revision and the sync status of each set;
+//   - /debug/services: the services, filtered by namespace, name and type;
+//   - /debug/endpoints: the endpoints, filtered by namespace, service, node and ready;
+//   - /debug/fornode: the endpoints selected for the given node, filtered by namespace and service.
+//
+// The namespace, name, service and node filters are glob patterns (ie: ?namespace=kube-*).
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/debug/store", h.serveStore)
+	mux.HandleFunc("/debug/services", h.serveServices)
+	mux.HandleFunc("/debug/endpoints", h.serveEndpoints)
+	mux.HandleFunc("/debug/fornode", h.serveForNode)
+}
+
+// view calls fn with the current state of the store, if any, and returns its revision.
+func (h *Handler) view(fn func(tx *proxystore.Tx)) uint64 {
+	rev := h.Store.Rev()
+	if rev == 0 {
+		return 0 // nothing stored yet (and View would wait for it)
+	}
+
+	rev, _ = h.Store.View(rev-1, fn)
+	return rev
+}
+
+func (h *Handler) serveStore(w http.ResponseWriter, r *http.Request) {
+	type setStatus struct {
+		Set     string `json:"set"`
+		Synced  bool   `json:"synced"`
+		Entries int    `json:"entries"`
+	}
+
+	sets := make([]setStatus, len(proxystore.AllSets))
+	for i, set := range proxystore.AllSets {
+		sets[i].Set = set.String()
+	}
+
+	provisional := false
+
+	rev := h.view(func(tx *proxystore.Tx) {
+		provisional = tx.Provisional()
+
+		for i, set := range proxystore.AllSets {
+			sets[i].Synced = tx.IsSynced(set)
+
+			tx.Each(set, func(kv *proxystore.KV) bool {
+				if set == proxystore.Endpoints && kv.Name == "" {
+					return true // also indexed by source
+				}
+				sets[i].Entries++
+				return true
+			})
+		}
+	})
+
+	writeJSON(w, struct {
+		Rev         uint64      `json:"rev"`
+		Provisional bool        `json:"provisional"`
+		Sets        []setStatus `json:"sets"`
+	}{rev, provisional, sets})
+}
+
+func (h *Handler) serveServices(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	filter, err := newFilter(q.Get("namespace"), q.Get("name"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	svcType := q.Get("type")
+
+	services := make([]json.RawMessage, 0)
+
+	rev := h.view(func(tx *proxystore.Tx) {
+		tx.Each(proxystore.Services, func(kv *proxystore.KV) bool {
+			svc := kv.Service.Service
+
+			if filter.match(svc.Namespace, svc.Name) && (svcType == "" || svc.Type == svcType) {
+				services = append(services, protoJSON(kv.Service))
+			}
+			return true
+		})
+	})
+
+	writeJSON(w, struct {
+		Rev      uint64            `json:"rev"`
+		Services []json.RawMessage `json:"services"`
+	}{rev, services})
+}
+
+func (h *Handler) serveEndpoints(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	filter, err := newFilter(q.Get("namespace"), q.Get("service"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	node := q.Get("node")
+	if _, err := path.Match(node, ""); err != nil {
+		http.Error(w, "invalid node pattern: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var ready *bool
+	if v := q.Get("ready"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "invalid ready filter: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		ready = &b
+	}
+
+	endpoints := make([]json.RawMessage, 0)
+
+	rev := h.view(func(tx *proxystore.Tx) {
+		tx.Each(proxystore.Endpoints, func(kv *proxystore.KV) bool {
+			if kv.Name == "" {
+				return true // also indexed by source
+			}
+
+			ei := kv.Endpoint
+
+			if !filter.match(ei.Namespace, ei.ServiceName) {
+				return true
+			}
+			if node != "" && !match(node, ei.GetTopology().GetNode()) && !match(node, proxystore.NodeName(ei.GetTopology().GetCluster(), ei.GetTopology().GetNode())) {
+				return true
+			}
+			if ready != nil && ei.GetConditions().GetReady() != *ready {
+				return true
+			}
+
+			endpoints = append(endpoints, protoJSON(ei))
+			return true
+		})
+	})
+
+	writeJSON(w, struct {
+		Rev       uint64            `json:"rev"`
+		Endpoints []json.RawMessage `json:"endpoints"`
+	}{rev, endpoints})
+}
+
+func (h *Handler) serveForNode(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	nodeName := q.Get("node")
+	if nodeName == "" {
+		http.Error(w, "the node parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	filter, err := newFilter(q.Get("namespace"), q.Get("service"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	selector := h.Selector
+	if selector == nil {
+		selector = pkgendpoints.Default{}
+	}
+
+	type serviceEndpoints struct {
+		Namespace string            `json:"namespace"`
+		Name      string            `json:"name"`
+		Endpoints []json.RawMessage `json:"endpoints"`
+	}
+
+	var node json.RawMessage
+	services := make([]serviceEndpoints, 0)
+
+	rev := h.view(func(tx *proxystore.Tx) {
+		if n := tx.GetNode(nodeName); n != nil {
+			node = protoJSON(n)
+		}
+
+		cluster := pkgendpoints.GetNode(tx, nodeName).GetTopology().GetCluster()
+
+		tx.Each(proxystore.Services, func(kv *proxystore.KV) bool {
+			svc := kv.Service.ServiceFor(cluster)
+
+			if svc == nil || !filter.match(svc.Namespace, svc.Name) {
+				return true
+			}
+
+			se := serviceEndpoints{
+				Namespace: svc.Namespace,
+				Name:      svc.Name,
+				Endpoints: make([]json.RawMessage, 0),
+			}
+
+			for _, ei := range pkgendpoints.ForNodeWith(selector, tx, kv.Service, nodeName) {
+				se.Endpoints = append(se.Endpoints, protoJSON(ei))
+			}
+
+			services = append(services, se)
+			return true
+		})
+	})
+
+	writeJSON(w, struct {
+		Rev      uint64             `json:"rev"`
+		NodeName string             `json:"nodeName"`
+		Node     json.RawMessage    `json:"node,omitempty"` // unknown node if not set
+		Services []serviceEndpoints `json:"services"`
+	}{rev, nodeName, node, services})
+}
+
+// filter matches objects by namespace and name.
+type filter struct {
+	namespace, name string
+}
+
+func newFilter(namespace, name string) (f filter, err error) {
+	for _, pattern := range []string{namespace, name} {
+		if _, err = path.Match(pattern, ""); err != nil {
+			return
+		}
+	}
+
+	f = filter{namespace, name}
+	return
+}
+
+func (f filter) match(namespace, name string) bool {
+	return (f.namespace == "" || match(f.namespace, namespace)) &&
+		(f.name == "" || match(f.name, name))
+}
+
+// match matches a value against a glob pattern, already checked as valid.
+func match(pattern, value string) bool {
+	ok, _ := path.Match(pattern, value)
+	return ok
+}
+
+func protoJSON(m proto.Message) json.RawMessage {
+	ba, err := protojson.Marshal(m)
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return ba
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"sigs.k8s.io/kpng/api/globalv1"
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+func TestHandler(t *testing.T) {
+	store := proxystore.New()
+
+	mux := http.NewServeMux()
+	(&Handler{Store: store}).Register(mux)
+
+	get := func(url string, result interface{}) int {
+		t.Helper()
+
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest("GET", url, nil))
+
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), result); err != nil {
+				t.Fatal(url, ": ", err)
+			}
+		}
+		return rec.Code
+	}
+
+	// empty store
+	status := struct {
+		Rev  uint64
+		Sets []struct {
+			Set     string
+			Synced  bool
+			Entries int
+		}
+	}{}
+	get("/debug/store", &status)
+	if status.Rev != 0 || len(status.Sets) != 3 || status.Sets[0].Synced {
+		t.Errorf("unexpected empty store status: %+v", status)
+	}
+
+	endpoint := func(svc, ip, node string, ready bool) *globalv1.EndpointInfo {
+		return &globalv1.EndpointInfo{
+			Namespace:   "default",
+			SourceName:  svc + "-abcde",
+			ServiceName: svc,
+			Endpoint:    &localv1.Endpoint{IPs: localv1.NewIPSet(ip)},
+			Conditions:  &globalv1.EndpointConditions{Ready: ready},
+			Topology:    &globalv1.TopologyInfo{Node: node},
+		}
+	}
+
+	store.Update(func(tx *proxystore.Tx) {
+		tx.SetService(&localv1.Service{Namespace: "default", Name: "web", Type: "ClusterIP"})
+		tx.SetService(&localv1.Service{Namespace: "default", Name: "dns", Type: "ClusterIP"})
+		tx.SetService(&localv1.Service{Namespace: "kube-system", Name: "web-lb", Type: "LoadBalancer"})
+		tx.SetEndpointsOfSource("default", "web-abcde", []*globalv1.EndpointInfo{
+			endpoint("web", "10.0.0.1", "node-a", true),
+			endpoint("web", "10.0.0.2", "node-b", false),
+		})
+		tx.SetNode(&globalv1.Node{Name: "node-a"})
+		tx.SetSync(proxystore.Services)
+	})
+
+	get("/debug/store", &status)
+	if status.Rev != 1 {
+		t.Errorf("expected rev 1, got %d", status.Rev)
+	}
+	for _, set := range status.Sets {
+		expected := map[string]int{"GlobalServiceInfos": 3, "GlobalEndpointInfos": 2, "GlobalNodeInfos": 1}[set.Set]
+		if set.Entries != expected {
+			t.Errorf("%s: expected %d entries, got %d", set.Set, expected, set.Entries)
+		}
+		if set.Synced != (set.Set == "GlobalServiceInfos") {
+			t.Errorf("%s: unexpected sync status %v", set.Set, set.Synced)
+		}
+	}
+
+	services := struct {
+		Services []struct {
+			Service struct{ Namespace, Name string }
+		}
+	}{}
+
+	for url, expected := range map[string]int{
+		"/debug/services":                            3,
+		"/debug/services?name=web*":                  2,
+		"/debug/services?namespace=default":          2,
+		"/debug/services?type=LoadBalancer":          1,
+		"/debug/services?namespace=kube-*&name=web*": 1,
+	} {
+		get(url, &services)
+		if len(services.Services) != expected {
+			t.Errorf("%s: expected %d services, got %d", url, expected, len(services.Services))
+		}
+	}
+
+	endpoints := struct {
+		Endpoints []struct{ ServiceName string }
+	}{}
+
+	for url, expected := range map[string]int{
+		"/debug/endpoints":                     2,
+		"/debug/endpoints?service=dns":         0,
+		"/debug/endpoints?node=node-b":         1,
+		"/debug/endpoints?ready=true":          1,
+		"/debug/endpoints?ready=0&node=node-a": 0,
+	} {
+		get(url, &endpoints)
+		if len(endpoints.Endpoints) != expected {
+			t.Errorf("%s: expected %d endpoints, got %d", url, expected, len(endpoints.Endpoints))
+		}
+	}
+
+	forNode := struct {
+		NodeName string
+		Node     *struct{ Name string }
+		Services []struct {
+			Name      string
+			Endpoints []struct {
+				Endpoint struct{ IPs struct{ V4 []string } }
+			}
+		}
+	}{}
+
+	get("/debug/fornode?node=node-c&service=web", &forNode)
+	if forNode.Node != nil {
+		t.Errorf("expected an unknown node")
+	}
+	if len(forNode.Services) != 1 || len(forNode.Services[0].Endpoints) != 1 ||
+		forNode.Services[0].Endpoints[0].Endpoint.IPs.V4[0] != "10.0.0.1" {
+		t.Errorf("unexpected endpoints for node-c: %+v", forNode.Services)
+	}
+
+	for _, url := range []string{"/debug/fornode", "/debug/services?name=[", "/debug/endpoints?ready=maybe"} {
+		if code := get(url, nil); code != http.StatusBadRequest {
+			t.Errorf("%s: expected a bad request, got %d", url, code)
+		}
+	}
+}
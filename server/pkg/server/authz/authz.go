@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package authz authorizes the API clients from the identity of their TLS certificate.
+package authz
+
+import (
+	"context"
+	"crypto/x509"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"sigs.k8s.io/kpng/api/globalv1"
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+// NodeCNPrefix prefixes the node name in the CN of the nodes' certificates, like kubelets' ones.
+const NodeCNPrefix = "system:node:"
+
+type Config struct {
+	// Enabled restricts the clients to the nodes of their certificate, and the globalv1 API to
+	// the GlobalIdentities.
+	Enabled bool
+
+	// NodeSANs also allows the DNS SANs of the certificates as node names.
+	NodeSANs bool
+
+	// GlobalIdentities are the identities (certificate CN or SAN) allowed to use the globalv1 API.
+	GlobalIdentities []string
+}
+
+func (c *Config) BindFlags(flags *pflag.FlagSet) {
+	flags.BoolVar(&c.Enabled, "authorize-nodes", false, "only allow the local API clients to watch the node of their certificate (CN "+NodeCNPrefix+"<name>); requires TLS")
+	flags.BoolVar(&c.NodeSANs, "authorize-node-sans", false, "with --authorize-nodes, also allow the DNS SANs of the certificates as node names")
+	flags.StringSliceVar(&c.GlobalIdentities, "authorize-global", nil, "with --authorize-nodes, the identities (certificate CN or SAN) allowed to use the globalv1 API (ie: kpng followers)")
+}
+
+// Identity is the identity of a client, from its certificate.
+type Identity struct {
+	CommonName string
+	DNSNames   []string
+	URIs       []string
+}
+
+// IdentityOf returns the identity of the certificate.
+func IdentityOf(cert *x509.Certificate) *Identity {
+	id := &Identity{
+		CommonName: cert.Subject.CommonName,
+		DNSNames:   cert.DNSNames,
+	}
+
+	for _, uri := range cert.URIs {
+		id.URIs = append(id.URIs, uri.String())
+	}
+
+	return id
+}
+
+// PeerIdentity returns the identity of the client of the call, or nil if it didn't present a
+// verified certificate.
+func PeerIdentity(ctx context.Context) *Identity {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	return IdentityOf(tlsInfo.State.VerifiedChains[0][0])
+}
+
+// NodeAllowed returns true if the identity can watch the node. The node name may be qualified by
+// its cluster (see proxystore.NodeName); the certificates only name the node.
+func (c *Config) NodeAllowed(id *Identity, nodeName string) bool {
+	if id == nil || nodeName == "" {
+		return false
+	}
+
+	_, nodeName = proxystore.SplitNodeName(nodeName)
+	if nodeName == "" {
+		return false
+	}
+
+	if name, ok := strings.CutPrefix(id.CommonName, NodeCNPrefix); ok && name == nodeName {
+		return true
+	}
+
+	if c.NodeSANs {
+		for _, name := range id.DNSNames {
+			if name == nodeName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// GlobalAllowed returns true if the identity can use the globalv1 API.
+func (c *Config) GlobalAllowed(id *Identity) bool {
+	if id == nil {
+		return false
+	}
+
+	for _, allowed := range c.GlobalIdentities {
+		if allowed == id.CommonName {
+			return true
+		}
+		for _, names := range [][]string{id.DNSNames, id.URIs} {
+			for _, name := range names {
+				if allowed == name {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// AuthorizeNode returns a PermissionDenied error if the client of the call can't watch the node.
+func (c *Config) AuthorizeNode(ctx context.Context, nodeName string) error {
+	if !c.Enabled {
+		return nil
+	}
+
+	id := PeerIdentity(ctx)
+	if !c.NodeAllowed(id, nodeName) {
+		return status.Errorf(codes.PermissionDenied, "%s is not allowed to watch node %q", id, nodeName)
+	}
+
+	return nil
+}
+
+// StreamInterceptor restricts the globalv1 API to the global identities.
+func (c *Config) StreamInterceptor() grpc.StreamServerInterceptor {
+	globalPrefix := "/" + globalv1.Sets_ServiceDesc.ServiceName + "/"
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if c.Enabled && strings.HasPrefix(info.FullMethod, globalPrefix) {
+			if id := PeerIdentity(ss.Context()); !c.GlobalAllowed(id) {
+				return status.Errorf(codes.PermissionDenied, "%s is not allowed to use the globalv1 API", id)
+			}
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func (id *Identity) String() string {
+	if id == nil {
+		return "anonymous client"
+	}
+	return "client " + id.CommonName
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authz
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"sigs.k8s.io/kpng/api/globalv1"
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/server/pkg/server/endpoints"
+	"sigs.k8s.io/kpng/server/pkg/server/global"
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+	next int64
+}
+
+func newTestCA(t *testing.T) *testCA {
+	ca := &testCA{}
+	ca.cert, ca.key = ca.issue(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "test CA"},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	})
+
+	ca.pool = x509.NewCertPool()
+	ca.pool.AddCert(ca.cert)
+	return ca
+}
+
+// issue signs the template with the CA (self-signed if the CA is not created yet).
+func (ca *testCA) issue(t *testing.T, template *x509.Certificate) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca.next++
+	template.SerialNumber = big.NewInt(ca.next)
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	parent, parentKey := template, key
+	if ca.cert != nil {
+		parent, parentKey = ca.cert, ca.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, key
+}
+
+func (ca *testCA) tlsCert(t *testing.T, template *x509.Certificate) tls.Certificate {
+	cert, key := ca.issue(t, template)
+	return tls.Certificate{Certificate: [][]byte{cert.Raw}, PrivateKey: key}
+}
+
+func TestAuthorization(t *testing.T) {
+	ca := newTestCA(t)
+
+	store := proxystore.New()
+	store.Update(func(tx *proxystore.Tx) {
+		tx.SetService(&localv1.Service{Namespace: "default", Name: "svc", Type: "ClusterIP"})
+		for _, set := range proxystore.AllSets {
+			tx.SetSync(set)
+		}
+	})
+
+	cfg := &Config{
+		Enabled:          true,
+		NodeSANs:         true,
+		GlobalIdentities: []string{"kpng-mirror"},
+	}
+
+	serverCert := ca.tlsCert(t, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "kpng"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+
+	srv := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(&tls.Config{
+			Certificates: []tls.Certificate{serverCert},
+			ClientCAs:    ca.pool,
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+		})),
+		grpc.StreamInterceptor(cfg.StreamInterceptor()),
+	)
+
+	localv1.RegisterSetsServer(srv, &endpoints.Server{Store: store, Authorize: cfg.AuthorizeNode})
+	global.Setup(srv, store)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go srv.Serve(lis)
+	defer srv.Stop()
+
+	dial := func(cn string, dnsNames ...string) *grpc.ClientConn {
+		cert := ca.tlsCert(t, &x509.Certificate{
+			Subject:     pkix.Name{CommonName: cn},
+			DNSNames:    dnsNames,
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+
+		conn, err := grpc.Dial(lis.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+			Certificates: []tls.Certificate{cert},
+			RootCAs:      ca.pool,
+		})))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return conn
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	watchLocal := func(conn *grpc.ClientConn, nodeName string) error {
+		watch, err := localv1.NewSetsClient(conn).Watch(ctx)
+		if err != nil {
+			return err
+		}
+		if err = watch.Send(&localv1.WatchReq{NodeName: nodeName}); err != nil {
+			return err
+		}
+		_, err = watch.Recv()
+		return err
+	}
+
+	watchGlobal := func(conn *grpc.ClientConn) error {
+		watch, err := globalv1.NewSetsClient(conn).Watch(ctx)
+		if err != nil {
+			return err
+		}
+		if err = watch.Send(&globalv1.GlobalWatchReq{}); err != nil {
+			return err
+		}
+		_, err = watch.Recv()
+		return err
+	}
+
+	nodeA := dial(NodeCNPrefix + "node-a")
+	defer nodeA.Close()
+
+	sanNode := dial("some-node", "node-c")
+	defer sanNode.Close()
+
+	mirror := dial("kpng-mirror")
+	defer mirror.Close()
+
+	for _, tc := range []struct {
+		name    string
+		err     error
+		allowed bool
+	}{
+		{"node-a watching node-a", watchLocal(nodeA, "node-a"), true},
+		{"node-a watching node-b", watchLocal(nodeA, "node-b"), false},
+		{"node-a watching c1/node-a", watchLocal(nodeA, "c1/node-a"), true},
+		{"node-a watching c1/node-b", watchLocal(nodeA, "c1/node-b"), false},
+		{"node-a watching node-a/", watchLocal(nodeA, "node-a/"), false},
+		{"node-a watching globalv1", watchGlobal(nodeA), false},
+		{"SAN node-c watching node-c", watchLocal(sanNode, "node-c"), true},
+		{"SAN node-c watching some-node", watchLocal(sanNode, "some-node"), false},
+		{"SAN node-c watching c2/node-c", watchLocal(sanNode, "c2/node-c"), true},
+		{"mirror watching globalv1", watchGlobal(mirror), true},
+		{"mirror watching node-a", watchLocal(mirror, "node-a"), false},
+	} {
+		if tc.allowed && tc.err != nil {
+			t.Errorf("%s: expected to be allowed, got %v", tc.name, tc.err)
+		}
+		if !tc.allowed && status.Code(tc.err) != codes.PermissionDenied {
+			t.Errorf("%s: expected to be denied, got %v", tc.name, tc.err)
+		}
+	}
+}
@@ -17,12 +17,32 @@ limitations under the License.
 package endpoints
 
 import (
+	"time"
+
 	"google.golang.org/grpc"
 
 	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/server/jobs/store2diff"
+	"sigs.k8s.io/kpng/server/jobs/store2localdiff"
+	pkgendpoints "sigs.k8s.io/kpng/server/pkg/endpoints"
 	"sigs.k8s.io/kpng/server/proxystore"
 )
 
-func Setup(s grpc.ServiceRegistrar, store *proxystore.Store) {
-	localv1.RegisterSetsServer(s, &Server{Store: store})
+// Setup registers the local API server. Disconnected watchers can resume within resumeTTL (0 disables resuming).
+func Setup(s grpc.ServiceRegistrar, store *proxystore.Store, resumeTTL time.Duration, selector pkgendpoints.Selector) *Server {
+	srv := &Server{
+		Store:    store,
+		Selector: selector,
+		Cache:    &store2localdiff.Cache{},
+		Statuses: &Statuses{Store: store},
+		Watchers: &Watchers{Store: store},
+	}
+
+	if resumeTTL > 0 {
+		srv.Views = store2diff.NewViews(resumeTTL)
+	}
+
+	localv1.RegisterSetsServer(s, srv)
+
+	return srv
 }
@@ -17,6 +17,10 @@ limitations under the License.
 package endpoints
 
 import (
+	"context"
+	"sync"
+	"time"
+
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/peer"
@@ -24,7 +28,10 @@ import (
 	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/server/jobs/store2diff"
 	"sigs.k8s.io/kpng/server/jobs/store2localdiff"
+	pkgendpoints "sigs.k8s.io/kpng/server/pkg/endpoints"
+	"sigs.k8s.io/kpng/server/pkg/metrics"
 	"sigs.k8s.io/kpng/server/proxystore"
 )
 
@@ -32,6 +39,35 @@ type Server struct {
 	localv1.UnimplementedSetsServer
 
 	Store *proxystore.Store
+
+	// Views retains the state of disconnected watchers so they can resume (nil to disable)
+	Views *store2diff.Views
+
+	// Selector selects the watchers' endpoints (nil for the default selection)
+	Selector pkgendpoints.Selector
+
+	// Cache shares the endpoints selected between watchers (nil to disable)
+	Cache *store2localdiff.Cache
+
+	// Statuses collects the apply statuses reported by the watchers (nil to ignore them)
+	Statuses *Statuses
+
+	// Watchers tracks the connected watchers (nil to disable)
+	Watchers *Watchers
+
+	// Authorize returns an error if the client of the call can't watch the node (nil to allow all)
+	Authorize func(ctx context.Context, nodeName string) error
+
+	// SendTimeout is the deadline of each send to a watcher. A watcher not receiving within it
+	// (ie: a wedged backend) is disconnected, and gets the whole state again when it reconnects
+	// (0 to disable).
+	SendTimeout time.Duration
+
+	// Coalescing collapses bursts of revisions into one Sync for each watcher (zero to disable)
+	Coalescing store2diff.Coalescing
+
+	stalledMu sync.Mutex
+	stalled   map[string]bool // the nodes which watch was dropped for being stalled
 }
 
 var syncItem = &localv1.OpItem{Op: &localv1.OpItem_Sync{}}
@@ -46,33 +82,203 @@ func (s *Server) Watch(res localv1.Sets_WatchServer) error {
 	klog.Info("new connection from ", remote)
 	defer klog.Info("connection from ", remote, " closed")
 
+	metrics.Kpng_local_watchers.Inc()
+	defer metrics.Kpng_local_watchers.Dec()
+
+	// the first request tells the state the client may resume from
+	req, err := res.Recv()
+	if err != nil {
+		return grpc.Errorf(codes.Aborted, "recv error: %v", err)
+	}
+
+	stream := &timedStream{Sets_WatchServer: res, timeout: s.SendTimeout, stalled: make(chan struct{})}
+
+	sink := &serverSink{Sets_WatchServer: stream, remote: remote, firstReq: req, statuses: s.Statuses, authorize: s.Authorize, takeStalled: s.takeStalled}
+
+	if req.Batching != nil {
+		sink.batcher = localv1.NewBatcher(stream, req.Batching)
+		klog.V(1).Info("remote ", remote, " gets batches of ", sink.batcher.MaxBytes, " bytes, compression: ", sink.batcher.Compression)
+	}
+
+	if s.Watchers != nil {
+		sink.watchers = s.Watchers
+		sink.watcherID = s.Watchers.add(remote)
+		defer s.Watchers.remove(sink.watcherID)
+	}
+
 	job := &store2localdiff.Job{
-		Store: s.Store,
-		Sink:  serverSink{res, remote},
+		Store:    s.Store,
+		Sink:     sink,
+		Views:    s.Views,
+		Resume:   req.StateDigest,
+		Selector: s.Selector,
+		Cache:    s.Cache,
+
+		Coalescing: s.Coalescing,
+	}
+
+	if s.SendTimeout == 0 {
+		return job.Run(res.Context())
+	}
+
+	// a send blocked by the flow control only returns when the stream ends, so run the job
+	// aside to be able to end it
+	done := make(chan error, 1)
+	go func() {
+		done <- job.Run(res.Context())
+	}()
+
+	select {
+	case err := <-done:
+		return err
+
+	case <-stream.stalled:
+		klog.Warning("dropping stalled watcher ", remote, " (node ", req.NodeName, "): no send completed in ", s.SendTimeout)
+		metrics.Kpng_local_stalled_watchers.Inc()
+
+		s.setStalled(req.NodeName)
+		return grpc.Errorf(codes.DeadlineExceeded, "send timeout")
 	}
+}
+
+func (s *Server) setStalled(nodeName string) {
+	s.stalledMu.Lock()
+	defer s.stalledMu.Unlock()
 
-	return job.Run(res.Context())
+	if s.stalled == nil {
+		s.stalled = map[string]bool{}
+	}
+	s.stalled[nodeName] = true
+}
+
+// takeStalled returns true if the node's last watch was dropped for being stalled, and forgets it.
+func (s *Server) takeStalled(nodeName string) (stalled bool) {
+	s.stalledMu.Lock()
+	defer s.stalledMu.Unlock()
+
+	stalled = s.stalled[nodeName]
+	delete(s.stalled, nodeName)
+	return
+}
+
+// timedStream measures the sends, and signals when one exceeds its deadline.
+type timedStream struct {
+	localv1.Sets_WatchServer
+
+	timeout   time.Duration
+	stalled   chan struct{}
+	stallOnce sync.Once
+}
+
+func (s *timedStream) Send(op *localv1.OpItem) error {
+	start := time.Now()
+
+	if s.timeout != 0 {
+		timer := time.AfterFunc(s.timeout, func() {
+			s.stallOnce.Do(func() { close(s.stalled) })
+		})
+		defer timer.Stop()
+	}
+
+	defer func() {
+		metrics.Kpng_local_send_duration_seconds.Observe(time.Since(start).Seconds())
+	}()
+
+	return s.Sets_WatchServer.Send(op)
 }
 
 type serverSink struct {
 	localv1.Sets_WatchServer
-	remote string
+	remote   string
+	firstReq *localv1.WatchReq
+	filter   *localv1.WatchFilter
+	statuses *Statuses
+
+	watchers  *Watchers
+	watcherID uint64
+
+	// batcher packs the ops, if the watcher asked for it
+	batcher *localv1.Batcher
+
+	authorize func(ctx context.Context, nodeName string) error
+
+	// takeStalled consumes the stall of the node's last watch, once the first request is authorized
+	takeStalled func(nodeName string) bool
+	wasStalled  bool
 }
 
-func (s serverSink) Setup() { /* noop */ }
+var _ store2diff.ResumeChecker = &serverSink{}
+
+func (s *serverSink) Setup() { /* noop */ }
+
+func (s *serverSink) WaitRequest() (nodeName string, err error) {
+	req := s.firstReq
+	s.firstReq = nil
 
-func (s serverSink) WaitRequest() (nodeName string, err error) {
-	req, err := s.Recv()
+	first := req != nil
+
+	if req == nil {
+		req, err = s.Recv()
+	}
 
 	if err != nil {
 		err = grpc.Errorf(codes.Aborted, "recv error: %v", err)
 		return
 	}
 
-	klog.V(1).Info("remote ", s.remote, " requested node ", req.NodeName)
+	klog.V(1).Info("remote ", s.remote, " requested node ", req.NodeName, " filter: ", req.Filter)
+
+	if s.authorize != nil {
+		if err = s.authorize(s.Context(), req.NodeName); err != nil {
+			klog.Warning("remote ", s.remote, " denied: ", err)
+			return
+		}
+	}
 
 	nodeName = req.NodeName
+	s.filter = req.Filter
+
+	if first && s.takeStalled(nodeName) {
+		klog.Info("node ", nodeName, " was stalled, sending it the whole state")
+		s.wasStalled = true
+	}
+
+	if s.statuses != nil {
+		s.statuses.Report(nodeName, s.remote, req.Applied)
+	}
+
+	if s.watchers != nil {
+		s.watchers.update(s.watcherID, func(w *WatcherInfo) { w.Node = nodeName })
+	}
+
+	return
+}
+
+// CanResume returns false if the node's last watch was dropped for being stalled, as the watcher
+// may not have received the state it was sent.
+func (s *serverSink) CanResume() bool {
+	return !s.wasStalled
+}
+
+func (s *serverSink) Send(op *localv1.OpItem) (err error) {
+	if s.batcher != nil {
+		err = s.batcher.Send(op)
+	} else {
+		err = s.Sets_WatchServer.Send(op)
+	}
+
+	if sync := op.GetSync(); sync != nil && err == nil && s.watchers != nil {
+		s.watchers.update(s.watcherID, func(w *WatcherInfo) {
+			w.Rev = sync.Rev
+			w.SentTime = time.Now()
+		})
+	}
+
 	return
 }
 
-func (s serverSink) Reset() {}
+func (s *serverSink) WatchFilter() *localv1.WatchFilter {
+	return s.filter
+}
+
+func (s *serverSink) Reset() {}
@@ -0,0 +1,163 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+func TestStalledWatcher(t *testing.T) {
+	store := proxystore.New()
+	store.Update(func(tx *proxystore.Tx) {
+		for i := 0; i < 5000; i++ {
+			tx.SetService(&localv1.Service{
+				Namespace: "default",
+				Name:      fmt.Sprint("svc-", i),
+				Type:      "ClusterIP",
+				IPs:       &localv1.ServiceIPs{ClusterIPs: localv1.NewIPSet(fmt.Sprintf("10.0.%d.%d", i/256, i%256))},
+			})
+		}
+		for _, set := range proxystore.AllSets {
+			tx.SetSync(set)
+		}
+	})
+
+	// node-a's watchers are denied while denied is set
+	var denied atomic.Bool
+	authorize := func(ctx context.Context, nodeName string) error {
+		if denied.Load() {
+			return status.Error(codes.PermissionDenied, "denied")
+		}
+		return nil
+	}
+
+	srv := &Server{Store: store, SendTimeout: 200 * time.Millisecond, Authorize: authorize}
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grpcSrv := grpc.NewServer()
+	localv1.RegisterSetsServer(grpcSrv, srv)
+
+	go grpcSrv.Serve(lis)
+	defer grpcSrv.Stop()
+
+	// a fixed flow control window, so the server blocks when the client doesn't read
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure(),
+		grpc.WithInitialWindowSize(64<<10), grpc.WithInitialConnWindowSize(64<<10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	watch, err := localv1.NewSetsClient(conn).Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = watch.Send(&localv1.WatchReq{NodeName: "node-a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the watcher is wedged: it doesn't read
+	time.Sleep(time.Second)
+
+	srv.stalledMu.Lock()
+	stalled := srv.stalled["node-a"]
+	srv.stalledMu.Unlock()
+
+	if !stalled {
+		t.Fatal("expected the watcher to be dropped")
+	}
+
+	for {
+		_, err = watch.Recv()
+		if err != nil {
+			break
+		}
+	}
+
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("expected a deadline exceeded error, got %v", err)
+	}
+
+	// a denied watcher of the node doesn't consume the stall
+	denied.Store(true)
+
+	watch, err = localv1.NewSetsClient(conn).Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = watch.Send(&localv1.WatchReq{NodeName: "node-a"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = watch.Recv(); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected a permission denied error, got %v", err)
+	}
+
+	srv.stalledMu.Lock()
+	stalled = srv.stalled["node-a"]
+	srv.stalledMu.Unlock()
+
+	if !stalled {
+		t.Fatal("expected the stall to be kept for the authorized watcher")
+	}
+
+	denied.Store(false)
+
+	// the watcher reconnects, and gets the whole state again
+	watch, err = localv1.NewSetsClient(conn).Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = watch.Send(&localv1.WatchReq{NodeName: "node-a", StateDigest: 1234}); err != nil {
+		t.Fatal(err)
+	}
+
+	op, err := watch.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if op.GetReset_() == nil {
+		t.Errorf("expected a reset, got %v", op)
+	}
+
+	if srv.takeStalled("node-a") {
+		t.Error("expected the stall to be forgotten")
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/server/pkg/metrics"
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+// NodeStatus is the last apply status reported by a node.
+type NodeStatus struct {
+	Node     string        `json:"node"`
+	Remote   string        `json:"remote"`
+	Rev      uint64        `json:"rev"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+	Time     time.Time     `json:"time"`
+}
+
+// Statuses aggregates the apply statuses reported by the nodes.
+type Statuses struct {
+	Store *proxystore.Store
+
+	mu    sync.Mutex
+	nodes map[string]NodeStatus
+
+	// storeNodes are the nodes in the store at the last prune
+	storeNodes map[string]bool
+}
+
+// Report records the status reported by the node.
+func (s *Statuses) Report(nodeName, remote string, status *localv1.ApplyStatus) {
+	if status == nil {
+		return
+	}
+
+	ns := NodeStatus{
+		Node:     nodeName,
+		Remote:   remote,
+		Rev:      status.Rev,
+		Error:    status.Error,
+		Duration: time.Duration(status.DurationNanos),
+		Time:     time.Now(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.nodes == nil {
+		s.nodes = map[string]NodeStatus{}
+	}
+
+	prev, known := s.nodes[nodeName]
+	if known && prev.Rev == ns.Rev && prev.Duration == ns.Duration && prev.Error == ns.Error {
+		return // same apply, reported again (ie: on reconnect)
+	}
+
+	s.nodes[nodeName] = ns
+
+	metrics.Kpng_node_applied_revision.WithLabelValues(nodeName).Set(float64(ns.Rev))
+	metrics.Kpng_node_apply_duration_seconds.WithLabelValues(nodeName).Set(ns.Duration.Seconds())
+
+	if ns.Error != "" {
+		metrics.Kpng_node_apply_failures.WithLabelValues(nodeName).Inc()
+		metrics.Kpng_node_apply_failed.WithLabelValues(nodeName).Set(1)
+	} else {
+		metrics.Kpng_node_apply_failed.WithLabelValues(nodeName).Set(0)
+	}
+}
+
+// Prune removes the statuses of the nodes deleted from the store, until the context is done or
+// the store is closed.
+func (s *Statuses) Prune(ctx context.Context) {
+	rev := uint64(0)
+
+	for ctx.Err() == nil {
+		newRev, closed := s.Store.WaitRev(rev, time.Second)
+		if closed {
+			return
+		}
+		if newRev == rev {
+			continue // check the context again
+		}
+
+		rev, _ = s.Store.View(rev, s.prune)
+	}
+}
+
+// prune removes the statuses of the nodes in the store at the last prune, but not anymore.
+func (s *Statuses) prune(tx *proxystore.Tx) {
+	nodes := map[string]bool{}
+	tx.Each(proxystore.Nodes, func(kv *proxystore.KV) bool {
+		nodes[kv.Name] = true // as named by watchers (see proxystore.NodeName)
+		return true
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name := range s.storeNodes {
+		if !nodes[name] {
+			s.forget(name)
+		}
+	}
+
+	s.storeNodes = nodes
+}
+
+// forget removes the status of the node, and its metrics. Must be called with the lock held.
+func (s *Statuses) forget(nodeName string) {
+	if _, ok := s.nodes[nodeName]; !ok {
+		return
+	}
+
+	delete(s.nodes, nodeName)
+
+	metrics.Kpng_node_applied_revision.DeleteLabelValues(nodeName)
+	metrics.Kpng_node_apply_duration_seconds.DeleteLabelValues(nodeName)
+	metrics.Kpng_node_apply_failed.DeleteLabelValues(nodeName)
+	metrics.Kpng_node_apply_failures.DeleteLabelValues(nodeName)
+}
+
+// List returns the nodes' statuses, ordered by node name.
+func (s *Statuses) List() (statuses []NodeStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses = make([]NodeStatus, 0, len(s.nodes))
+	for _, ns := range s.nodes {
+		statuses = append(statuses, ns)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Node < statuses[j].Node })
+	return
+}
+
+// ServeHTTP writes the nodes' statuses, with their lag behind the store, as JSON.
+func (s *Statuses) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	type nodeLag struct {
+		NodeStatus
+		Lag uint64 `json:"lag"`
+	}
+
+	rev := s.Store.Rev()
+
+	nodes := make([]nodeLag, 0)
+	for _, ns := range s.List() {
+		nl := nodeLag{NodeStatus: ns}
+		if ns.Rev < rev {
+			nl.Lag = rev - ns.Rev
+		}
+		nodes = append(nodes, nl)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(struct {
+		Rev   uint64    `json:"rev"`
+		Nodes []nodeLag `json:"nodes"`
+	}{rev, nodes})
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"sigs.k8s.io/kpng/api/globalv1"
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+func TestStatuses(t *testing.T) {
+	store := proxystore.New()
+	for i := 0; i < 5; i++ {
+		store.Update(func(tx *proxystore.Tx) {
+			tx.SetService(&localv1.Service{Namespace: "default", Name: "svc", Type: string(rune('a' + i))})
+		})
+	}
+
+	statuses := &Statuses{Store: store}
+
+	statuses.Report("node-b", "10.0.0.2:1234", &localv1.ApplyStatus{Rev: 5, DurationNanos: 1000})
+	statuses.Report("node-a", "10.0.0.1:1234", &localv1.ApplyStatus{Rev: 3, Error: "nft failed"})
+	statuses.Report("node-c", "10.0.0.3:1234", nil)
+
+	rec := httptest.NewRecorder()
+	statuses.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/nodes", nil))
+
+	result := struct {
+		Rev   uint64
+		Nodes []struct {
+			Node  string
+			Rev   uint64
+			Error string
+			Lag   uint64
+		}
+	}{}
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Rev != 5 {
+		t.Errorf("expected rev 5, got %d", result.Rev)
+	}
+
+	if len(result.Nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %d", len(result.Nodes))
+	}
+
+	if n := result.Nodes[0]; n.Node != "node-a" || n.Rev != 3 || n.Error != "nft failed" || n.Lag != 2 {
+		t.Errorf("unexpected status for node-a: %+v", n)
+	}
+	if n := result.Nodes[1]; n.Node != "node-b" || n.Rev != 5 || n.Error != "" || n.Lag != 0 {
+		t.Errorf("unexpected status for node-b: %+v", n)
+	}
+}
+
+func TestPruneStatuses(t *testing.T) {
+	store := proxystore.New()
+	store.Update(func(tx *proxystore.Tx) {
+		tx.SetNode(&globalv1.Node{Name: "node-a"})
+		tx.SetNode(&globalv1.Node{Name: "node-b"})
+	})
+
+	statuses := &Statuses{Store: store}
+	statuses.Report("node-a", "10.0.0.1:1234", &localv1.ApplyStatus{Rev: 1})
+	statuses.Report("node-b", "10.0.0.2:1234", &localv1.ApplyStatus{Rev: 1})
+	statuses.Report("node-c", "10.0.0.3:1234", &localv1.ApplyStatus{Rev: 1}) // not in the store
+
+	rev, _ := store.View(0, statuses.prune)
+
+	store.Update(func(tx *proxystore.Tx) { tx.DelNode("node-a") })
+	store.View(rev, statuses.prune)
+
+	names := []string{}
+	for _, ns := range statuses.List() {
+		names = append(names, ns.Node)
+	}
+
+	if fmt.Sprint(names) != "[node-b node-c]" {
+		t.Errorf("expected the statuses of node-b and node-c, got %v", names)
+	}
+}
+
+func TestWatchers(t *testing.T) {
+	store := proxystore.New()
+	for i := 0; i < 3; i++ {
+		store.Update(func(tx *proxystore.Tx) {
+			tx.SetService(&localv1.Service{Namespace: "default", Name: "svc", Type: string(rune('a' + i))})
+		})
+	}
+
+	watchers := &Watchers{Store: store}
+
+	a := watchers.add("10.0.0.1:1234")
+	b := watchers.add("10.0.0.2:1234")
+	gone := watchers.add("10.0.0.3:1234")
+
+	watchers.update(a, func(w *WatcherInfo) { w.Node, w.Rev = "node-b", 3 })
+	watchers.update(b, func(w *WatcherInfo) { w.Node, w.Rev = "node-a", 1 })
+	watchers.remove(gone)
+
+	rec := httptest.NewRecorder()
+	watchers.ServeHTTP(rec, httptest.NewRequest("GET", "/debug/watchers", nil))
+
+	result := struct {
+		Rev      uint64
+		Watchers []struct {
+			Node   string
+			Remote string
+			Rev    uint64
+			Lag    uint64
+		}
+	}{}
+
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Watchers) != 2 {
+		t.Fatalf("expected 2 watchers, got %d", len(result.Watchers))
+	}
+
+	if w := result.Watchers[0]; w.Node != "node-a" || w.Remote != "10.0.0.2:1234" || w.Lag != 2 {
+		t.Errorf("unexpected watcher: %+v", w)
+	}
+	if w := result.Watchers[1]; w.Node != "node-b" || w.Lag != 0 {
+		t.Errorf("unexpected watcher: %+v", w)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package endpoints
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/kpng/server/proxystore"
+)
+
+// WatcherInfo describes a connected watcher.
+type WatcherInfo struct {
+	ID     uint64    `json:"id"`
+	Node   string    `json:"node"`
+	Remote string    `json:"remote"`
+	Since  time.Time `json:"since"`
+	// Rev is the last revision sent to the watcher (0 if none)
+	Rev uint64 `json:"rev"`
+	// SentTime is when the last revision was sent
+	SentTime time.Time `json:"sentTime,omitempty"`
+}
+
+// Watchers tracks the connected watchers.
+type Watchers struct {
+	Store *proxystore.Store
+
+	mu       sync.Mutex
+	lastID   uint64
+	watchers map[uint64]*WatcherInfo
+}
+
+// add registers a new watcher, and returns its ID.
+func (ws *Watchers) add(remote string) uint64 {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.watchers == nil {
+		ws.watchers = map[uint64]*WatcherInfo{}
+	}
+
+	ws.lastID++
+	ws.watchers[ws.lastID] = &WatcherInfo{
+		ID:     ws.lastID,
+		Remote: remote,
+		Since:  time.Now(),
+	}
+
+	return ws.lastID
+}
+
+func (ws *Watchers) remove(id uint64) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	delete(ws.watchers, id)
+}
+
+func (ws *Watchers) update(id uint64, update func(w *WatcherInfo)) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if w := ws.watchers[id]; w != nil {
+		update(w)
+	}
+}
+
+// List returns the connected watchers, ordered by node name.
+func (ws *Watchers) List() (watchers []WatcherInfo) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	watchers = make([]WatcherInfo, 0, len(ws.watchers))
+	for _, w := range ws.watchers {
+		watchers = append(watchers, *w)
+	}
+
+	sort.Slice(watchers, func(i, j int) bool {
+		if watchers[i].Node != watchers[j].Node {
+			return watchers[i].Node < watchers[j].Node
+		}
+		return watchers[i].ID < watchers[j].ID
+	})
+	return
+}
+
+// ServeHTTP writes the connected watchers, with their lag behind the store, as JSON.
+func (ws *Watchers) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	type watcherLag struct {
+		WatcherInfo
+		Lag uint64 `json:"lag"`
+	}
+
+	rev := ws.Store.Rev()
+
+	watchers := make([]watcherLag, 0)
+	for _, wi := range ws.List() {
+		wl := watcherLag{WatcherInfo: wi}
+		if wi.Rev < rev {
+			wl.Lag = rev - wi.Rev
+		}
+		watchers = append(watchers, wl)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(struct {
+		Rev      uint64       `json:"rev"`
+		Watchers []watcherLag `json:"watchers"`
+	}{rev, watchers})
+}
@@ -25,7 +25,9 @@ import (
 
 	"sigs.k8s.io/kpng/api/localv1"
 	"sigs.k8s.io/kpng/client/lightdiffstore"
+	"sigs.k8s.io/kpng/client/statedigest"
 	"sigs.k8s.io/kpng/server/pkg/metrics"
+	"sigs.k8s.io/kpng/server/serde"
 )
 
 // WatchState represents the data in a watch
@@ -57,6 +59,25 @@ func New(res localv1.OpSink, sets []localv1.Set) *WatchState {
 	}
 }
 
+// SetSink changes the sink of this watch (ie: when a client resumes a watch on a new connection).
+func (w *WatchState) SetSink(sink localv1.OpSink) {
+	w.sink = sink
+}
+
+// Digest returns the digest of the values held by this watch, as computed by
+// a client holding them (see statedigest).
+func (w *WatchState) Digest() uint64 {
+	d := statedigest.New()
+
+	for i, set := range w.sets {
+		for _, kv := range w.diffs[i].All() {
+			d.Set(set, string(kv.Key), serde.Marshal(kv.Value.(proto.Message)))
+		}
+	}
+
+	return d.Sum()
+}
+
 // StoreFor is syntactic sugar for StoreForN(.., 0) to get the first
 // instance of a given type stored in this database.
 func (w *WatchState) StoreFor(set localv1.Set) *lightdiffstore.DiffStore {
@@ -135,10 +156,8 @@ func (w *WatchState) send(item *localv1.OpItem) {
 }
 
 func (w *WatchState) sendSet(set localv1.Set, path string, m proto.Message) {
-	message, err := proto.Marshal(m)
-	if err != nil {
-		panic("protobuf Marshal failed: " + err.Error())
-	}
+	// deterministic marshalling so clients get the same bytes for the same value (see Digest)
+	message := serde.Marshal(m)
 
 	w.send(&localv1.OpItem{
 		Op: &localv1.OpItem_Set{
@@ -164,10 +183,9 @@ func (w *WatchState) Reset(state lightdiffstore.ItemState) {
 	}
 }
 
-var syncItem = &localv1.OpItem{Op: &localv1.OpItem_Sync{}}
-
-func (w *WatchState) SendSync() {
-	w.send(syncItem)
+// SendSync signals the change set for the given store revision is fully sent.
+func (w *WatchState) SendSync(rev uint64) {
+	w.send(&localv1.OpItem{Op: &localv1.OpItem_Sync{Sync: &localv1.SyncOp{Rev: rev}}})
 }
 
 var resetItem = &localv1.OpItem{Op: &localv1.OpItem_Reset_{}}
@@ -19,9 +19,12 @@ package proxystore
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/btree"
+	"google.golang.org/protobuf/proto"
 	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/kpng/api/globalv1"
@@ -30,15 +33,45 @@ import (
 	"sigs.k8s.io/kpng/server/serde"
 )
 
+// Store holds the global state. Updates are serialized and publish a new immutable state at each
+// revision, so views never block updates (nor other views).
 type Store struct {
-	sync.RWMutex
+	sync.Mutex // serializes updates
+
 	c      *sync.Cond
-	rev    uint64
 	closed bool
-	tree   *btree.BTree
+	state  *state // last published state, protected by c.L
+
+	// provisional entries and sync info, loaded from a snapshot and not confirmed yet (see LoadSnapshot)
+	provisional     *btree.BTree
+	provisionalSync map[Set]bool
+}
+
+// state is the content of the store at a given revision. It is never modified once published:
+// updates work on a copy-on-write clone of the tree.
+type state struct {
+	rev  uint64
+	tree *btree.BTree
 
 	// set sync info
 	sync map[Set]bool
+
+	// some sets are still provisional (see LoadSnapshot)
+	provisional bool
+}
+
+func (st *state) clone() *state {
+	synced := make(map[Set]bool, len(st.sync))
+	for set, isSync := range st.sync {
+		synced[set] = isSync
+	}
+
+	return &state{
+		rev:         st.rev,
+		tree:        st.tree.Clone(),
+		sync:        synced,
+		provisional: st.provisional,
+	}
 }
 
 type Set = localv1.Set
@@ -57,9 +90,11 @@ type Hashed interface {
 
 func New() *Store {
 	return &Store{
-		c:    sync.NewCond(&sync.Mutex{}),
-		tree: btree.New(2),
-		sync: map[Set]bool{},
+		c: sync.NewCond(&sync.Mutex{}),
+		state: &state{
+			tree: btree.New(2),
+			sync: map[Set]bool{},
+		},
 	}
 }
 
@@ -76,23 +111,24 @@ func (s *Store) Update(update func(tx *Tx)) {
 
 	metrics.Kpng_k8s_api_events.Inc()
 
-	tx := &Tx{s: s}
+	s.c.L.Lock()
+	current := s.state
+	s.c.L.Unlock()
+
+	tx := &Tx{s: s, state: current.clone()}
 	update(tx)
 
 	if tx.changes == 0 {
 		return // nothing changed
 	}
 
-	// TODO check if the update really updated something
-	s.c.L.Lock()
-	s.rev++
-	s.c.Broadcast()
-	s.c.L.Unlock()
+	tx.rev++
+	s.publish(tx.state)
 
 	if log := klog.V(3); log.Enabled() {
-		log.Info("store updated to rev ", s.rev, " with ", s.tree.Len(), " entries")
+		log.Info("store updated to rev ", tx.rev, " with ", tx.tree.Len(), " entries")
 		if log := klog.V(4); log.Enabled() {
-			s.tree.Ascend(func(i btree.Item) bool {
+			tx.tree.Ascend(func(i btree.Item) bool {
 				kv := i.(*KV)
 				log.Info("- entry: ", kv.Sync, "/", kv.Set, ": ", kv.Namespace, "/", kv.Name, "/", kv.Source, "/", kv.Key)
 				return true
@@ -101,31 +137,81 @@ func (s *Store) Update(update func(tx *Tx)) {
 	}
 }
 
+// publish makes st the current state and wakes up the views waiting for it.
+func (s *Store) publish(st *state) {
+	s.c.L.Lock()
+	s.state = st
+	s.c.Broadcast()
+	s.c.L.Unlock()
+
+	metrics.Kpng_store_revision.Set(float64(st.rev))
+}
+
+// Rev returns the current revision of the store.
+func (s *Store) Rev() uint64 {
+	s.c.L.Lock()
+	defer s.c.L.Unlock()
+
+	return s.state.rev
+}
+
+// View calls view with the first state after afterRev, waiting for it if needed. The view sees
+// a point-in-time state of the store, and doesn't prevent updates while it runs.
 func (s *Store) View(afterRev uint64, view func(tx *Tx)) (rev uint64, closed bool) {
 	s.c.L.Lock()
-	for s.rev <= afterRev && !s.closed {
+	for s.state.rev <= afterRev && !s.closed {
 		s.c.Wait()
 	}
+	current, closed := s.state, s.closed
 	s.c.L.Unlock()
 
-	s.RLock()
-	defer s.RUnlock()
+	if closed {
+		return 0, closed
+	}
+
+	view(&Tx{s: s, state: current, ro: true})
 
-	if s.closed {
-		return 0, s.closed
+	return current.rev, false
+}
+
+// WaitRev waits for a revision after afterRev, for at most timeout (forever if not positive), and
+// returns the current revision.
+func (s *Store) WaitRev(afterRev uint64, timeout time.Duration) (rev uint64, closed bool) {
+	timedOut := false
+
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() {
+			s.c.L.Lock()
+			timedOut = true
+			s.c.Broadcast()
+			s.c.L.Unlock()
+		})
+		defer timer.Stop()
 	}
 
-	view(&Tx{s: s, ro: true})
+	s.c.L.Lock()
+	defer s.c.L.Unlock()
 
-	return s.rev, s.closed
+	for s.state.rev <= afterRev && !s.closed && !timedOut {
+		s.c.Wait()
+	}
+
+	return s.state.rev, s.closed
 }
 
 type Tx struct {
+	*state
+
 	s       *Store
 	ro      bool
 	changes uint
 }
 
+// Rev returns the revision of the state seen by the transaction.
+func (tx *Tx) Rev() uint64 {
+	return tx.rev
+}
+
 func (tx *Tx) roPanic() {
 	if tx.ro {
 		panic("read-only!")
@@ -134,7 +220,7 @@ func (tx *Tx) roPanic() {
 
 // Each iterate over each item in the given set, stopping if the callback returns false
 func (tx *Tx) Each(set Set, callback func(*KV) bool) {
-	tx.s.tree.AscendGreaterOrEqual(&KV{Set: set}, func(i btree.Item) bool {
+	tx.tree.AscendGreaterOrEqual(&KV{Set: set}, func(i btree.Item) bool {
 		kv := i.(*KV)
 
 		if kv.Set != set {
@@ -149,14 +235,18 @@ func (tx *Tx) Each(set Set, callback func(*KV) bool) {
 func (tx *Tx) Reset() {
 	tx.roPanic()
 
-	if tx.s.tree.Len() != 0 {
-		tx.s.tree.Clear(false)
+	if tx.tree.Len() != 0 {
+		tx.tree = btree.New(2)
 		tx.changes++
 	}
 
-	for set, isSync := range tx.s.sync {
+	tx.s.provisional = nil
+	tx.s.provisionalSync = nil
+	tx.provisional = false
+
+	for set, isSync := range tx.sync {
 		if isSync {
-			tx.s.sync[set] = false
+			tx.sync[set] = false
 			tx.changes++
 		}
 	}
@@ -164,19 +254,23 @@ func (tx *Tx) Reset() {
 
 func (tx *Tx) set(kv *KV) {
 	tx.roPanic()
-	prev := tx.s.tree.Get(kv)
+	tx.confirm(kv)
+
+	prev := tx.tree.Get(kv)
 
 	if prev != nil && prev.(*KV).Value.GetHash() == kv.Value.GetHash() {
 		return // not changed
 	}
 
-	tx.s.tree.ReplaceOrInsert(kv)
+	tx.tree.ReplaceOrInsert(kv)
 	tx.changes++
 }
 
 func (tx *Tx) del(kv *KV) {
 	tx.roPanic()
-	i := tx.s.tree.Delete(kv)
+	tx.confirm(kv)
+
+	i := tx.tree.Delete(kv)
 	if i != nil {
 		tx.changes++
 	}
@@ -225,13 +319,17 @@ func (tx *Tx) AllSynced() bool {
 	return true
 }
 func (tx *Tx) IsSynced(set Set) bool {
-	return tx.s.sync[set]
+	return tx.sync[set]
 }
 func (tx *Tx) SetSync(set Set) {
 	tx.roPanic()
 
-	if !tx.s.sync[set] {
-		tx.s.sync[set] = true
+	if tx.s.provisionalSync[set] {
+		tx.reconcile(set)
+	}
+
+	if !tx.sync[set] {
+		tx.sync[set] = true
 		tx.changes++
 	}
 }
@@ -239,12 +337,27 @@ func (tx *Tx) SetSync(set Set) {
 // Services funcs
 
 func (tx *Tx) SetService(s *localv1.Service) {
-	si := &globalv1.ServiceInfo{
-		Service: s,
-		Hash: serde.Hash(&globalv1.ServiceInfo{
-			Service: s,
-		}),
+	tx.SetServiceInfo(&globalv1.ServiceInfo{Service: s})
+}
+
+// SetServiceInfo sets a service with its global information. The hash is computed.
+func (tx *Tx) SetServiceInfo(si *globalv1.ServiceInfo) {
+	s := si.Service
+
+	// the trigger time of the endpoints' last change is not hashed (see SetServiceTriggerTime)
+	triggerTime := s.LastChangeTriggerTime
+	s.LastChangeTriggerTime = 0
+
+	si.Hash = serde.Hash(&globalv1.ServiceInfo{
+		Service:    s,
+		ExportedBy: si.ExportedBy,
+		LocalIPs:   si.LocalIPs,
+	})
+
+	if prev := tx.tree.Get(&KV{Set: Services, Namespace: s.Namespace, Name: s.Name}); prev != nil && triggerTime == 0 {
+		triggerTime = prev.(*KV).Service.Service.LastChangeTriggerTime
 	}
+	s.LastChangeTriggerTime = triggerTime
 
 	tx.set(&KV{
 		Set:       Services,
@@ -255,6 +368,30 @@ func (tx *Tx) SetService(s *localv1.Service) {
 	})
 }
 
+// SetServiceTriggerTime records when the last change of the service's endpoints was triggered
+// (see localv1.Service.LastChangeTriggerTime), if later than the recorded one. It doesn't change
+// the service's hash.
+func (tx *Tx) SetServiceTriggerTime(namespace, name string, triggerTime int64) {
+	tx.roPanic()
+
+	i := tx.tree.Get(&KV{Set: Services, Namespace: namespace, Name: name})
+	if i == nil {
+		return
+	}
+
+	kv := *i.(*KV)
+	if triggerTime <= kv.Service.Service.LastChangeTriggerTime {
+		return
+	}
+
+	si := proto.Clone(kv.Service).(*globalv1.ServiceInfo)
+	si.Service.LastChangeTriggerTime = triggerTime
+	kv.Service, kv.Value = si, si
+
+	tx.tree.ReplaceOrInsert(&kv)
+	tx.changes++
+}
+
 func (tx *Tx) DelService(namespace, name string) {
 	tx.del(&KV{
 		Set:       Services,
@@ -266,7 +403,7 @@ func (tx *Tx) DelService(namespace, name string) {
 // Endpoints funcs
 
 func (tx *Tx) EachEndpointOfService(namespace, serviceName string, callback func(*globalv1.EndpointInfo)) {
-	tx.s.tree.AscendGreaterOrEqual(&KV{
+	tx.tree.AscendGreaterOrEqual(&KV{
 		Set:       Endpoints,
 		Namespace: namespace,
 		Name:      serviceName,
@@ -308,7 +445,7 @@ func (tx *Tx) SetEndpointsOfSource(namespace, sourceName string, eis []*globalv1
 	// to delete unseen endpoints
 	toDel := make([]*KV, 0)
 
-	tx.s.tree.AscendGreaterOrEqual(&KV{
+	tx.tree.AscendGreaterOrEqual(&KV{
 		Set:       Endpoints,
 		Namespace: namespace,
 		Source:    sourceName,
@@ -352,21 +489,23 @@ func (tx *Tx) SetEndpointsOfSource(namespace, sourceName string, eis []*globalv1
 			Endpoint:  ei,
 		}
 
-		if tx.s.tree.Has(kv) {
-			continue
-		}
-
-		tx.set(kv)
-
-		// also index by source only
-		tx.set(&KV{
+		sourceKV := &KV{ // also index by source only
 			Set:       Endpoints,
 			Namespace: ei.Namespace,
 			Source:    ei.SourceName,
 			Key:       key,
 			Value:     ei,
 			Endpoint:  ei,
-		})
+		}
+
+		if tx.tree.Has(kv) {
+			tx.confirm(kv)
+			tx.confirm(sourceKV)
+			continue
+		}
+
+		tx.set(kv)
+		tx.set(sourceKV)
 	}
 }
 
@@ -375,7 +514,7 @@ func (tx *Tx) DelEndpointsOfSource(namespace, sourceName string) {
 
 	toDel := make([]*KV, 0)
 
-	tx.s.tree.AscendGreaterOrEqual(&KV{
+	tx.tree.AscendGreaterOrEqual(&KV{
 		Set:       Endpoints,
 		Namespace: namespace,
 		Source:    sourceName,
@@ -462,8 +601,26 @@ func (tx *Tx) SetEndpoint(ei *globalv1.EndpointInfo) {
 
 // Nodes funcs
 
+// NodeName returns the name of a node in a store holding several clusters: its name, qualified by
+// its cluster. With a single cluster, nodes keep their bare name.
+func NodeName(cluster, name string) string {
+	if cluster == "" {
+		return name
+	}
+	return cluster + "/" + name
+}
+
+// SplitNodeName returns the cluster (if qualified) and name of a node from its name in the store.
+func SplitNodeName(nodeName string) (cluster, name string) {
+	if i := strings.LastIndexByte(nodeName, '/'); i >= 0 {
+		return nodeName[:i], nodeName[i+1:]
+	}
+	return "", nodeName
+}
+
+// GetNode returns the node by its name in the store (see NodeName).
 func (tx *Tx) GetNode(name string) *globalv1.Node {
-	i := tx.s.tree.Get(&KV{Set: Nodes, Name: name})
+	i := tx.tree.Get(&KV{Set: Nodes, Name: name})
 
 	if i == nil {
 		return nil
@@ -472,6 +629,17 @@ func (tx *Tx) GetNode(name string) *globalv1.Node {
 	return i.(*KV).Node.Node
 }
 
+// GetNodeOf returns the node of the topology, named with or without its cluster.
+func (tx *Tx) GetNodeOf(topology *globalv1.TopologyInfo) *globalv1.Node {
+	if cluster := topology.GetCluster(); cluster != "" {
+		if node := tx.GetNode(NodeName(cluster, topology.Node)); node != nil {
+			return node
+		}
+	}
+	return tx.GetNode(topology.GetNode())
+}
+
+// SetNode sets the node, by its name in the store (see NodeName).
 func (tx *Tx) SetNode(n *globalv1.Node) {
 	ni := &globalv1.NodeInfo{
 		Node: n,
@@ -486,6 +654,7 @@ func (tx *Tx) SetNode(n *globalv1.Node) {
 	})
 }
 
+// DelNode deletes the node by its name in the store (see NodeName).
 func (tx *Tx) DelNode(name string) {
 	tx.del(&KV{
 		Set:  Nodes,
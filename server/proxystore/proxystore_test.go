@@ -19,6 +19,7 @@ package proxystore
 import (
 	"fmt"
 	"sort"
+	"sync"
 	"testing"
 
 	"sigs.k8s.io/kpng/api/globalv1"
@@ -121,3 +122,120 @@ func TestSessionAffinitySetClientIP(t *testing.T) {
 		})
 	})
 }
+
+func TestServiceTriggerTime(t *testing.T) {
+	store := New()
+
+	setService := func(port int32) {
+		store.Update(func(tx *Tx) {
+			tx.SetService(&localv1.Service{
+				Namespace: "default",
+				Name:      "svc",
+				Type:      "ClusterIP",
+				Ports:     []*localv1.PortMapping{{Protocol: localv1.Protocol_TCP, Port: port}},
+			})
+		})
+	}
+
+	get := func() (si *globalv1.ServiceInfo) {
+		store.View(0, func(tx *Tx) {
+			tx.Each(Services, func(kv *KV) bool {
+				si = kv.Service
+				return false
+			})
+		})
+		return
+	}
+
+	setService(80)
+	hash := get().Hash
+
+	for _, triggerTime := range []int64{42, 41} { // earlier times are ignored
+		store.Update(func(tx *Tx) {
+			tx.SetServiceTriggerTime("default", "svc", triggerTime)
+		})
+
+		if si := get(); si.Service.LastChangeTriggerTime != 42 || si.Hash != hash {
+			t.Errorf("%d: expected trigger time 42 with hash %x, got %d with hash %x", triggerTime, hash, si.Service.LastChangeTriggerTime, si.Hash)
+		}
+	}
+
+	// kept across the service's changes
+	setService(81)
+
+	if si := get(); si.Service.LastChangeTriggerTime != 42 || si.Hash == hash {
+		t.Errorf("expected the changed service with trigger time 42, got %v", si)
+	}
+}
+
+// BenchmarkUpdateWithWatchers measures the update latency while watchers are computing their view.
+func BenchmarkUpdateWithWatchers(b *testing.B) {
+	const services = 1000
+
+	setService := func(tx *Tx, i, port int) {
+		name := fmt.Sprint("svc-", i)
+
+		tx.SetService(&localv1.Service{
+			Namespace: "default",
+			Name:      name,
+			Type:      "ClusterIP",
+			IPs:       &localv1.ServiceIPs{ClusterIPs: localv1.NewIPSet(fmt.Sprintf("10.1.%d.%d", i/256, i%256))},
+			Ports:     []*localv1.PortMapping{{Protocol: localv1.Protocol_TCP, Port: int32(port), TargetPort: 8080}},
+		})
+
+		tx.SetEndpointsOfSource("default", name+"-abcde", []*globalv1.EndpointInfo{
+			{
+				Namespace:   "default",
+				SourceName:  name + "-abcde",
+				ServiceName: name,
+				Endpoint:    &localv1.Endpoint{IPs: localv1.NewIPSet(fmt.Sprintf("10.2.%d.%d", i/256, i%256))},
+				Conditions:  &globalv1.EndpointConditions{Ready: true},
+			},
+		})
+	}
+
+	for _, watchers := range []int{0, 10, 100} {
+		b.Run(fmt.Sprint("watchers=", watchers), func(b *testing.B) {
+			s := New()
+			s.Update(func(tx *Tx) {
+				for i := 0; i < services; i++ {
+					setService(tx, i, 80)
+				}
+			})
+
+			wg := sync.WaitGroup{}
+			for w := 0; w < watchers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					var rev uint64
+					closed := false
+
+					for !closed {
+						rev, closed = s.View(rev, func(tx *Tx) {
+							tx.Each(Services, func(kv *KV) bool {
+								svc := kv.Service.Service
+								tx.EachEndpointOfService(svc.Namespace, svc.Name, func(*globalv1.EndpointInfo) {})
+								return true
+							})
+						})
+					}
+				}()
+			}
+
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				s.Update(func(tx *Tx) {
+					setService(tx, i%services, 1+i%65535)
+				})
+			}
+
+			b.StopTimer()
+
+			s.Close()
+			wg.Wait()
+		})
+	}
+}
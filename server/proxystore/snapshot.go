@@ -0,0 +1,232 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxystore
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/google/btree"
+	"google.golang.org/protobuf/proto"
+	"k8s.io/klog/v2"
+
+	"sigs.k8s.io/kpng/api/globalv1"
+	"sigs.k8s.io/kpng/api/localv1"
+	"sigs.k8s.io/kpng/server/serde"
+)
+
+// Snapshots are the store revision (uvarint), followed by each entry as a length-prefixed
+// (uvarint) localv1.Value, referencing the entry's set and path.
+
+// WriteSnapshot writes the store's content to w.
+func (tx *Tx) WriteSnapshot(w io.Writer) (err error) {
+	rev := tx.rev
+
+	out := bufio.NewWriter(w)
+	buf := make([]byte, 0, binary.MaxVarintLen64)
+
+	if _, err = out.Write(binary.AppendUvarint(buf, rev)); err != nil {
+		return
+	}
+
+	tx.tree.Ascend(func(i btree.Item) bool {
+		kv := i.(*KV)
+
+		ba := serde.Marshal(&localv1.Value{
+			Ref:   &localv1.Ref{Set: kv.Set, Path: kv.Path()},
+			Bytes: serde.Marshal(kv.Value.(proto.Message)),
+		})
+
+		if _, err = out.Write(binary.AppendUvarint(buf, uint64(len(ba)))); err != nil {
+			return false
+		}
+		if _, err = out.Write(ba); err != nil {
+			return false
+		}
+		return true
+	})
+
+	if err != nil {
+		return
+	}
+
+	err = out.Flush()
+	return
+}
+
+// LoadSnapshot loads a snapshot written by WriteSnapshot in the store, as a provisional state:
+// every set is considered synced, and the loaded entries are kept until their set is synced by
+// a producer. Then, the entries that were not set again are deleted, so watchers see only the
+// real differences between the snapshot and the producer's state.
+func (s *Store) LoadSnapshot(r io.Reader) (rev uint64, err error) {
+	in := bufio.NewReader(r)
+
+	rev, err = binary.ReadUvarint(in)
+	if err != nil {
+		err = fmt.Errorf("failed to read the snapshot revision: %w", err)
+		return
+	}
+
+	kvs := make([]*KV, 0)
+
+	for {
+		var size uint64
+		size, err = binary.ReadUvarint(in)
+		if err == io.EOF {
+			err = nil
+			break
+		} else if err != nil {
+			return
+		}
+
+		ba := make([]byte, size)
+		if _, err = io.ReadFull(in, ba); err != nil {
+			return
+		}
+
+		var kv *KV
+		kv, err = snapshotKV(ba)
+		if err != nil {
+			return
+		}
+
+		kvs = append(kvs, kv)
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	st := &state{
+		tree:        btree.New(2),
+		sync:        map[Set]bool{},
+		provisional: true,
+	}
+
+	s.provisional = btree.New(2)
+	s.provisionalSync = map[Set]bool{}
+
+	for _, kv := range kvs {
+		st.tree.ReplaceOrInsert(kv)
+		s.provisional.ReplaceOrInsert(kv)
+	}
+
+	for _, set := range AllSets {
+		st.sync[set] = true
+		s.provisionalSync[set] = true
+	}
+
+	s.c.L.Lock()
+	st.rev = s.state.rev + 1
+	s.c.L.Unlock()
+
+	if rev > st.rev {
+		st.rev = rev
+	}
+
+	s.publish(st)
+
+	klog.Infof("loaded %d entries from snapshot at rev %d", len(kvs), rev)
+
+	return
+}
+
+// MarkProvisional marks the current entries of the store as provisional, as if they were loaded
+// from a snapshot. It's used when another producer takes over the store (ie: a new leader or a
+// reconnection): the entries it doesn't set again before syncing their set are deleted.
+func (s *Store) MarkProvisional() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.c.L.Lock()
+	current := s.state
+	s.c.L.Unlock()
+
+	s.provisional = current.tree.Clone()
+	s.provisionalSync = map[Set]bool{}
+
+	for _, set := range AllSets {
+		s.provisionalSync[set] = true
+	}
+}
+
+func snapshotKV(ba []byte) (kv *KV, err error) {
+	v := &localv1.Value{}
+	if err = proto.Unmarshal(ba, v); err != nil {
+		return
+	}
+
+	kv = &KV{Set: v.GetRef().GetSet()}
+	kv.SetPath(v.GetRef().GetPath())
+
+	switch kv.Set {
+	case Services:
+		kv.Service = &globalv1.ServiceInfo{}
+		kv.Value = kv.Service
+	case Endpoints:
+		kv.Endpoint = &globalv1.EndpointInfo{}
+		kv.Value = kv.Endpoint
+	case Nodes:
+		kv.Node = &globalv1.NodeInfo{}
+		kv.Value = kv.Node
+	default:
+		return nil, fmt.Errorf("unknown set in snapshot: %v", kv.Set)
+	}
+
+	err = proto.Unmarshal(v.Bytes, kv.Value.(proto.Message))
+	return
+}
+
+// Provisional returns true if some sets still have their state loaded from a snapshot.
+func (tx *Tx) Provisional() bool {
+	return tx.provisional
+}
+
+// confirm marks the entry as not provisional anymore.
+func (tx *Tx) confirm(kv *KV) {
+	if tx.s.provisional != nil {
+		tx.s.provisional.Delete(kv)
+	}
+}
+
+// reconcile deletes the provisional entries of the set that were not confirmed.
+func (tx *Tx) reconcile(set Set) {
+	delete(tx.s.provisionalSync, set)
+
+	stale := make([]*KV, 0)
+	tx.s.provisional.AscendGreaterOrEqual(&KV{Set: set}, func(i btree.Item) bool {
+		kv := i.(*KV)
+		if kv.Set != set {
+			return false
+		}
+
+		stale = append(stale, kv)
+		return true
+	})
+
+	for _, kv := range stale {
+		tx.del(kv)
+	}
+
+	klog.Infof("reconciled %v with the provisional state: %d stale entries deleted", set, len(stale))
+
+	if len(tx.s.provisionalSync) == 0 {
+		tx.s.provisional = nil
+		tx.provisional = false
+	}
+}
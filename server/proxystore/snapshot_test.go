@@ -0,0 +1,181 @@
+/*
+Copyright 2023 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package proxystore
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"sigs.k8s.io/kpng/api/globalv1"
+	"sigs.k8s.io/kpng/api/localv1"
+)
+
+func TestSnapshot(t *testing.T) {
+	service := func(name string) *localv1.Service {
+		return &localv1.Service{Namespace: "default", Name: name, Type: "ClusterIP"}
+	}
+	endpoint := func(svc, ip string) *globalv1.EndpointInfo {
+		return &globalv1.EndpointInfo{
+			Namespace:   "default",
+			SourceName:  svc + "-abcde",
+			ServiceName: svc,
+			Endpoint:    &localv1.Endpoint{IPs: localv1.NewIPSet(ip)},
+			Conditions:  &globalv1.EndpointConditions{Ready: true},
+		}
+	}
+
+	s1 := New()
+	s1.Update(func(tx *Tx) {
+		tx.SetService(service("svc-a"))
+		tx.SetService(service("svc-b"))
+		tx.SetEndpointsOfSource("default", "svc-a-abcde", []*globalv1.EndpointInfo{endpoint("svc-a", "10.0.0.1")})
+		tx.SetEndpointsOfSource("default", "svc-b-abcde", []*globalv1.EndpointInfo{endpoint("svc-b", "10.0.0.2")})
+		tx.SetNode(&globalv1.Node{Name: "node-a"})
+
+		for _, set := range AllSets {
+			tx.SetSync(set)
+		}
+	})
+
+	buf := new(bytes.Buffer)
+	rev1, _ := s1.View(0, func(tx *Tx) {
+		if err := tx.WriteSnapshot(buf); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	s2 := New()
+	rev, err := s2.LoadSnapshot(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rev != rev1 {
+		t.Errorf("expected rev %d, got %d", rev1, rev)
+	}
+
+	check := func(step string, expectedRev uint64, expectedProvisional bool, expected string) {
+		t.Helper()
+
+		s2.View(0, func(tx *Tx) {
+			if tx.rev != expectedRev {
+				t.Errorf("%s: expected rev %d, got %d", step, expectedRev, tx.rev)
+			}
+			if !tx.AllSynced() {
+				t.Errorf("%s: expected all sets synced", step)
+			}
+			if tx.Provisional() != expectedProvisional {
+				t.Errorf("%s: expected provisional=%v", step, expectedProvisional)
+			}
+
+			content := ""
+			for _, set := range AllSets {
+				tx.Each(set, func(kv *KV) bool {
+					if kv.Name == "" {
+						return true // endpoints indexed by source
+					}
+					content += fmt.Sprint(kv.Set, ":", kv.Name, " ")
+					return true
+				})
+			}
+
+			if content != expected {
+				t.Errorf("%s: expected content %q, got %q", step, expected, content)
+			}
+		})
+	}
+
+	check("loaded", rev1, true,
+		"GlobalServiceInfos:svc-a GlobalServiceInfos:svc-b GlobalEndpointInfos:svc-a GlobalEndpointInfos:svc-b GlobalNodeInfos:node-a ")
+
+	// the producer sets the same values: nothing changes
+	s2.Update(func(tx *Tx) {
+		tx.SetService(service("svc-a"))
+		tx.SetEndpointsOfSource("default", "svc-a-abcde", []*globalv1.EndpointInfo{endpoint("svc-a", "10.0.0.1")})
+		tx.SetNode(&globalv1.Node{Name: "node-a"})
+	})
+
+	check("confirmed", rev1, true,
+		"GlobalServiceInfos:svc-a GlobalServiceInfos:svc-b GlobalEndpointInfos:svc-a GlobalEndpointInfos:svc-b GlobalNodeInfos:node-a ")
+
+	// services are synced: svc-b is gone
+	s2.Update(func(tx *Tx) {
+		tx.SetService(service("svc-c"))
+		tx.SetSync(Services)
+	})
+
+	check("services synced", rev1+1, true,
+		"GlobalServiceInfos:svc-a GlobalServiceInfos:svc-c GlobalEndpointInfos:svc-a GlobalEndpointInfos:svc-b GlobalNodeInfos:node-a ")
+
+	s2.Update(func(tx *Tx) {
+		tx.SetSync(Endpoints)
+		tx.SetSync(Nodes)
+	})
+
+	check("all synced", rev1+2, false,
+		"GlobalServiceInfos:svc-a GlobalServiceInfos:svc-c GlobalEndpointInfos:svc-a GlobalNodeInfos:node-a ")
+}
+
+func TestSnapshotCorrupted(t *testing.T) {
+	s := New()
+
+	if _, err := s.LoadSnapshot(bytes.NewReader([]byte{1, 10, 1})); err == nil {
+		t.Error("expected an error")
+	}
+
+	if s.provisional != nil || s.state.tree.Len() != 0 {
+		t.Error("store changed by an invalid snapshot")
+	}
+}
+
+func TestMarkProvisional(t *testing.T) {
+	s := New()
+	s.Update(func(tx *Tx) {
+		tx.SetService(&localv1.Service{Namespace: "default", Name: "svc-a"})
+		tx.SetService(&localv1.Service{Namespace: "default", Name: "svc-b"})
+		tx.SetSync(Services)
+	})
+
+	rev := s.Rev()
+
+	// a new producer takes over, and doesn't have svc-b
+	s.MarkProvisional()
+
+	s.Update(func(tx *Tx) {
+		tx.SetService(&localv1.Service{Namespace: "default", Name: "svc-a"})
+	})
+
+	if s.Rev() != rev {
+		t.Errorf("expected no change before the sync")
+	}
+
+	s.Update(func(tx *Tx) {
+		tx.SetSync(Services)
+	})
+
+	names := ""
+	s.View(0, func(tx *Tx) {
+		tx.Each(Services, func(kv *KV) bool {
+			names += kv.Name + " "
+			return true
+		})
+	})
+
+	if names != "svc-a " {
+		t.Errorf("expected only svc-a, got %q", names)
+	}
+}